@@ -0,0 +1,17 @@
+create table public.lanchonete_pickup_counters
+(
+    store_id    varchar(40) not null,
+    day         date        not null,
+    last_number int         not null,
+
+    constraint lanchonete_pickup_counters_pk
+        PRIMARY KEY (store_id, day)
+);
+
+alter table public.lanchonete_orders
+    add column store_id      varchar(40),
+    add column pickup_number int,
+    add column pickup_date   date;
+
+create unique index lanchonete_orders_pickup_number_index
+    on public.lanchonete_orders using BTREE (store_id, pickup_date, pickup_number);
@@ -0,0 +1,5 @@
+alter table public.lanchonete_orders
+    add column customer_name varchar(100);
+
+create index lanchonete_orders_panel_index
+    on public.lanchonete_orders using BTREE (store_id, pickup_date, status);
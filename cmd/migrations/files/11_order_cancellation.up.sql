@@ -0,0 +1,6 @@
+alter table public.lanchonete_orders
+    add column cancel_reason varchar(40),
+    add column canceled_at   timestamptz;
+
+create index lanchonete_orders_status_created_at_index
+    on public.lanchonete_orders using BTREE (status, created_at);
@@ -0,0 +1,31 @@
+alter table public.lanchonete_payments
+    alter column status type varchar(30);
+
+create table public.lanchonete_refunds
+(
+    id         uuid           not null,
+    created_at timestamptz    not null,
+    updated_at timestamptz,
+    payment_id uuid           not null,
+    order_id   uuid           not null,
+    amount     numeric(10, 2) not null,
+    items      jsonb          not null default '[]',
+    reason     varchar(100),
+    status     varchar(20)    not null,
+
+    constraint lanchonete_refunds_pk
+        PRIMARY KEY (id)
+);
+
+alter table public.lanchonete_refunds
+    add constraint fk_refund_payment_id
+        foreign key (payment_id)
+            references public.lanchonete_payments (id);
+
+alter table public.lanchonete_refunds
+    add constraint fk_refund_order_id
+        foreign key (order_id)
+            references public.lanchonete_orders (id);
+
+create index lanchonete_refunds_order_id_idx
+    on public.lanchonete_refunds (order_id, created_at);
@@ -0,0 +1,33 @@
+alter table public.lanchonete_payments
+    drop constraint lanchonete_payments_order_id_key;
+
+alter table public.lanchonete_payments
+    add column attempt int default 1 not null;
+
+create unique index lanchonete_payments_order_attempt_index
+    on public.lanchonete_payments (order_id, attempt);
+
+create table public.lanchonete_payment_events
+(
+    id          bigserial   not null,
+    payment_id  uuid        not null,
+    order_id    uuid        not null,
+    kind        varchar(20) not null,
+    status      varchar(30) not null,
+    occurred_at timestamptz not null,
+
+    constraint lanchonete_payment_events_pk
+        PRIMARY KEY (id)
+);
+
+alter table public.lanchonete_payment_events
+    add constraint fk_payment_event_payment_id
+        foreign key (payment_id)
+            references public.lanchonete_payments (id);
+
+create index lanchonete_payment_events_order_id_idx
+    on public.lanchonete_payment_events (order_id, occurred_at);
+
+insert into public.lanchonete_payment_events (payment_id, order_id, kind, status, occurred_at)
+select id, order_id, 'created', status, created_at
+from public.lanchonete_payments;
@@ -0,0 +1,2 @@
+create index lanchonete_payments_status_created_at_index
+    on public.lanchonete_payments using BTREE (status, created_at);
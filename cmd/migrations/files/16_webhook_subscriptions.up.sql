@@ -0,0 +1,38 @@
+create table public.lanchonete_webhook_subscriptions
+(
+    id         uuid         not null,
+    created_at timestamptz  not null,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    url        text         not null,
+    secret     varchar(128) not null,
+    statuses   jsonb        not null default '[]',
+
+    constraint lanchonete_webhook_subscriptions_pk
+        PRIMARY KEY (id)
+);
+
+create table public.lanchonete_webhook_deliveries
+(
+    id              bigserial   not null,
+    subscription_id uuid        not null,
+    event_id        bigint      not null,
+    order_id        uuid        not null,
+    order_status    varchar(40) not null,
+    attempt         int         not null,
+    status_code     int,
+    error           text,
+    succeeded       boolean     not null,
+    attempted_at    timestamptz not null,
+
+    constraint lanchonete_webhook_deliveries_pk
+        PRIMARY KEY (id)
+);
+
+alter table public.lanchonete_webhook_deliveries
+    add constraint fk_webhook_delivery_subscription_id
+        foreign key (subscription_id)
+            references public.lanchonete_webhook_subscriptions (id);
+
+create index lanchonete_webhook_deliveries_subscription_idx
+    on public.lanchonete_webhook_deliveries (subscription_id, attempted_at desc);
@@ -0,0 +1,2 @@
+alter table public.lanchonete_orders
+    add column adjustments jsonb not null default '[]';
@@ -0,0 +1,7 @@
+alter table public.lanchonete_payments
+    add column split_id uuid,
+    add column payer    varchar(100);
+
+create index lanchonete_payments_split_id_idx
+    on public.lanchonete_payments (split_id)
+    where split_id is not null;
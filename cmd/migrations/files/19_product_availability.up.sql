@@ -0,0 +1,3 @@
+alter table public.lanchonete_products
+    add column available         boolean not null default true,
+    add column unavailable_until timestamptz;
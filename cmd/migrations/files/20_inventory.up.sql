@@ -0,0 +1,77 @@
+create table public.lanchonete_ingredients
+(
+    id         uuid           not null,
+    created_at timestamptz    not null,
+    updated_at timestamptz,
+    name       varchar(100)   not null,
+    unit       varchar(20)    not null,
+    stock      numeric(12, 3) not null default 0,
+    reserved   numeric(12, 3) not null default 0,
+
+    constraint lanchonete_ingredients_pk
+        PRIMARY KEY (id),
+    constraint lanchonete_ingredients_name_key
+        UNIQUE (name),
+    constraint lanchonete_ingredients_reserved_check
+        CHECK (reserved >= 0)
+);
+
+create table public.lanchonete_recipes
+(
+    product_id    uuid           not null,
+    ingredient_id uuid           not null,
+    quantity      numeric(12, 3) not null,
+
+    constraint lanchonete_recipes_pk
+        PRIMARY KEY (product_id, ingredient_id),
+    constraint fk_recipe_product_id
+        FOREIGN KEY (product_id)
+            REFERENCES public.lanchonete_products (id)
+            ON DELETE CASCADE,
+    constraint fk_recipe_ingredient_id
+        FOREIGN KEY (ingredient_id)
+            REFERENCES public.lanchonete_ingredients (id)
+);
+
+create index lanchonete_recipes_ingredient_id_idx
+    on public.lanchonete_recipes (ingredient_id);
+
+-- one row per ingredient held for an order, moved to committed or released exactly once
+create table public.lanchonete_stock_reservations
+(
+    order_id      uuid           not null,
+    ingredient_id uuid           not null,
+    quantity      numeric(12, 3) not null,
+    status        varchar(20)    not null,
+    created_at    timestamptz    not null,
+    updated_at    timestamptz,
+
+    constraint lanchonete_stock_reservations_pk
+        PRIMARY KEY (order_id, ingredient_id)
+);
+
+create table public.lanchonete_stock_movements
+(
+    id             bigserial      not null,
+    ingredient_id  uuid           not null,
+    order_id       uuid,
+    kind           varchar(20)    not null,
+    quantity       numeric(12, 3) not null,
+    stock_after    numeric(12, 3) not null,
+    reserved_after numeric(12, 3) not null,
+    reason         varchar(200),
+    actor          varchar(100),
+    occurred_at    timestamptz    not null,
+
+    constraint lanchonete_stock_movements_pk
+        PRIMARY KEY (id),
+    constraint fk_stock_movement_ingredient_id
+        FOREIGN KEY (ingredient_id)
+            REFERENCES public.lanchonete_ingredients (id)
+);
+
+create index lanchonete_stock_movements_ingredient_idx
+    on public.lanchonete_stock_movements (ingredient_id, occurred_at);
+
+alter table public.lanchonete_products
+    add column out_of_stock boolean not null default false;
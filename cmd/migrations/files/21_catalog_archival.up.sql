@@ -0,0 +1,17 @@
+-- archived products and categories stay around, orders and reports still resolve them by id
+alter table public.lanchonete_products
+    add column deleted_at timestamptz;
+
+alter table public.lanchonete_categories
+    add column deleted_at timestamptz;
+
+-- an archived category frees its name for a new one
+drop index public.lanchonete_categories_name_index;
+
+create unique index lanchonete_categories_name_index
+    on public.lanchonete_categories using BTREE (name)
+    where deleted_at is null;
+
+create index lanchonete_products_category_active_idx
+    on public.lanchonete_products (category_id)
+    where deleted_at is null;
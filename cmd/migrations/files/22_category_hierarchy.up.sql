@@ -0,0 +1,12 @@
+alter table public.lanchonete_categories
+    add column display_order integer not null default 0,
+    add column parent_id     uuid,
+    add constraint fk_category_parent_id
+        FOREIGN KEY (parent_id)
+            REFERENCES public.lanchonete_categories (id),
+    add constraint lanchonete_categories_parent_check
+        CHECK (parent_id <> id);
+
+create index lanchonete_categories_parent_id_idx
+    on public.lanchonete_categories (parent_id)
+    where deleted_at is null;
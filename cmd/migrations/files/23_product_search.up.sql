@@ -0,0 +1,18 @@
+create extension if not exists unaccent;
+
+-- unaccent is only stable, generated columns and indexes need an immutable wrapper pinned to its dictionary
+create or replace function public.lanchonete_unaccent(text) returns text
+    language sql immutable parallel safe strict
+as
+$$
+select public.unaccent('public.unaccent'::regdictionary, $1)
+$$;
+
+alter table public.lanchonete_products
+    add column search tsvector generated always as (
+        setweight(to_tsvector('portuguese', public.lanchonete_unaccent(name)), 'A') ||
+        setweight(to_tsvector('portuguese', public.lanchonete_unaccent(description)), 'B')
+        ) stored;
+
+create index lanchonete_products_search_idx
+    on public.lanchonete_products using gin (search);
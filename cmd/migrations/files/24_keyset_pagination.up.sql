@@ -0,0 +1,11 @@
+create index lanchonete_orders_created_at_id_idx
+    on public.lanchonete_orders (created_at, id)
+    where deleted_at is null;
+
+create index lanchonete_categories_created_at_id_idx
+    on public.lanchonete_categories (created_at, id)
+    where deleted_at is null;
+
+create index lanchonete_products_category_created_at_id_idx
+    on public.lanchonete_products (category_id, created_at, id)
+    where deleted_at is null;
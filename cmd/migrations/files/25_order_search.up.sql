@@ -0,0 +1,28 @@
+create extension if not exists pg_trgm;
+
+-- containment searches for a product need jsonb, the column was created as json
+alter table public.lanchonete_orders
+    alter column products type jsonb using products::jsonb;
+
+create index lanchonete_orders_products_idx
+    on public.lanchonete_orders using GIN (products jsonb_path_ops)
+    where deleted_at is null;
+
+create index lanchonete_orders_status_created_at_idx
+    on public.lanchonete_orders (status, created_at, id)
+    where deleted_at is null;
+
+create index lanchonete_orders_user_id_idx
+    on public.lanchonete_orders (user_id)
+    where deleted_at is null;
+
+create index lanchonete_orders_payment_id_idx
+    on public.lanchonete_orders (payment_id);
+
+create index lanchonete_orders_customer_name_idx
+    on public.lanchonete_orders using GIN (customer_name gin_trgm_ops)
+    where deleted_at is null;
+
+create index lanchonete_orders_price_idx
+    on public.lanchonete_orders (price)
+    where deleted_at is null;
@@ -0,0 +1,37 @@
+create extension if not exists pgcrypto;
+
+create table public.lanchonete_product_prices
+(
+    id           uuid           not null,
+    created_at   timestamptz    not null,
+    product_id   uuid           not null,
+    price        numeric(10, 2) not null,
+    effective_at timestamptz    not null,
+    status       varchar(20)    not null,
+    applied_at   timestamptz,
+
+    constraint lanchonete_product_prices_pk
+        PRIMARY KEY (id),
+    constraint fk_product_price_product_id
+        FOREIGN KEY (product_id)
+            REFERENCES public.lanchonete_products (id)
+            ON DELETE CASCADE,
+    constraint lanchonete_product_prices_price_check
+        CHECK (price > 0),
+    constraint lanchonete_product_prices_status_check
+        CHECK (status in ('scheduled', 'applied', 'superseded', 'canceled'))
+);
+
+create index lanchonete_product_prices_product_id_idx
+    on public.lanchonete_product_prices (product_id, effective_at);
+
+create index lanchonete_product_prices_due_idx
+    on public.lanchonete_product_prices (effective_at)
+    where status = 'scheduled';
+
+-- the current prices open the history of the existing products, the ones still priced at 0 start it on
+-- their first update
+insert into public.lanchonete_product_prices (id, created_at, product_id, price, effective_at, status, applied_at)
+select gen_random_uuid(), now(), id, price, coalesce(updated_at, created_at), 'applied', now()
+from public.lanchonete_products
+where price > 0;
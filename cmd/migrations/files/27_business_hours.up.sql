@@ -0,0 +1,42 @@
+-- times of day are kept as minutes after midnight in the store timezone, 1440 closes a day
+create table public.lanchonete_business_hours
+(
+    store_id varchar(40) not null,
+    weekday  smallint    not null,
+    opens_at smallint    not null,
+    closes_at smallint   not null,
+
+    constraint lanchonete_business_hours_pk
+        PRIMARY KEY (store_id, weekday, opens_at),
+    constraint lanchonete_business_hours_weekday_check
+        CHECK (weekday between 0 and 6),
+    constraint lanchonete_business_hours_window_check
+        CHECK (opens_at between 0 and 1439 and closes_at between 0 and 1440 and opens_at <> closes_at)
+);
+
+create table public.lanchonete_hours_exceptions
+(
+    store_id  varchar(40)  not null,
+    day       date         not null,
+    closed    boolean      not null,
+    opens_at  smallint,
+    closes_at smallint,
+    reason    varchar(200) not null default '',
+
+    constraint lanchonete_hours_exceptions_pk
+        PRIMARY KEY (store_id, day),
+    constraint lanchonete_hours_exceptions_window_check
+        CHECK (closed or (opens_at between 0 and 1439 and closes_at between 0 and 1440 and opens_at <> closes_at))
+);
+
+alter table public.lanchonete_categories
+    add column available_from smallint,
+    add column available_to   smallint,
+    add constraint lanchonete_categories_window_check
+        CHECK ((available_from is null) = (available_to is null));
+
+alter table public.lanchonete_products
+    add column available_from smallint,
+    add column available_to   smallint,
+    add constraint lanchonete_products_window_check
+        CHECK ((available_from is null) = (available_to is null));
@@ -4,6 +4,9 @@ package main
 import (
 	"os"
 	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // Config is a struct to hold the configuration
@@ -57,3 +60,21 @@ func LoadConfig() (Config, error) {
 
 	return cfg, nil
 }
+
+// durationFromEnv reads an integer amount of unit from key, falling back to def when it is not set or invalid
+func durationFromEnv(key string, unit time.Duration, def int) time.Duration {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		value = def
+	}
+	return time.Duration(value) * unit
+}
+
+// percentageFromEnv reads a percentage from key, anything unset, invalid or outside 0-100 disables it
+func percentageFromEnv(key string) decimal.Decimal {
+	value, err := decimal.NewFromString(os.Getenv(key))
+	if err != nil || value.IsNegative() || value.GreaterThan(decimal.NewFromInt(100)) {
+		return decimal.Zero
+	}
+	return value
+}
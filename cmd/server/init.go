@@ -5,11 +5,15 @@ import (
 	"flag"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
 	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/pix"
 
 	"github.com/SOAT1StackGoLang/msvc-payments/pkg/datastore"
 	logger "github.com/SOAT1StackGoLang/msvc-payments/pkg/middleware"
+	"github.com/shopspring/decimal"
 )
 
 // initializeApp initializes the application by loading the configuration, connecting to the datastore,
@@ -17,10 +21,26 @@ import (
 // It returns a pointer to the RedisStore and an error if any.
 
 var (
-	binding       string
-	connString    string
-	paymentURI    string
-	productionURI string
+	binding        string
+	connString     string
+	paymentURI     string
+	productionURI  string
+	wsDeviceTokens string
+	webhookSecret  string
+	storeID        string
+	storeTimezone  string
+
+	serviceChargePercentage decimal.Decimal
+
+	pixMerchant pix.Merchant
+
+	webhookClaims helpers.KeyClaimer
+
+	menuSnapshotTTL time.Duration
+
+	orderExpiration       service.OrderExpirationConfig
+	paymentReconciliation service.PaymentReconciliationConfig
+	scheduledPrices       service.ScheduledPricesConfig
 )
 
 func initializeApp() (datastore.RedisStore, error) {
@@ -33,6 +53,36 @@ func initializeApp() (datastore.RedisStore, error) {
 	helpers.ReadPgxConnEnvs()
 	paymentURI = os.Getenv("PAYMENT_URI")
 	productionURI = os.Getenv("PRODUCTION_URI")
+	wsDeviceTokens = os.Getenv("WS_DEVICE_TOKENS")
+	webhookSecret = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	storeID = os.Getenv("STORE_ID")
+	if storeID == "" {
+		storeID = "default"
+	}
+	storeTimezone = os.Getenv("STORE_TIMEZONE")
+	if storeTimezone == "" {
+		storeTimezone = "America/Sao_Paulo"
+	}
+	serviceChargePercentage = percentageFromEnv("STORE_SERVICE_CHARGE_PERCENTAGE")
+	pixMerchant = pix.Merchant{
+		Key:  os.Getenv("PIX_KEY"),
+		Name: os.Getenv("PIX_MERCHANT_NAME"),
+		City: os.Getenv("PIX_MERCHANT_CITY"),
+	}
+	menuSnapshotTTL = durationFromEnv("MENU_SNAPSHOT_TTL_SECONDS", time.Second, 60)
+	orderExpiration = service.OrderExpirationConfig{
+		Interval:       durationFromEnv("ORDER_EXPIRATION_INTERVAL_SECONDS", time.Second, 60),
+		OpenTTL:        durationFromEnv("ORDER_OPEN_TTL_MINUTES", time.Minute, 120),
+		PaymentTimeout: durationFromEnv("ORDER_PAYMENT_TIMEOUT_MINUTES", time.Minute, 30),
+	}
+	paymentReconciliation = service.PaymentReconciliationConfig{
+		Interval:  durationFromEnv("PAYMENT_RECONCILIATION_INTERVAL_SECONDS", time.Second, 300),
+		OlderThan: durationFromEnv("PAYMENT_RECONCILIATION_AFTER_MINUTES", time.Minute, 10),
+		Timeout:   durationFromEnv("PAYMENT_RECONCILIATION_TIMEOUT_SECONDS", time.Second, 10),
+	}
+	scheduledPrices = service.ScheduledPricesConfig{
+		Interval: durationFromEnv("PRODUCT_PRICE_SCHEDULE_INTERVAL_SECONDS", time.Second, 60),
+	}
 	connString = helpers.GetConnectionParams()
 
 	logger.InitializeLogger()
@@ -54,6 +104,8 @@ func initializeApp() (datastore.RedisStore, error) {
 		return nil, err
 	}
 
+	webhookClaims = helpers.NewRedisKeyClaimer(configs.KVSURI, configs.KVSDB)
+
 	// Subscribe to the Redis channel if APP_LOG_LEVEL is set to debug
 	if strings.ToLower(os.Getenv("APP_LOG_LEVEL")) == "debug" {
 		err = debugChannelSubscriber(redisStore)
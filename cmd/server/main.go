@@ -2,13 +2,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
+	_ "time/tzdata" // the distroless image ships without a zoneinfo database
 
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/transport"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/transport/routes"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/paymentsapi"
 	logger "github.com/SOAT1StackGoLang/msvc-payments/pkg/middleware"
 	"github.com/gorilla/mux"
 	"gorm.io/driver/postgres"
@@ -29,22 +34,61 @@ func main() {
 		log.Panicf("failed initializing db: %s\n", err)
 	}
 
+	storeLocation, err := time.LoadLocation(storeTimezone)
+	if err != nil {
+		log.Panicf("invalid store timezone %s: %s\n", storeTimezone, err)
+	}
+	store := models.Store{ID: storeID, Location: storeLocation, ServiceChargePercentage: serviceChargePercentage}
+
 	r := mux.NewRouter()
 
+	hoursRepo := persistence.NewHoursPersistence(gormDB, logger.InfoLogger)
+	hoursSvc := service.NewStoreHoursService(hoursRepo, store, menuSnapshotTTL, logger.InfoLogger)
+	r = routes.NewStoreHoursRouter(hoursSvc, r, logger.InfoLogger)
+
+	menuRepo := persistence.NewMenuPersistence(gormDB, logger.InfoLogger)
+	menuSvc := service.NewMenuService(menuRepo, hoursSvc, store, menuSnapshotTTL, logger.InfoLogger)
+	r = routes.NewMenuRouter(menuSvc, r, logger.InfoLogger)
+
 	catRepo := persistence.NewCategoriesPersistence(gormDB, logger.InfoLogger)
-	categoriesSvc := service.NewCategoriesService(catRepo, logger.InfoLogger)
+	categoriesSvc := service.NewCategoriesService(catRepo, menuSvc, logger.InfoLogger)
 	r = routes.NewCategoriesRouter(categoriesSvc, r, logger.InfoLogger)
 
 	productsRepo := persistence.NewProductsPersistence(gormDB, logger.InfoLogger)
-	productsSvc := service.NewProductsService(productsRepo, logger.InfoLogger)
+	productsSvc := service.NewProductsService(productsRepo, menuSvc, logger.InfoLogger)
 	r = routes.NewProductsRouter(productsSvc, r, logger.InfoLogger)
+	go service.RunScheduledPrices(context.Background(), productsSvc, scheduledPrices, logger.InfoLogger)
+
+	inventoryRepo := persistence.NewInventoryPersistence(gormDB, logger.InfoLogger)
+	inventorySvc := service.NewInventoryService(inventoryRepo, menuSvc, logger.InfoLogger)
+	r = routes.NewInventoryRouter(inventorySvc, r, logger.InfoLogger)
 
 	paymentsRepo := persistence.NewPaymentsPersistence(gormDB, logger.InfoLogger)
-	paymentsSvc := service.NewPaymentsService(paymentsRepo, logger.InfoLogger, cache)
+	refundsRepo := persistence.NewRefundsPersistence(gormDB, logger.InfoLogger)
+	paymentsSvc := service.NewPaymentsService(paymentsRepo, refundsRepo, logger.InfoLogger, cache, pixMerchant)
 	r = routes.NewPaymentsRouter(paymentsSvc, r, logger.InfoLogger)
 
+	orderEvents := service.NewOrderEventsHub(logger.InfoLogger)
+
 	ordersRepo := persistence.NewOrdersPersistence(gormDB, logger.InfoLogger)
-	ordersSvc := service.NewOrdersService(ordersRepo, productsSvc, paymentsSvc, logger.InfoLogger, cache)
+	ordersSvc := service.NewOrdersService(ordersRepo, productsSvc, paymentsSvc, inventorySvc, hoursSvc, logger.InfoLogger, cache, orderEvents, store)
+	go service.RunOrderExpiration(context.Background(), ordersSvc, orderExpiration, logger.InfoLogger)
+
+	var reconciler *service.PaymentReconciler
+	if paymentURI != "" {
+		reconciler = service.NewPaymentReconciler(paymentsSvc, ordersSvc, paymentsapi.NewClient(paymentURI, paymentReconciliation.Timeout), logger.InfoLogger)
+	}
+	go service.RunPaymentReconciliation(context.Background(), reconciler, paymentReconciliation, logger.InfoLogger)
+
+	webhooksRepo := persistence.NewWebhooksPersistence(gormDB, logger.InfoLogger)
+	webhooksSvc := service.NewWebhooksService(webhooksRepo, orderEvents, logger.InfoLogger)
+	go webhooksSvc.Run(context.Background())
+	r = routes.NewWebhookSubscriptionsRouter(webhooksSvc, r, logger.InfoLogger)
+
+	r = routes.NewOrderEventsRouter(ordersSvc, orderEvents, r, logger.InfoLogger)
+	r = routes.NewWebsocketRouter(ordersSvc, orderEvents, routes.ParseDeviceTokens(wsDeviceTokens), r, logger.InfoLogger)
+	r = routes.NewPickupPanelRouter(ordersSvc, r, logger.InfoLogger)
+	r = routes.NewPaymentWebhookRouter(ordersSvc, cache, webhookClaims, webhookSecret, r, logger.InfoLogger)
 	r = routes.NewOrdersRouter(ordersSvc, r, logger.InfoLogger)
 
 	transport.NewHTTPServer(":8080", muxToHttp(r))
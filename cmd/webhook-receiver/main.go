@@ -0,0 +1,67 @@
+// webhook-receiver is a local stand-in for a webhook subscriber: it checks the
+// signature of every delivery, logs it and can fail on purpose to exercise retries.
+//
+//	go run ./cmd/webhook-receiver -secret <subscription secret> -fail-rate 0.3
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/messages"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	secret := flag.String("secret", "", "subscription secret, signatures are not checked when empty")
+	failRate := flag.Float64("fail-rate", 0, "fraction of deliveries answered with 503")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if *secret != "" {
+			ts, err := helpers.ParseSignatureTimestamp(r.Header.Get(helpers.TimestampHeader))
+			if err != nil || !helpers.VerifySignature([]byte(*secret), ts, body, r.Header.Get(helpers.SignatureHeader)) {
+				log.Printf("rejected delivery %s: invalid signature", r.Header.Get(messages.WebhookDeliveryIDHeader))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var msg messages.OrderWebhookMessage
+		if err = json.Unmarshal(body, &msg); err != nil {
+			log.Printf("rejected delivery %s: %s", r.Header.Get(messages.WebhookDeliveryIDHeader), err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if rand.Float64() < *failRate {
+			log.Printf("failing delivery %s on purpose", msg.ID)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		log.Printf("%s %s order %s: %s -> %s", msg.ID, msg.Event, msg.OrderID, msg.PreviousStatus, msg.Status)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("listening on %s", *addr)
+	srv := &http.Server{Addr: *addr, ReadHeaderTimeout: 5 * time.Second}
+	log.Fatal(srv.ListenAndServe())
+}
@@ -16,6 +16,9 @@ type (
 		InsertCategoryEndpoint endpoint.Endpoint
 		ListCategoriesEndpoint endpoint.Endpoint
 		DeleteCategoryEndpoint endpoint.Endpoint
+		UpdateCategoryEndpoint endpoint.Endpoint
+		CategoryTreeEndpoint   endpoint.Endpoint
+		SetWindowEndpoint      endpoint.Endpoint
 	}
 )
 
@@ -25,6 +28,9 @@ func MakeCategoryEndpoints(svc service.CategoriesService) CategoryEndpoints {
 		InsertCategoryEndpoint: makeInsertCategoryEndpoint(svc),
 		DeleteCategoryEndpoint: makeDeleteCategoryEndpoint(svc),
 		ListCategoriesEndpoint: makeListCategoriesEndpoint(svc),
+		UpdateCategoryEndpoint: makeUpdateCategoryEndpoint(svc),
+		CategoryTreeEndpoint:   makeGetCategoryTreeEndpoint(svc),
+		SetWindowEndpoint:      makeSetCategoryWindowEndpoint(svc),
 	}
 }
 
@@ -32,26 +38,26 @@ func makeListCategoriesEndpoint(svc service.CategoriesService) endpoint.Endpoint
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(ListCategoriesRequest)
 
-		out := ListCategoriesResponse{
-			Limit:  int(req.Limit),
-			Offset: int(req.Offset),
+		page, err := pageRequest(req.Limit, req.Cursor)
+		if err != nil {
+			return nil, err
 		}
 
-		cats, err := svc.ListCategories(ctx, int(req.Limit), int(req.Offset))
+		cats, err := svc.ListCategories(ctx, page)
 		if err != nil {
 			return nil, err
 		}
 
+		out := ListCategoriesResponse{
+			Categories: make([]CoreCategory, 0, len(cats.Categories)),
+			Limit:      cats.Limit,
+			Total:      cats.Total,
+			NextCursor: cats.Next.Encode(),
+			PrevCursor: cats.Prev.Encode(),
+		}
+
 		for _, c := range cats.Categories {
-			cat := CoreCategory{
-				ID:        c.ID.String(),
-				CreatedAt: c.CreatedAt.String(),
-				Name:      c.Name,
-			}
-			if !c.UpdatedAt.IsZero() {
-				cat.UpdatedAt = c.UpdatedAt.String()
-			}
-			out.Categories = append(out.Categories, cat)
+			out.Categories = append(out.Categories, CoreCategoryFromModel(c))
 		}
 
 		return out, nil
@@ -68,7 +74,7 @@ func makeDeleteCategoryEndpoint(svc service.CategoriesService) endpoint.Endpoint
 			return nil, err
 		}
 
-		err = svc.DeleteCategory(ctx, id)
+		err = svc.DeleteCategory(ctx, id, req.Cascade)
 		if err != nil {
 			return nil, err
 		}
@@ -82,16 +88,19 @@ func makeInsertCategoryEndpoint(svc service.CategoriesService) endpoint.Endpoint
 		var out InsertCategoryResponse
 		req := request.(InsertCategoryRequest)
 
-		in := &models.Category{Name: req.Name}
+		in := &models.Category{Name: req.Name, DisplayOrder: req.DisplayOrder}
+		if req.ParentID != "" {
+			if in.ParentID, err = uuid.Parse(req.ParentID); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
 
 		cat, err := svc.InsertCategory(ctx, in)
 		if err != nil {
 			return nil, helpers.ErrInvalidInput
 		}
 
-		out.ID = cat.ID.String()
-		out.Name = cat.Name
-		out.CreatedAt = cat.CreatedAt.String()
+		out.CoreCategory = CoreCategoryFromModel(cat)
 
 		return out, nil
 	}
@@ -111,14 +120,62 @@ func makeGetCategoryEndpoint(svc service.CategoriesService) endpoint.Endpoint {
 			return nil, err
 		}
 
-		var out GetCategoryResponse
-		out.ID = cat.ID.String()
-		out.CreatedAt = cat.CreatedAt.String()
-		out.Name = cat.Name
+		return GetCategoryResponse{CoreCategory: CoreCategoryFromModel(cat)}, nil
+	}
+}
+
+func makeUpdateCategoryEndpoint(svc service.CategoriesService) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (response any, err error) {
+		req := request.(UpdateCategoryRequest)
 
-		if !cat.UpdatedAt.IsZero() {
-			out.UpdatedAt = cat.UpdatedAt.String()
+		in := &models.Category{Name: req.Name, DisplayOrder: req.DisplayOrder}
+		if in.ID, err = uuid.Parse(req.ID); err != nil {
+			return nil, helpers.ErrInvalidInput
 		}
-		return out, nil
+		if req.ParentID != "" {
+			if in.ParentID, err = uuid.Parse(req.ParentID); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+
+		cat, err := svc.UpdateCategory(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetCategoryResponse{CoreCategory: CoreCategoryFromModel(cat)}, nil
+	}
+}
+
+func makeGetCategoryTreeEndpoint(svc service.CategoriesService) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (response any, err error) {
+		tree, err := svc.GetCategoryTree(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return CategoryTreeResponse{Categories: CategoryTreeResponseFromModel(tree)}, nil
+	}
+}
+
+func makeSetCategoryWindowEndpoint(svc service.CategoriesService) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (response any, err error) {
+		req := request.(SetWindowRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+		window, err := windowFromRequest(req.From, req.To)
+		if err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+
+		cat, err := svc.SetCategoryWindow(ctx, id, window)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetCategoryResponse{CoreCategory: CoreCategoryFromModel(cat)}, nil
 	}
 }
@@ -0,0 +1,114 @@
+package endpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	StoreHoursEndpoints struct {
+		GetStoreHoursEndpoint        endpoint.Endpoint
+		SetBusinessHoursEndpoint     endpoint.Endpoint
+		SetHoursExceptionEndpoint    endpoint.Endpoint
+		DeleteHoursExceptionEndpoint endpoint.Endpoint
+		GetStoreStatusEndpoint       endpoint.Endpoint
+	}
+)
+
+func MakeStoreHoursEndpoints(svc service.StoreHoursService) StoreHoursEndpoints {
+	return StoreHoursEndpoints{
+		GetStoreHoursEndpoint:        makeGetStoreHoursEndpoint(svc),
+		SetBusinessHoursEndpoint:     makeSetBusinessHoursEndpoint(svc),
+		SetHoursExceptionEndpoint:    makeSetHoursExceptionEndpoint(svc),
+		DeleteHoursExceptionEndpoint: makeDeleteHoursExceptionEndpoint(svc),
+		GetStoreStatusEndpoint:       makeGetStoreStatusEndpoint(svc),
+	}
+}
+
+func makeGetStoreHoursEndpoint(svc service.StoreHoursService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		schedule, err := svc.GetSchedule(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return StoreScheduleResponseFromModel(schedule), nil
+	}
+}
+
+func makeSetBusinessHoursEndpoint(svc service.StoreHoursService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SetBusinessHoursRequest)
+
+		hours := make([]models.BusinessHours, 0, len(req.Hours))
+		for _, h := range req.Hours {
+			window, err := windowFromRequest(h.OpensAt, h.ClosesAt)
+			if err != nil || window.IsZero() {
+				return nil, helpers.ErrInvalidInput
+			}
+			hours = append(hours, models.BusinessHours{Weekday: time.Weekday(h.Weekday), TimeWindow: window})
+		}
+
+		schedule, err := svc.SetBusinessHours(ctx, hours)
+		if err != nil {
+			return nil, err
+		}
+		return StoreScheduleResponseFromModel(schedule), nil
+	}
+}
+
+func makeSetHoursExceptionEndpoint(svc service.StoreHoursService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(HoursExceptionEntry)
+
+		in := models.HoursException{Closed: req.Closed, Reason: req.Reason}
+		if in.Day, err = time.Parse(time.DateOnly, req.Day); err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+		if !req.Closed {
+			if in.Window, err = windowFromRequest(req.OpensAt, req.ClosesAt); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+
+		schedule, err := svc.SetHoursException(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return StoreScheduleResponseFromModel(schedule), nil
+	}
+}
+
+func makeDeleteHoursExceptionEndpoint(svc service.StoreHoursService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(DeleteHoursExceptionRequest)
+
+		day, err := time.Parse(time.DateOnly, req.Day)
+		if err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+		if err = svc.DeleteHoursException(ctx, day); err != nil {
+			return nil, err
+		}
+
+		schedule, err := svc.GetSchedule(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return StoreScheduleResponseFromModel(schedule), nil
+	}
+}
+
+func makeGetStoreStatusEndpoint(svc service.StoreHoursService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		status, err := svc.Status(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return StoreStatusResponse{Open: status.Open, At: status.At.String()}, nil
+	}
+}
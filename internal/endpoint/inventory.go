@@ -0,0 +1,188 @@
+package endpoint
+
+import (
+	"context"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	InventoryEndpoints struct {
+		CreateIngredientEndpoint   endpoint.Endpoint
+		ListIngredientsEndpoint    endpoint.Endpoint
+		GetIngredientEndpoint      endpoint.Endpoint
+		AdjustStockEndpoint        endpoint.Endpoint
+		ListStockMovementsEndpoint endpoint.Endpoint
+		SetRecipeEndpoint          endpoint.Endpoint
+		GetRecipeEndpoint          endpoint.Endpoint
+	}
+)
+
+func MakeInventoryEndpoints(svc service.InventoryService) InventoryEndpoints {
+	return InventoryEndpoints{
+		CreateIngredientEndpoint:   makeCreateIngredientEndpoint(svc),
+		ListIngredientsEndpoint:    makeListIngredientsEndpoint(svc),
+		GetIngredientEndpoint:      makeGetIngredientEndpoint(svc),
+		AdjustStockEndpoint:        makeAdjustStockEndpoint(svc),
+		ListStockMovementsEndpoint: makeListStockMovementsEndpoint(svc),
+		SetRecipeEndpoint:          makeSetRecipeEndpoint(svc),
+		GetRecipeEndpoint:          makeGetRecipeEndpoint(svc),
+	}
+}
+
+func makeCreateIngredientEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(CreateIngredientRequest)
+
+		stock := decimal.Zero
+		if req.Stock != "" {
+			if stock, err = decimal.NewFromString(req.Stock); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+
+		ingredient, err := svc.CreateIngredient(ctx, &models.Ingredient{
+			Name:  req.Name,
+			Unit:  req.Unit,
+			Stock: stock,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return IngredientResponseFromModel(ingredient), nil
+	}
+}
+
+func makeListIngredientsEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		ingredients, err := svc.ListIngredients(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		out := IngredientList{Ingredients: make([]IngredientResponse, 0, len(ingredients))}
+		for _, i := range ingredients {
+			out.Ingredients = append(out.Ingredients, IngredientResponseFromModel(i))
+		}
+
+		return out, nil
+	}
+}
+
+func makeGetIngredientEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetIngredientRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ingredient, err := svc.GetIngredient(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return IngredientResponseFromModel(ingredient), nil
+	}
+}
+
+func makeAdjustStockEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(AdjustStockRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		delta, err := decimal.NewFromString(req.Delta)
+		if err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+
+		ingredient, err := svc.AdjustStock(ctx, id, delta, req.Reason, req.Actor)
+		if err != nil {
+			return nil, err
+		}
+
+		return IngredientResponseFromModel(ingredient), nil
+	}
+}
+
+func makeListStockMovementsEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(ListStockMovementsRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		movements, err := svc.ListStockMovements(ctx, id, req.Limit)
+		if err != nil {
+			return nil, err
+		}
+
+		out := StockMovementList{Movements: make([]StockMovementResponse, 0, len(movements))}
+		for _, m := range movements {
+			out.Movements = append(out.Movements, StockMovementResponseFromModel(m))
+		}
+
+		return out, nil
+	}
+}
+
+func makeSetRecipeEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SetRecipeRequest)
+
+		productID, err := uuid.Parse(req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]models.RecipeItem, 0, len(req.Items))
+		for _, i := range req.Items {
+			ingredientID, err := uuid.Parse(i.IngredientID)
+			if err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+			quantity, err := decimal.NewFromString(i.Quantity)
+			if err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+			items = append(items, models.RecipeItem{IngredientID: ingredientID, Quantity: quantity})
+		}
+
+		recipe, err := svc.SetRecipe(ctx, productID, items)
+		if err != nil {
+			return nil, err
+		}
+
+		return RecipeResponseFromModel(productID, recipe), nil
+	}
+}
+
+func makeGetRecipeEndpoint(svc service.InventoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetProductRequest)
+
+		productID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		recipe, err := svc.GetRecipe(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+
+		return RecipeResponseFromModel(productID, recipe), nil
+	}
+}
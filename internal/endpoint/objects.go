@@ -1,17 +1,25 @@
 package endpoint
 
 import (
+	"time"
+
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/helpers"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/google/uuid"
 )
 
 type (
 	// CATEGORIES
 	CoreCategory struct {
-		ID        string `json:"id"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at,omitempty"`
-		Name      string `json:"name"`
+		ID           string `json:"id"`
+		CreatedAt    string `json:"created_at"`
+		UpdatedAt    string `json:"updated_at,omitempty"`
+		Name         string `json:"name"`
+		ParentID     string `json:"parent_id,omitempty" description:"Categoria pai, vazio no primeiro nível"`
+		DisplayOrder int    `json:"display_order" description:"Ordem de exibição entre as categorias irmãs"`
+
+		AvailableFrom string `json:"available_from,omitempty" readOnly:"true" description:"Início do horário de venda dos produtos"`
+		AvailableTo   string `json:"available_to,omitempty" readOnly:"true" description:"Fim do horário de venda dos produtos"`
 	}
 	GetCategoryRequest struct {
 		ID string `json:"id"`
@@ -22,7 +30,27 @@ type (
 	}
 
 	InsertCategoryRequest struct {
-		Name string `json:"name" description:"Nome da categoria de produto"`
+		Name         string `json:"name" description:"Nome da categoria de produto"`
+		ParentID     string `json:"parent_id,omitempty" description:"Categoria pai, vazio no primeiro nível"`
+		DisplayOrder int    `json:"display_order" description:"Ordem de exibição entre as categorias irmãs"`
+	}
+
+	// UpdateCategoryRequest holds the category data
+	//	@Description	Category data, replaces name, parent and display order
+	UpdateCategoryRequest struct {
+		ID           string `json:"-"`
+		Name         string `json:"name" description:"Nome da categoria de produto"`
+		ParentID     string `json:"parent_id,omitempty" description:"Categoria pai, vazio para mover ao primeiro nível"`
+		DisplayOrder int    `json:"display_order" description:"Ordem de exibição entre as categorias irmãs"`
+	}
+
+	CategoryNodeResponse struct {
+		CoreCategory
+		Children []CategoryNodeResponse `json:"children" description:"Subcategorias"`
+	}
+
+	CategoryTreeResponse struct {
+		Categories []CategoryNodeResponse `json:"categories"`
 	}
 
 	InsertCategoryResponse struct {
@@ -30,7 +58,8 @@ type (
 	}
 
 	DeleteCategoryRequest struct {
-		ID string `json:"id"`
+		ID      string `json:"id"`
+		Cascade bool   `json:"cascade"`
 	}
 
 	DeleteCategoryResponse struct {
@@ -38,18 +67,50 @@ type (
 	}
 
 	ListCategoriesRequest struct {
-		Limit  int64 `json:"limit" default:"10" description:"Quantidade de registros"`
-		Offset int64 `json:"offset"`
+		Limit  int    `json:"limit" default:"20" description:"Quantidade de registros"`
+		Cursor string `json:"cursor" description:"Cursor da página, vazio na primeira"`
 	}
 
 	ListCategoriesResponse struct {
 		Categories []CoreCategory `json:"categories"`
-		Limit      int            `json:"limit" default:"10"`
-		Offset     int            `json:"offset"`
+		Limit      int            `json:"limit" default:"20"`
 		Total      int64          `json:"total"`
+		NextCursor string         `json:"next_cursor,omitempty" description:"Cursor da próxima página"`
+		PrevCursor string         `json:"prev_cursor,omitempty" description:"Cursor da página anterior"`
 	}
 )
 
+func CoreCategoryFromModel(in *models.Category) CoreCategory {
+	out := CoreCategory{
+		ID:           in.ID.String(),
+		CreatedAt:    in.CreatedAt.String(),
+		Name:         in.Name,
+		DisplayOrder: in.DisplayOrder,
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = in.UpdatedAt.String()
+	}
+	if in.ParentID != uuid.Nil {
+		out.ParentID = in.ParentID.String()
+	}
+	if !in.Window.IsZero() {
+		out.AvailableFrom = in.Window.From.String()
+		out.AvailableTo = in.Window.To.String()
+	}
+	return out
+}
+
+func CategoryTreeResponseFromModel(in []*models.CategoryNode) []CategoryNodeResponse {
+	out := make([]CategoryNodeResponse, 0, len(in))
+	for _, n := range in {
+		out = append(out, CategoryNodeResponse{
+			CoreCategory: CoreCategoryFromModel(&n.Category),
+			Children:     CategoryTreeResponseFromModel(n.Children),
+		})
+	}
+	return out
+}
+
 type (
 	// PRODUCTS
 
@@ -58,9 +119,10 @@ type (
 	}
 
 	ListProductsByCategoryRequest struct {
-		ID     string `json:"id"`
-		Limit  int64  `json:"limit"`
-		Offset int64  `json:"offset"`
+		ID          string `json:"id"`
+		Descendants bool   `json:"descendants"`
+		Limit       int    `json:"limit"`
+		Cursor      string `json:"cursor"`
 	}
 
 	DeleteProductRequest struct {
@@ -95,13 +157,69 @@ type (
 		CreatedAt   string `json:"created_at,omitempty" readOnly:"true"`
 		UpdatedAt   string `json:"updated_at,omitempty" readOnly:"true"`
 		DeletedAt   string `json:"deleted_at,omitempty" readOnly:"true"`
+
+		Available        *bool  `json:"available,omitempty" readOnly:"true" description:"Disponível para pedidos"`
+		UnavailableUntil string `json:"unavailable_until,omitempty" readOnly:"true" description:"Volta a ficar disponível nesta data"`
+		AvailableFrom    string `json:"available_from,omitempty" readOnly:"true" description:"Início do horário de venda, próprio ou da categoria"`
+		AvailableTo      string `json:"available_to,omitempty" readOnly:"true" description:"Fim do horário de venda, próprio ou da categoria"`
+	}
+
+	// SetProductAvailabilityRequest holds the availability switch of a product
+	//	@Description	Product availability data
+	SetProductAvailabilityRequest struct {
+		ID        string `json:"-"`
+		Available bool   `json:"available" description:"Disponível para pedidos"`
+		Until     string `json:"until,omitempty" description:"Data RFC 3339 em que volta a ficar disponível, só quando indisponível"`
+	}
+
+	// SchedulePriceRequest holds a future price of a product
+	//	@Description	Scheduled price data
+	SchedulePriceRequest struct {
+		ID          string `json:"-"`
+		Price       string `json:"price" description:"Novo preço do produto"`
+		EffectiveAt string `json:"effective_at" description:"Data RFC 3339 futura em que o preço passa a valer"`
+	}
+
+	CancelScheduledPriceRequest struct {
+		ID      string `json:"id"`
+		PriceID string `json:"price_id"`
+	}
+
+	// ProductPriceResponse is an entry of the price history of a product
+	//	@Description	Product price history entry
+	ProductPriceResponse struct {
+		ID          string `json:"id"`
+		ProductID   string `json:"product_id"`
+		Price       string `json:"price"`
+		EffectiveAt string `json:"effective_at" description:"Quando o preço passa ou passou a valer"`
+		Status      string `json:"status" description:"scheduled, applied, superseded ou canceled"`
+		AppliedAt   string `json:"applied_at,omitempty" description:"Quando o preço do produto foi de fato alterado"`
+		CreatedAt   string `json:"created_at"`
+	}
+
+	PriceHistoryResponse struct {
+		ProductID string                 `json:"product_id"`
+		Prices    []ProductPriceResponse `json:"prices"`
 	}
 
 	ProductList struct {
-		Products []ProductResponse `json:"products"`
-		Limit    int               `json:"limit" default:"10"`
-		Offset   int               `json:"offset"`
-		Total    int               `json:"total"`
+		Products   []ProductResponse `json:"products"`
+		Limit      int               `json:"limit" default:"10"`
+		Offset     int               `json:"offset,omitempty"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"next_cursor,omitempty" description:"Cursor da próxima página"`
+		PrevCursor string            `json:"prev_cursor,omitempty" description:"Cursor da página anterior"`
+	}
+
+	SearchProductsRequest struct {
+		Query        string `json:"q"`
+		CategoryID   string `json:"category_id"`
+		MinPrice     string `json:"min_price"`
+		MaxPrice     string `json:"max_price"`
+		Availability string `json:"availability"`
+		Sort         string `json:"sort"`
+		Limit        int    `json:"limit"`
+		Offset       int    `json:"offset"`
 	}
 )
 
@@ -119,13 +237,86 @@ type (
 		Price     string `json:"price"`
 		OrderID   string `json:"order_id"`
 		Status    string `json:"status"`
+		Attempt   int    `json:"attempt"`
+	}
+
+	ListOrderPaymentsRequest struct {
+		ID string `json:"id"`
+	}
+
+	RetryPaymentRequest struct {
+		ID string `json:"id"`
+	}
+
+	// PaymentAttemptResponse holds a payment attempt and its history
+	//	@Description	Payment attempt with its status timeline
+	PaymentAttemptResponse struct {
+		ID        string                 `json:"id" description:"ID do pagamento"`
+		Attempt   int                    `json:"attempt" description:"Número da tentativa"`
+		Price     string                 `json:"price" description:"Valor cobrado"`
+		Status    string                 `json:"status" description:"Status atual do pagamento"`
+		CreatedAt string                 `json:"created_at" description:"Data de criação"`
+		UpdatedAt string                 `json:"updated_at,omitempty" description:"Data de atualização"`
+		Timeline  []PaymentEventResponse `json:"timeline" description:"Histórico do pagamento"`
+		SplitID   string                 `json:"split_id,omitempty" description:"Conta dividida da qual o pagamento é parte"`
+		Payer     string                 `json:"payer,omitempty" description:"Pagante da parte"`
+	}
+
+	PaymentEventResponse struct {
+		Kind       string `json:"kind" description:"Tipo do evento: created, sent ou status_changed"`
+		Status     string `json:"status" description:"Status do pagamento após o evento"`
+		OccurredAt string `json:"occurred_at" description:"Data do evento"`
+	}
+
+	PaymentAttemptList struct {
+		Payments []PaymentAttemptResponse `json:"payments"`
 	}
 )
 
+func PaymentAttemptResponseFromModel(in *models.Payment) PaymentAttemptResponse {
+	out := PaymentAttemptResponse{
+		ID:        in.ID.String(),
+		Attempt:   in.Attempt,
+		Price:     helpers.ParseDecimalToString(in.Price),
+		Status:    string(in.Status),
+		CreatedAt: in.CreatedAt.String(),
+		Timeline:  make([]PaymentEventResponse, 0, len(in.Timeline)),
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = in.UpdatedAt.String()
+	}
+	if in.SplitID != uuid.Nil {
+		out.SplitID = in.SplitID.String()
+		out.Payer = in.Payer
+	}
+	for _, e := range in.Timeline {
+		out.Timeline = append(out.Timeline, PaymentEventResponse{
+			Kind:       string(e.Kind),
+			Status:     string(e.Status),
+			OccurredAt: e.OccurredAt.String(),
+		})
+	}
+	return out
+}
+
 type (
 	ListOrderRequest struct {
-		Limit  int `json:"limt"`
-		Offset int `json:"offset"`
+		Limit  int    `json:"limit"`
+		Cursor string `json:"cursor"`
+	}
+
+	SearchOrdersRequest struct {
+		Statuses  []string `json:"status"`
+		From      string   `json:"from"`
+		To        string   `json:"to"`
+		UserID    string   `json:"user_id"`
+		Customer  string   `json:"customer"`
+		PaymentID string   `json:"payment_id"`
+		ProductID string   `json:"product_id"`
+		MinPrice  string   `json:"min_price"`
+		MaxPrice  string   `json:"max_price"`
+		Limit     int      `json:"limit"`
+		Cursor    string   `json:"cursor"`
 	}
 
 	GetOrderRequest struct {
@@ -140,6 +331,82 @@ type (
 		ID string `json:"id"`
 	}
 
+	// SplitCheckoutRequest holds how the bill is split between payers
+	//	@Description	Split checkout data
+	SplitCheckoutRequest struct {
+		ID    string               `json:"-"`
+		Parts []PaymentPartRequest `json:"parts" description:"Partes da conta, ao menos duas"`
+	}
+
+	PaymentPartRequest struct {
+		Payer  string              `json:"payer" description:"Quem paga a parte"`
+		Amount string              `json:"amount,omitempty" description:"Valor fixo da parte"`
+		Items  []RefundItemRequest `json:"items,omitempty" description:"Itens pagos pela parte, acrescidos da sua parcela dos ajustes"`
+	}
+
+	// CancelOrderRequest holds the cancellation data
+	//	@Description	Order cancellation data
+	CancelOrderRequest struct {
+		ID     string `json:"-"`
+		Reason string `json:"reason" description:"Motivo do cancelamento: customer_request, store_request, out_of_stock, duplicate_order ou other"`
+		Actor  string `json:"actor" description:"Quem solicitou o cancelamento"`
+	}
+
+	// UpdateOrderAdjustmentsRequest holds the tip and service charge choice of an open order
+	//	@Description	Order adjustments data
+	UpdateOrderAdjustmentsRequest struct {
+		ID            string      `json:"-"`
+		Tip           *TipRequest `json:"tip,omitempty" description:"Gorjeta, fixa ou percentual. Ausente mantém a atual, vazia remove a gorjeta"`
+		ServiceCharge *bool       `json:"service_charge,omitempty" description:"Cobrar a taxa de serviço da loja. Ausente mantém a escolha atual"`
+	}
+
+	TipRequest struct {
+		Amount     string `json:"amount,omitempty" description:"Valor fixo da gorjeta"`
+		Percentage string `json:"percentage,omitempty" description:"Percentual da gorjeta sobre o subtotal"`
+	}
+
+	// RefundOrderRequest holds the refund request data
+	//	@Description	Refund request data, without items the whole remaining amount is refunded
+	RefundOrderRequest struct {
+		ID        string              `json:"-"`
+		PaymentID string              `json:"payment_id,omitempty" description:"Parte da conta dividida a estornar, padrão o pagamento do pedido"`
+		Items     []RefundItemRequest `json:"items,omitempty" description:"Itens a estornar"`
+		Reason    string              `json:"reason,omitempty" description:"Motivo do estorno"`
+	}
+
+	RefundItemRequest struct {
+		ProductID string `json:"product_id" description:"ID do produto"`
+		Quantity  int    `json:"quantity" description:"Quantidade a estornar"`
+	}
+
+	ListOrderRefundsRequest struct {
+		ID string `json:"id"`
+	}
+
+	// RefundResponse holds the refund data
+	//	@Description	Refund data
+	RefundResponse struct {
+		ID        string               `json:"id" description:"ID do estorno"`
+		PaymentID string               `json:"payment_id" description:"ID do pagamento"`
+		OrderID   string               `json:"order_id" description:"ID do Pedido"`
+		Amount    string               `json:"amount" description:"Valor estornado"`
+		Items     []RefundItemResponse `json:"items,omitempty" description:"Itens estornados"`
+		Reason    string               `json:"reason,omitempty" description:"Motivo do estorno"`
+		Status    string               `json:"status" description:"Status do estorno"`
+		CreatedAt string               `json:"created_at" description:"Data de criação"`
+		UpdatedAt string               `json:"updated_at,omitempty" description:"Data de atualização"`
+	}
+
+	RefundItemResponse struct {
+		ProductID string `json:"product_id" description:"ID do produto"`
+		Quantity  int    `json:"quantity" description:"Quantidade estornada"`
+		Amount    string `json:"amount" description:"Valor estornado"`
+	}
+
+	RefundList struct {
+		Refunds []RefundResponse `json:"refunds"`
+	}
+
 	DeleteOrderResponse struct {
 		Deleted string `json:"deleted"`
 	}
@@ -148,6 +415,11 @@ type (
 		PaymentID string `json:"payment_id"`
 	}
 
+	GetOrderByPickupNumberRequest struct {
+		Number int    `json:"number"`
+		Date   string `json:"date"`
+	}
+
 	// OrderResponse holds the order response data
 	//	@Description	Order response data
 	OrderResponse struct {
@@ -156,16 +428,44 @@ type (
 		CreatedAt string            `json:"created_at" description:"Data de criação"`
 		UpdatedAt string            `json:"updated_at,omitempty" description:"Data de atualização"`
 		DeletedAt string            `json:"deleted_at,omitempty" description:"Data de deleção"`
-		Price     string            `json:"price" description:"Preço do pedido"`
+		Price     string            `json:"price" description:"Preço total do pedido, mantido por compatibilidade com total"`
 		Status    string            `json:"status" description:"Status do pedido"`
 		Products  []ProductResponse `json:"products" description:"Lista de Pedidos"`
+
+		Subtotal    string               `json:"subtotal" description:"Soma dos produtos"`
+		Adjustments []AdjustmentResponse `json:"adjustments" description:"Gorjeta e taxa de serviço"`
+		Total       string               `json:"total" description:"Subtotal mais ajustes, valor cobrado no pagamento"`
+
+		PaymentParts []PaymentPartResponse `json:"payment_parts,omitempty" description:"Cobranças da conta dividida"`
+
+		PickupNumber int    `json:"pickup_number,omitempty" description:"Senha de retirada do dia"`
+		CustomerName string `json:"customer_name,omitempty" description:"Nome do cliente"`
+		CancelReason string `json:"cancel_reason,omitempty" description:"Motivo do cancelamento"`
+		CanceledAt   string `json:"canceled_at,omitempty" description:"Data do cancelamento"`
+		CanceledBy   string `json:"canceled_by,omitempty" description:"Quem cancelou o pedido"`
+		PixCode      string `json:"pix_code,omitempty" description:"PIX copia e cola da cobrança pendente"`
+	}
+
+	PaymentPartResponse struct {
+		PaymentID string `json:"payment_id" description:"ID do pagamento da parte"`
+		Payer     string `json:"payer" description:"Quem paga a parte"`
+		Price     string `json:"price" description:"Valor cobrado da parte"`
+		Status    string `json:"status" description:"Status do pagamento da parte"`
+		PixCode   string `json:"pix_code,omitempty" description:"PIX copia e cola da parte"`
+	}
+
+	AdjustmentResponse struct {
+		Kind       string `json:"kind" description:"Tipo do ajuste: tip ou service_charge"`
+		Percentage string `json:"percentage,omitempty" description:"Percentual sobre o subtotal"`
+		Amount     string `json:"amount" description:"Valor do ajuste"`
 	}
 
 	// CreateOrderRequest holds the order request data
 	//	@Description	Order request data
 	CreateOrderRequest struct {
-		UserID      string   `json:"user_id" description:"ID do dono do pedido"`
-		ProductsIDs []string `json:"products_ids" description:"ID dos produtos"`
+		UserID       string   `json:"user_id" description:"ID do dono do pedido"`
+		ProductsIDs  []string `json:"products_ids" description:"ID dos produtos"`
+		CustomerName string   `json:"customer_name,omitempty" description:"Nome do cliente para chamada na retirada"`
 	}
 
 	// UpdateOrderRequest holds the order request data for update
@@ -176,13 +476,126 @@ type (
 	}
 
 	OrderList struct {
-		Orders []OrderResponse `json:"orders"`
-		Limit  int             `json:"limit" default:"10"`
-		Offset int             `json:"offset"`
-		Total  int             `json:"total"`
+		Orders     []OrderResponse `json:"orders"`
+		Limit      int             `json:"limit" default:"20"`
+		Total      int             `json:"total"`
+		NextCursor string          `json:"next_cursor,omitempty" description:"Cursor da próxima página"`
+		PrevCursor string          `json:"prev_cursor,omitempty" description:"Cursor da página anterior"`
+	}
+
+	// OrderEventResponse holds a status change pushed to streaming clients
+	//	@Description	Order status change event
+	OrderEventResponse struct {
+		ID             uint64 `json:"id,omitempty" description:"ID sequencial do evento"`
+		OrderID        string `json:"order_id" description:"ID do Pedido"`
+		Status         string `json:"status" description:"Status do pedido"`
+		PreviousStatus string `json:"previous_status,omitempty" description:"Status anterior do pedido"`
+		OccurredAt     string `json:"occurred_at" description:"Data da mudança de status"`
+	}
+)
+
+type (
+	PickupPanelEntryResponse struct {
+		PickupNumber int    `json:"pickup_number" description:"Senha de retirada"`
+		CustomerName string `json:"customer_name,omitempty" description:"Primeiro nome do cliente"`
+	}
+
+	// PickupPanelResponse holds what the public pickup panel shows
+	//	@Description	Pickup panel data
+	PickupPanelResponse struct {
+		Preparing   []PickupPanelEntryResponse `json:"preparing" description:"Pedidos em preparação"`
+		Ready       []PickupPanelEntryResponse `json:"ready" description:"Pedidos prontos para retirada"`
+		GeneratedAt string                     `json:"generated_at" description:"Data de geração do painel"`
 	}
 )
 
+type (
+	// MenuResponse holds the whole catalog shown on the kiosks
+	//	@Description	Menu data
+	MenuResponse struct {
+		Open       bool                   `json:"open" description:"Loja aceitando pedidos agora"`
+		Categories []MenuCategoryResponse `json:"categories" description:"Categorias com seus produtos"`
+		UpdatedAt  string                 `json:"updated_at,omitempty" description:"Data da última alteração do cardápio"`
+	}
+
+	MenuCategoryResponse struct {
+		ID       string            `json:"id" description:"ID da categoria"`
+		Name     string            `json:"name" description:"Nome da categoria"`
+		ParentID string            `json:"parent_id,omitempty" description:"Categoria pai, vazio no primeiro nível"`
+		Products []ProductResponse `json:"products" description:"Produtos da categoria"`
+	}
+)
+
+func MenuResponseFromModel(in *models.Menu) MenuResponse {
+	out := MenuResponse{Open: in.Open, Categories: make([]MenuCategoryResponse, 0, len(in.Categories))}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = in.UpdatedAt.String()
+	}
+	for _, c := range in.Categories {
+		category := MenuCategoryResponse{
+			ID:       c.ID.String(),
+			Name:     c.Name,
+			Products: make([]ProductResponse, 0, len(c.Products)),
+		}
+		if c.ParentID != uuid.Nil {
+			category.ParentID = c.ParentID.String()
+		}
+		for _, p := range c.Products {
+			category.Products = append(category.Products, ProductResponseFromModel(&p))
+		}
+		out.Categories = append(out.Categories, category)
+	}
+	return out
+}
+
+func PickupPanelResponseFromModel(in *models.PickupPanel) PickupPanelResponse {
+	out := PickupPanelResponse{
+		Preparing:   make([]PickupPanelEntryResponse, 0, len(in.Preparing)),
+		Ready:       make([]PickupPanelEntryResponse, 0, len(in.Ready)),
+		GeneratedAt: in.GeneratedAt.String(),
+	}
+	for _, e := range in.Preparing {
+		out.Preparing = append(out.Preparing, PickupPanelEntryResponse{PickupNumber: e.PickupNumber, CustomerName: e.CustomerName})
+	}
+	for _, e := range in.Ready {
+		out.Ready = append(out.Ready, PickupPanelEntryResponse{PickupNumber: e.PickupNumber, CustomerName: e.CustomerName})
+	}
+	return out
+}
+
+func RefundResponseFromModel(in *models.Refund) RefundResponse {
+	out := RefundResponse{
+		ID:        in.ID.String(),
+		PaymentID: in.PaymentID.String(),
+		OrderID:   in.OrderID.String(),
+		Amount:    helpers.ParseDecimalToString(in.Amount),
+		Reason:    in.Reason,
+		Status:    string(in.Status),
+		CreatedAt: in.CreatedAt.String(),
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = in.UpdatedAt.String()
+	}
+	for _, i := range in.Items {
+		out.Items = append(out.Items, RefundItemResponse{
+			ProductID: i.ProductID.String(),
+			Quantity:  i.Quantity,
+			Amount:    helpers.ParseDecimalToString(i.Amount),
+		})
+	}
+	return out
+}
+
+func OrderEventResponseFromModel(in models.OrderEvent) OrderEventResponse {
+	return OrderEventResponse{
+		ID:             in.ID,
+		OrderID:        in.OrderID.String(),
+		Status:         string(in.Status),
+		PreviousStatus: string(in.PreviousStatus),
+		OccurredAt:     in.OccurredAt.String(),
+	}
+}
+
 func OrderResponseFromModel(in *models.Order) OrderResponse {
 	out := OrderResponse{
 		ID:        in.ID.String(),
@@ -193,6 +606,41 @@ func OrderResponseFromModel(in *models.Order) OrderResponse {
 		Price:     helpers.ParseDecimalToString(in.Price),
 		Status:    string(in.Status),
 		Products:  nil,
+
+		PickupNumber: in.PickupNumber,
+		CustomerName: in.CustomerName,
+		CancelReason: string(in.CancelReason),
+		CanceledBy:   in.CanceledBy,
+		PixCode:      in.PixCode,
+
+		Subtotal:    helpers.ParseDecimalToString(in.Subtotal()),
+		Adjustments: make([]AdjustmentResponse, 0, len(in.Adjustments)),
+		Total:       helpers.ParseDecimalToString(in.Price),
+	}
+
+	for _, p := range in.PaymentParts {
+		out.PaymentParts = append(out.PaymentParts, PaymentPartResponse{
+			PaymentID: p.ID.String(),
+			Payer:     p.Payer,
+			Price:     helpers.ParseDecimalToString(p.Price),
+			Status:    string(p.Status),
+			PixCode:   p.PixCode,
+		})
+	}
+
+	for _, a := range in.Adjustments {
+		adjustment := AdjustmentResponse{
+			Kind:   string(a.Kind),
+			Amount: helpers.ParseDecimalToString(a.Amount),
+		}
+		if a.IsPercentage() {
+			adjustment.Percentage = a.Percentage.String()
+		}
+		out.Adjustments = append(out.Adjustments, adjustment)
+	}
+
+	if !in.CanceledAt.IsZero() {
+		out.CanceledAt = in.CanceledAt.String()
 	}
 
 	if !in.UpdatedAt.IsZero() {
@@ -205,7 +653,10 @@ func OrderResponseFromModel(in *models.Order) OrderResponse {
 
 	var prods []ProductResponse
 	for _, p := range in.Products {
-		prods = append(prods, ProductResponseFromModel(&p))
+		prod := ProductResponseFromModel(&p)
+		// order lines are what was sold, availability belongs to the catalog
+		prod.Available = nil
+		prods = append(prods, prod)
 	}
 	out.Products = prods
 	return out
@@ -219,10 +670,341 @@ func ProductResponseFromModel(in *models.Product) ProductResponse {
 		CategoryID:  in.CategoryID.String(),
 		Price:       helpers.ParseDecimalToString(in.Price),
 		CreatedAt:   in.CreatedAt.String(),
+		Available:   &in.Available,
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = in.UpdatedAt.String()
+	}
+	if !in.Available && !in.UnavailableUntil.IsZero() {
+		out.UnavailableUntil = in.UnavailableUntil.String()
+	}
+	if in.IsArchived() {
+		out.DeletedAt = in.DeletedAt.String()
+	}
+	if window := in.OrderableWindow(); !window.IsZero() {
+		out.AvailableFrom = window.From.String()
+		out.AvailableTo = window.To.String()
+	}
+
+	return out
+}
+
+func ProductPriceResponseFromModel(in *models.ProductPrice) ProductPriceResponse {
+	out := ProductPriceResponse{
+		ID:          in.ID.String(),
+		ProductID:   in.ProductID.String(),
+		Price:       helpers.ParseDecimalToString(in.Price),
+		EffectiveAt: in.EffectiveAt.String(),
+		Status:      string(in.Status),
+		CreatedAt:   in.CreatedAt.String(),
+	}
+	if !in.AppliedAt.IsZero() {
+		out.AppliedAt = in.AppliedAt.String()
+	}
+	return out
+}
+
+type (
+	// CreateWebhookSubscriptionRequest holds the webhook subscription data
+	//	@Description	Webhook subscription data
+	CreateWebhookSubscriptionRequest struct {
+		URL      string   `json:"url" description:"URL que recebe os eventos"`
+		Secret   string   `json:"secret,omitempty" description:"Segredo da assinatura HMAC, gerado quando omitido"`
+		Statuses []string `json:"statuses,omitempty" description:"Status de pedido que disparam o webhook, todos quando omitido"`
+	}
+
+	GetWebhookSubscriptionRequest struct {
+		ID string `json:"id"`
+	}
+
+	// WebhookSubscriptionResponse holds the webhook subscription data
+	//	@Description	Webhook subscription, the secret is only shown at creation
+	WebhookSubscriptionResponse struct {
+		ID        string   `json:"id" description:"ID da assinatura"`
+		URL       string   `json:"url" description:"URL que recebe os eventos"`
+		Secret    string   `json:"secret,omitempty" description:"Segredo da assinatura HMAC"`
+		Statuses  []string `json:"statuses" description:"Status de pedido que disparam o webhook"`
+		CreatedAt string   `json:"created_at" description:"Data de criação"`
+	}
+
+	WebhookSubscriptionList struct {
+		Subscriptions []WebhookSubscriptionResponse `json:"subscriptions"`
+	}
+
+	// WebhookDeliveryResponse holds one delivery attempt
+	//	@Description	Webhook delivery attempt
+	WebhookDeliveryResponse struct {
+		EventID     uint64 `json:"event_id" description:"ID do evento"`
+		OrderID     string `json:"order_id" description:"ID do Pedido"`
+		OrderStatus string `json:"order_status" description:"Status do pedido notificado"`
+		Attempt     int    `json:"attempt" description:"Número da tentativa"`
+		StatusCode  int    `json:"status_code,omitempty" description:"Código HTTP da resposta"`
+		Error       string `json:"error,omitempty" description:"Erro de envio"`
+		Succeeded   bool   `json:"succeeded" description:"Entregue com sucesso"`
+		AttemptedAt string `json:"attempted_at" description:"Data da tentativa"`
+	}
+
+	WebhookDeliveryList struct {
+		Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	}
+)
+
+func WebhookSubscriptionResponseFromModel(in *models.WebhookSubscription, withSecret bool) WebhookSubscriptionResponse {
+	out := WebhookSubscriptionResponse{
+		ID:        in.ID.String(),
+		URL:       in.URL,
+		Statuses:  make([]string, 0, len(in.Statuses)),
+		CreatedAt: in.CreatedAt.String(),
+	}
+	if withSecret {
+		out.Secret = in.Secret
+	}
+	for _, s := range in.Statuses {
+		out.Statuses = append(out.Statuses, string(s))
+	}
+	return out
+}
+
+func WebhookDeliveryResponseFromModel(in *models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		EventID:     in.EventID,
+		OrderID:     in.OrderID.String(),
+		OrderStatus: string(in.OrderStatus),
+		Attempt:     in.Attempt,
+		StatusCode:  in.StatusCode,
+		Error:       in.Error,
+		Succeeded:   in.Succeeded,
+		AttemptedAt: in.AttemptedAt.String(),
+	}
+}
+
+type (
+	// INVENTORY
+
+	// CreateIngredientRequest holds the ingredient data
+	//	@Description	Ingredient data
+	CreateIngredientRequest struct {
+		Name  string `json:"name" description:"Nome do ingrediente"`
+		Unit  string `json:"unit" description:"Unidade de medida, como un, g ou ml"`
+		Stock string `json:"stock,omitempty" description:"Estoque inicial"`
+	}
+
+	GetIngredientRequest struct {
+		ID string `json:"id"`
+	}
+
+	// IngredientResponse holds the ingredient stock
+	//	@Description	Ingredient stock, only available can still be sold
+	IngredientResponse struct {
+		ID        string `json:"id" description:"ID do ingrediente"`
+		Name      string `json:"name" description:"Nome do ingrediente"`
+		Unit      string `json:"unit" description:"Unidade de medida"`
+		Stock     string `json:"stock" description:"Estoque físico"`
+		Reserved  string `json:"reserved" description:"Reservado por pedidos aguardando pagamento"`
+		Available string `json:"available" description:"Disponível para novos pedidos"`
+		UpdatedAt string `json:"updated_at,omitempty" description:"Data da última movimentação"`
+	}
+
+	IngredientList struct {
+		Ingredients []IngredientResponse `json:"ingredients"`
+	}
+
+	// AdjustStockRequest holds a manual stock change
+	//	@Description	Manual stock change, negative for losses
+	AdjustStockRequest struct {
+		ID     string `json:"-"`
+		Delta  string `json:"delta" description:"Quantidade somada ao estoque, negativa para perdas"`
+		Reason string `json:"reason" description:"Motivo do ajuste"`
+		Actor  string `json:"actor,omitempty" description:"Quem fez o ajuste"`
+	}
+
+	ListStockMovementsRequest struct {
+		ID    string `json:"id"`
+		Limit int    `json:"limit"`
+	}
+
+	// StockMovementResponse holds one entry of the stock history
+	//	@Description	Stock movement
+	StockMovementResponse struct {
+		ID            int64  `json:"id" description:"ID da movimentação"`
+		Kind          string `json:"kind" description:"Tipo: reserve, commit, release ou adjust"`
+		OrderID       string `json:"order_id,omitempty" description:"ID do Pedido"`
+		Quantity      string `json:"quantity" description:"Quantidade movimentada"`
+		StockAfter    string `json:"stock_after" description:"Estoque após a movimentação"`
+		ReservedAfter string `json:"reserved_after" description:"Reservado após a movimentação"`
+		Reason        string `json:"reason,omitempty" description:"Motivo do ajuste"`
+		Actor         string `json:"actor,omitempty" description:"Quem fez o ajuste"`
+		OccurredAt    string `json:"occurred_at" description:"Data da movimentação"`
+	}
+
+	StockMovementList struct {
+		Movements []StockMovementResponse `json:"movements"`
+	}
+
+	RecipeItemRequest struct {
+		IngredientID string `json:"ingredient_id" description:"ID do ingrediente"`
+		Quantity     string `json:"quantity" description:"Quantidade usada por unidade do produto"`
+	}
+
+	// SetRecipeRequest holds the ingredients of a product
+	//	@Description	Recipe of a product, empty to stop tracking its stock
+	SetRecipeRequest struct {
+		ProductID string              `json:"-"`
+		Items     []RecipeItemRequest `json:"items" description:"Ingredientes por unidade do produto"`
+	}
+
+	RecipeItemResponse struct {
+		IngredientID string `json:"ingredient_id" description:"ID do ingrediente"`
+		Quantity     string `json:"quantity" description:"Quantidade usada por unidade do produto"`
+	}
+
+	RecipeResponse struct {
+		ProductID string               `json:"product_id" description:"ID do produto"`
+		Items     []RecipeItemResponse `json:"items" description:"Ingredientes por unidade do produto"`
+	}
+)
+
+func IngredientResponseFromModel(in *models.Ingredient) IngredientResponse {
+	out := IngredientResponse{
+		ID:        in.ID.String(),
+		Name:      in.Name,
+		Unit:      in.Unit,
+		Stock:     in.Stock.String(),
+		Reserved:  in.Reserved.String(),
+		Available: in.Available().String(),
 	}
 	if !in.UpdatedAt.IsZero() {
 		out.UpdatedAt = in.UpdatedAt.String()
 	}
+	return out
+}
+
+func StockMovementResponseFromModel(in *models.StockMovement) StockMovementResponse {
+	out := StockMovementResponse{
+		ID:            in.ID,
+		Kind:          string(in.Kind),
+		Quantity:      in.Quantity.String(),
+		StockAfter:    in.StockAfter.String(),
+		ReservedAfter: in.ReservedAfter.String(),
+		Reason:        in.Reason,
+		Actor:         in.Actor,
+		OccurredAt:    in.OccurredAt.String(),
+	}
+	if in.OrderID != uuid.Nil {
+		out.OrderID = in.OrderID.String()
+	}
+	return out
+}
+
+func RecipeResponseFromModel(productID uuid.UUID, in []models.RecipeItem) RecipeResponse {
+	out := RecipeResponse{
+		ProductID: productID.String(),
+		Items:     make([]RecipeItemResponse, 0, len(in)),
+	}
+	for _, i := range in {
+		out.Items = append(out.Items, RecipeItemResponse{
+			IngredientID: i.IngredientID.String(),
+			Quantity:     i.Quantity.String(),
+		})
+	}
+	return out
+}
+
+type (
+	// SetWindowRequest holds the hours of the day a product or category is sold
+	//	@Description	Availability window data
+	SetWindowRequest struct {
+		ID   string `json:"-"`
+		From string `json:"available_from" description:"Início do horário de venda HH:MM, vazio para o dia todo"`
+		To   string `json:"available_to" description:"Fim do horário de venda HH:MM, antes do início passa da meia-noite"`
+	}
+
+	// BusinessHoursEntry is a period the store is open on a weekday
+	//	@Description	Business hours period
+	BusinessHoursEntry struct {
+		Weekday  int    `json:"weekday" description:"Dia da semana, 0 é domingo"`
+		OpensAt  string `json:"opens_at" description:"Abertura HH:MM"`
+		ClosesAt string `json:"closes_at" description:"Fechamento HH:MM, antes da abertura passa da meia-noite"`
+	}
+
+	// SetBusinessHoursRequest replaces the weekly hours of the store
+	//	@Description	Weekly business hours
+	SetBusinessHoursRequest struct {
+		Hours []BusinessHoursEntry `json:"hours" description:"Períodos de funcionamento, vazio para sempre aberto"`
+	}
 
+	// HoursExceptionEntry replaces the weekly hours on a day
+	//	@Description	Business hours exception
+	HoursExceptionEntry struct {
+		Day      string `json:"day" readOnly:"true" description:"Dia YYYY-MM-DD"`
+		Closed   bool   `json:"closed" description:"Fechado o dia todo"`
+		OpensAt  string `json:"opens_at,omitempty" description:"Abertura HH:MM, quando não fechado"`
+		ClosesAt string `json:"closes_at,omitempty" description:"Fechamento HH:MM, quando não fechado"`
+		Reason   string `json:"reason,omitempty" description:"Motivo, como um feriado"`
+	}
+
+	DeleteHoursExceptionRequest struct {
+		Day string `json:"day"`
+	}
+
+	GetStoreHoursRequest struct{}
+
+	// StoreScheduleResponse holds the business hours of the store, in its timezone
+	//	@Description	Store business hours
+	StoreScheduleResponse struct {
+		Hours      []BusinessHoursEntry  `json:"hours" description:"Horário semanal, vazio quando sempre aberto"`
+		Exceptions []HoursExceptionEntry `json:"exceptions" description:"Exceções a partir de ontem"`
+	}
+
+	// StoreStatusResponse tells whether the store takes orders
+	//	@Description	Store status
+	StoreStatusResponse struct {
+		Open bool   `json:"open" description:"Aceitando pedidos"`
+		At   string `json:"at" description:"Momento da consulta"`
+	}
+)
+
+// windowFromRequest reads an optional HH:MM window, both ends empty is no window
+func windowFromRequest(from, to string) (models.TimeWindow, error) {
+	if from == "" && to == "" {
+		return models.TimeWindow{}, nil
+	}
+	var (
+		out models.TimeWindow
+		err error
+	)
+	if out.From, err = models.ParseClockTime(from); err != nil {
+		return models.TimeWindow{}, err
+	}
+	if out.To, err = models.ParseClockTime(to); err != nil {
+		return models.TimeWindow{}, err
+	}
+	return out, nil
+}
+
+func StoreScheduleResponseFromModel(in *models.StoreSchedule) StoreScheduleResponse {
+	out := StoreScheduleResponse{
+		Hours:      make([]BusinessHoursEntry, 0, len(in.Hours)),
+		Exceptions: make([]HoursExceptionEntry, 0, len(in.Exceptions)),
+	}
+	for _, h := range in.Hours {
+		out.Hours = append(out.Hours, BusinessHoursEntry{
+			Weekday:  int(h.Weekday),
+			OpensAt:  h.From.String(),
+			ClosesAt: h.To.String(),
+		})
+	}
+	for _, e := range in.Exceptions {
+		entry := HoursExceptionEntry{
+			Day:    e.Day.Format(time.DateOnly),
+			Closed: e.Closed,
+			Reason: e.Reason,
+		}
+		if !e.Closed {
+			entry.OpensAt = e.Window.From.String()
+			entry.ClosesAt = e.Window.To.String()
+		}
+		out.Exceptions = append(out.Exceptions, entry)
+	}
 	return out
 }
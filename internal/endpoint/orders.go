@@ -2,11 +2,14 @@ package endpoint
 
 import (
 	"context"
+	"time"
 
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type (
@@ -14,10 +17,19 @@ type (
 		GetOrderEndpoint         endpoint.Endpoint
 		CreateOrderEndpoint      endpoint.Endpoint
 		UpdateOrderItemsEndpoint endpoint.Endpoint
+		UpdateAdjustments        endpoint.Endpoint
 		ListOrdersEndpoint       endpoint.Endpoint
 		DeleteOrderEndpoint      endpoint.Endpoint
 		OrderCheckoutEndpoint    endpoint.Endpoint
+		SplitCheckoutEndpoint    endpoint.Endpoint
+		CancelOrderEndpoint      endpoint.Endpoint
+		ListOrderPayments        endpoint.Endpoint
+		RetryPaymentEndpoint     endpoint.Endpoint
+		RefundOrderEndpoint      endpoint.Endpoint
+		ListOrderRefunds         endpoint.Endpoint
 		GetOrderByPaymentID      endpoint.Endpoint
+		GetOrderByPickupNumber   endpoint.Endpoint
+		SearchOrdersEndpoint     endpoint.Endpoint
 	}
 )
 
@@ -26,10 +38,19 @@ func MakeOrdersEndpoint(svc service.OrdersService) OrdersEndpoint {
 		GetOrderEndpoint:         makeGetOrderEndpoint(svc),
 		CreateOrderEndpoint:      makeCreateOrderEndpoint(svc),
 		UpdateOrderItemsEndpoint: makeUpdateOrderItemsEndpoint(svc),
+		UpdateAdjustments:        makeUpdateAdjustmentsEndpoint(svc),
 		DeleteOrderEndpoint:      makeDeleteOrderEndpoint(svc),
 		OrderCheckoutEndpoint:    makeOrderCheckoutEndpoint(svc),
+		SplitCheckoutEndpoint:    makeSplitCheckoutEndpoint(svc),
+		CancelOrderEndpoint:      makeCancelOrderEndpoint(svc),
+		ListOrderPayments:        makeListOrderPaymentsEndpoint(svc),
+		RetryPaymentEndpoint:     makeRetryPaymentEndpoint(svc),
+		RefundOrderEndpoint:      makeRefundOrderEndpoint(svc),
+		ListOrderRefunds:         makeListOrderRefundsEndpoint(svc),
 		GetOrderByPaymentID:      makeGetOrderByPaymentIDEndpoint(svc),
+		GetOrderByPickupNumber:   makeGetOrderByPickupNumberEndpoint(svc),
 		ListOrdersEndpoint:       makeListOrdersEndpoint(svc),
+		SearchOrdersEndpoint:     makeSearchOrdersEndpoint(svc),
 	}
 }
 
@@ -37,25 +58,109 @@ func makeListOrdersEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(ListOrderRequest)
 
-		svcOut, err := svc.ListOrders(ctx, req.Limit, req.Offset)
+		page, err := pageRequest(req.Limit, req.Cursor)
 		if err != nil {
 			return nil, err
 		}
 
-		var orders []OrderResponse
+		svcOut, err := svc.ListOrders(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+
+		orders := make([]OrderResponse, 0, len(svcOut.Orders))
 		for _, o := range svcOut.Orders {
 			orders = append(orders, OrderResponseFromModel(o))
 		}
 
 		return OrderList{
-			Orders: orders,
-			Limit:  req.Limit,
-			Offset: req.Offset,
-			Total:  len(orders),
+			Orders:     orders,
+			Limit:      svcOut.Limit,
+			Total:      int(svcOut.Total),
+			NextCursor: svcOut.Next.Encode(),
+			PrevCursor: svcOut.Prev.Encode(),
+		}, nil
+	}
+}
+
+func makeSearchOrdersEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SearchOrdersRequest)
+
+		in := models.OrderSearch{CustomerName: req.Customer}
+		if in.Page, err = pageRequest(req.Limit, req.Cursor); err != nil {
+			return nil, err
+		}
+		for _, s := range req.Statuses {
+			in.Statuses = append(in.Statuses, models.OrderStatus(s))
+		}
+		if req.From != "" {
+			if in.CreatedFrom, in.FromDay, err = parseSearchTime(req.From); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+		if req.To != "" {
+			if in.CreatedTo, in.ToDay, err = parseSearchTime(req.To); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+		for _, id := range []struct {
+			in  string
+			out *uuid.UUID
+		}{
+			{req.UserID, &in.UserID},
+			{req.PaymentID, &in.PaymentID},
+			{req.ProductID, &in.ProductID},
+		} {
+			if id.in == "" {
+				continue
+			}
+			if *id.out, err = uuid.Parse(id.in); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+		if req.MinPrice != "" {
+			if in.MinPrice.Decimal, err = decimal.NewFromString(req.MinPrice); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+			in.MinPrice.Valid = true
+		}
+		if req.MaxPrice != "" {
+			if in.MaxPrice.Decimal, err = decimal.NewFromString(req.MaxPrice); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+			in.MaxPrice.Valid = true
+		}
+
+		found, err := svc.SearchOrders(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		orders := make([]OrderResponse, 0, len(found.Orders))
+		for _, o := range found.Orders {
+			orders = append(orders, OrderResponseFromModel(o))
+		}
+
+		return OrderList{
+			Orders:     orders,
+			Limit:      found.Limit,
+			Total:      int(found.Total),
+			NextCursor: found.Next.Encode(),
+			PrevCursor: found.Prev.Encode(),
 		}, nil
 	}
 }
 
+// parseSearchTime reads either a timestamp or a store day
+func parseSearchTime(in string) (at, day time.Time, err error) {
+	if at, err = time.Parse(time.RFC3339, in); err == nil {
+		return at, time.Time{}, nil
+	}
+	day, err = time.Parse(time.DateOnly, in)
+	return time.Time{}, day, err
+}
+
 func makeGetOrderByPaymentIDEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(GetOrderByPaymentIDRequest)
@@ -74,6 +179,26 @@ func makeGetOrderByPaymentIDEndpoint(svc service.OrdersService) endpoint.Endpoin
 	}
 }
 
+func makeGetOrderByPickupNumberEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetOrderByPickupNumberRequest)
+
+		var day time.Time
+		if req.Date != "" {
+			if day, err = time.Parse(time.DateOnly, req.Date); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+
+		order, err := svc.GetOrderByPickupNumber(ctx, req.Number, day)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderResponseFromModel(order), nil
+	}
+}
+
 func makeOrderCheckoutEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(CheckoutOrderRequest)
@@ -92,6 +217,163 @@ func makeOrderCheckoutEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	}
 }
 
+func makeSplitCheckoutEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SplitCheckoutRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := make([]models.PaymentPart, 0, len(req.Parts))
+		for _, p := range req.Parts {
+			part := models.PaymentPart{Payer: p.Payer}
+			if p.Amount != "" {
+				if part.Amount, err = decimal.NewFromString(p.Amount); err != nil {
+					return nil, helpers.ErrInvalidInput
+				}
+			}
+			for _, i := range p.Items {
+				prodID, err := uuid.Parse(i.ProductID)
+				if err != nil {
+					return nil, helpers.ErrInvalidInput
+				}
+				part.Items = append(part.Items, models.PaymentPartItem{ProductID: prodID, Quantity: i.Quantity})
+			}
+			parts = append(parts, part)
+		}
+
+		order, err := svc.SplitCheckout(ctx, oID, parts)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderResponseFromModel(order), nil
+	}
+}
+
+func makeCancelOrderEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(CancelOrderRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		reason := models.CancelReason(req.Reason)
+		if !reason.IsRequestable() {
+			return nil, helpers.ErrInvalidInput
+		}
+
+		order, err := svc.CancelOrder(ctx, oID, reason, req.Actor)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderResponseFromModel(order), nil
+	}
+}
+
+func makeListOrderPaymentsEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(ListOrderPaymentsRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		payments, err := svc.ListOrderPayments(ctx, oID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := PaymentAttemptList{Payments: make([]PaymentAttemptResponse, 0, len(payments))}
+		for _, p := range payments {
+			out.Payments = append(out.Payments, PaymentAttemptResponseFromModel(p))
+		}
+
+		return out, nil
+	}
+}
+
+func makeRetryPaymentEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(RetryPaymentRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		order, err := svc.RetryPayment(ctx, oID)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderResponseFromModel(order), nil
+	}
+}
+
+func makeRefundOrderEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(RefundOrderRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []models.RefundItem
+		for _, v := range req.Items {
+			prodID, err := uuid.Parse(v.ProductID)
+			if err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+			items = append(items, models.RefundItem{ProductID: prodID, Quantity: v.Quantity})
+		}
+
+		var pID uuid.UUID
+		if req.PaymentID != "" {
+			if pID, err = uuid.Parse(req.PaymentID); err != nil {
+				return nil, helpers.ErrInvalidInput
+			}
+		}
+
+		refund, err := svc.RefundOrder(ctx, oID, pID, items, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+
+		return RefundResponseFromModel(refund), nil
+	}
+}
+
+func makeListOrderRefundsEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(ListOrderRefundsRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		refunds, err := svc.ListOrderRefunds(ctx, oID)
+		if err != nil {
+			return nil, err
+		}
+
+		out := RefundList{Refunds: make([]RefundResponse, 0, len(refunds))}
+		for _, r := range refunds {
+			out.Refunds = append(out.Refunds, RefundResponseFromModel(r))
+		}
+
+		return out, nil
+	}
+}
+
 func makeDeleteOrderEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(DeleteOrderRequest)
@@ -140,6 +422,39 @@ func makeUpdateOrderItemsEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	}
 }
 
+func makeUpdateAdjustmentsEndpoint(svc service.OrdersService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(UpdateOrderAdjustmentsRequest)
+
+		oID, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var tip *models.Adjustment
+		if req.Tip != nil {
+			tip = &models.Adjustment{}
+			if req.Tip.Amount != "" {
+				if tip.Amount, err = decimal.NewFromString(req.Tip.Amount); err != nil {
+					return nil, helpers.ErrInvalidInput
+				}
+			}
+			if req.Tip.Percentage != "" {
+				if tip.Percentage, err = decimal.NewFromString(req.Tip.Percentage); err != nil {
+					return nil, helpers.ErrInvalidInput
+				}
+			}
+		}
+
+		order, err := svc.UpdateOrderAdjustments(ctx, oID, tip, req.ServiceCharge)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderResponseFromModel(order), nil
+	}
+}
+
 func makeCreateOrderEndpoint(svc service.OrdersService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		var order *models.Order
@@ -157,12 +472,12 @@ func makeCreateOrderEndpoint(svc service.OrdersService) endpoint.Endpoint {
 
 		uid, err := uuid.Parse(req.UserID)
 		if err == nil {
-			order, err = svc.CreateOrder(ctx, prods, uid)
+			order, err = svc.CreateOrder(ctx, prods, uid, req.CustomerName)
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			order, err = svc.CreateOrder(ctx, prods, uuid.Nil)
+			order, err = svc.CreateOrder(ctx, prods, uuid.Nil, req.CustomerName)
 			if err != nil {
 				return nil, err
 			}
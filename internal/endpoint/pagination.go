@@ -0,0 +1,18 @@
+package endpoint
+
+import (
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+)
+
+// pageRequest reads the optional limit and cursor of a list request
+func pageRequest(limit int, cursor string) (models.PageRequest, error) {
+	c, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.PageRequest{}, helpers.ErrInvalidInput
+	}
+	if limit < 0 {
+		return models.PageRequest{}, helpers.ErrInvalidInput
+	}
+	return models.PageRequest{Limit: limit, Cursor: c}, nil
+}
@@ -41,6 +41,7 @@ func makeGetPayment(svc service.PaymentsService) endpoint.Endpoint {
 			Price:     helpers.ParseDecimalToString(payment.Price),
 			OrderID:   payment.OrderID.String(),
 			Status:    string(payment.Status),
+			Attempt:   payment.Attempt,
 		}
 
 		return out, nil
@@ -5,8 +5,10 @@ import (
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/helpers"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	pkghelpers "github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/google/uuid"
+	"time"
 )
 
 type (
@@ -15,7 +17,14 @@ type (
 		InsertProductEndpoint  endpoint.Endpoint
 		UpdateProductEndpoint  endpoint.Endpoint
 		DeleteProductEndpoint  endpoint.Endpoint
+		RestoreProductEndpoint endpoint.Endpoint
+		SetAvailability        endpoint.Endpoint
 		ListProductsByCategory endpoint.Endpoint
+		SearchProducts         endpoint.Endpoint
+		SchedulePrice          endpoint.Endpoint
+		CancelScheduledPrice   endpoint.Endpoint
+		ListPriceHistory       endpoint.Endpoint
+		SetWindow              endpoint.Endpoint
 	}
 )
 
@@ -25,7 +34,14 @@ func MakeProductsEndpoint(svc service.ProductsService) ProductsEndpoints {
 		InsertProductEndpoint:  makeInsertProductEndpoint(svc),
 		UpdateProductEndpoint:  makeUpdateProductEndpoint(svc),
 		DeleteProductEndpoint:  makeDeleteProductEndpoint(svc),
+		RestoreProductEndpoint: makeRestoreProductEndpoint(svc),
+		SetAvailability:        makeSetAvailabilityEndpoint(svc),
 		ListProductsByCategory: makeListProductsByCategory(svc),
+		SearchProducts:         makeSearchProductsEndpoint(svc),
+		SchedulePrice:          makeSchedulePriceEndpoint(svc),
+		CancelScheduledPrice:   makeCancelScheduledPriceEndpoint(svc),
+		ListPriceHistory:       makeListPriceHistoryEndpoint(svc),
+		SetWindow:              makeSetProductWindowEndpoint(svc),
 	}
 }
 
@@ -37,16 +53,22 @@ func makeListProductsByCategory(svc service.ProductsService) endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		cats, err := svc.ListProductsByCategory(ctx, uid, int(req.Limit), int(req.Offset))
+		page, err := pageRequest(req.Limit, req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		cats, err := svc.ListProductsByCategory(ctx, uid, req.Descendants, page)
 		if err != nil {
 			return nil, err
 		}
 
 		out := ProductList{
-			Products: make([]ProductResponse, len(cats.Products)),
-			Limit:    int(req.Limit),
-			Offset:   int(req.Offset),
-			Total:    len(cats.Products),
+			Products:   make([]ProductResponse, len(cats.Products)),
+			Limit:      cats.Limit,
+			Total:      int(cats.Total),
+			NextCursor: cats.Next.Encode(),
+			PrevCursor: cats.Prev.Encode(),
 		}
 		for k, v := range cats.Products {
 			out.Products[k] = ProductResponseFromModel(v)
@@ -148,3 +170,190 @@ func makeDeleteProductEndpoint(svc service.ProductsService) endpoint.Endpoint {
 		return DelectProductResponse{Deleted: true}, err
 	}
 }
+
+func makeSearchProductsEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SearchProductsRequest)
+
+		in := models.ProductSearch{
+			Query:        req.Query,
+			Availability: models.ProductAvailability(req.Availability),
+			Sort:         models.ProductSort(req.Sort),
+			Limit:        req.Limit,
+			Offset:       req.Offset,
+		}
+		if req.CategoryID != "" {
+			if in.CategoryID, err = uuid.Parse(req.CategoryID); err != nil {
+				return nil, pkghelpers.ErrInvalidInput
+			}
+		}
+		if req.MinPrice != "" {
+			if in.MinPrice.Decimal, err = helpers.ParseDecimalFromString(req.MinPrice); err != nil {
+				return nil, pkghelpers.ErrInvalidInput
+			}
+			in.MinPrice.Valid = true
+		}
+		if req.MaxPrice != "" {
+			if in.MaxPrice.Decimal, err = helpers.ParseDecimalFromString(req.MaxPrice); err != nil {
+				return nil, pkghelpers.ErrInvalidInput
+			}
+			in.MaxPrice.Valid = true
+		}
+
+		found, err := svc.SearchProducts(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		out := ProductList{
+			Products: make([]ProductResponse, 0, len(found.Products)),
+			Limit:    found.Limit,
+			Offset:   found.Offset,
+			Total:    int(found.Total),
+		}
+		for _, p := range found.Products {
+			out.Products = append(out.Products, ProductResponseFromModel(p))
+		}
+
+		return out, nil
+	}
+}
+
+func makeRestoreProductEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetProductRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		product, err := svc.RestoreProduct(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProductResponseFromModel(product), nil
+	}
+}
+
+func makeSetAvailabilityEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SetProductAvailabilityRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var until time.Time
+		if req.Until != "" {
+			if until, err = time.Parse(time.RFC3339, req.Until); err != nil {
+				return nil, pkghelpers.ErrInvalidInput
+			}
+		}
+
+		product, err := svc.SetProductAvailability(ctx, id, req.Available, until)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProductResponseFromModel(product), nil
+	}
+}
+
+func makeSchedulePriceEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SchedulePriceRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		price, err := helpers.ParseDecimalFromString(req.Price)
+		if err != nil {
+			return nil, pkghelpers.ErrInvalidInput
+		}
+		effectiveAt, err := time.Parse(time.RFC3339, req.EffectiveAt)
+		if err != nil {
+			return nil, pkghelpers.ErrInvalidInput
+		}
+
+		scheduled, err := svc.SchedulePrice(ctx, id, price, effectiveAt)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProductPriceResponseFromModel(scheduled), nil
+	}
+}
+
+func makeCancelScheduledPriceEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(CancelScheduledPriceRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		priceID, err := uuid.Parse(req.PriceID)
+		if err != nil {
+			return nil, err
+		}
+
+		canceled, err := svc.CancelScheduledPrice(ctx, id, priceID)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProductPriceResponseFromModel(canceled), nil
+	}
+}
+
+func makeListPriceHistoryEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetProductRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		prices, err := svc.ListPriceHistory(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		out := PriceHistoryResponse{
+			ProductID: id.String(),
+			Prices:    make([]ProductPriceResponse, 0, len(prices)),
+		}
+		for _, p := range prices {
+			out.Prices = append(out.Prices, ProductPriceResponseFromModel(p))
+		}
+
+		return out, nil
+	}
+}
+
+func makeSetProductWindowEndpoint(svc service.ProductsService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(SetWindowRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		window, err := windowFromRequest(req.From, req.To)
+		if err != nil {
+			return nil, pkghelpers.ErrInvalidInput
+		}
+
+		product, err := svc.SetProductWindow(ctx, id, window)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProductResponseFromModel(product), nil
+	}
+}
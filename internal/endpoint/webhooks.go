@@ -0,0 +1,122 @@
+package endpoint
+
+import (
+	"context"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+type (
+	WebhooksEndpoints struct {
+		CreateSubscriptionEndpoint endpoint.Endpoint
+		GetSubscriptionEndpoint    endpoint.Endpoint
+		ListSubscriptionsEndpoint  endpoint.Endpoint
+		DeleteSubscriptionEndpoint endpoint.Endpoint
+		ListDeliveriesEndpoint     endpoint.Endpoint
+	}
+)
+
+func MakeWebhooksEndpoints(svc service.WebhooksService) WebhooksEndpoints {
+	return WebhooksEndpoints{
+		CreateSubscriptionEndpoint: makeCreateSubscriptionEndpoint(svc),
+		GetSubscriptionEndpoint:    makeGetSubscriptionEndpoint(svc),
+		ListSubscriptionsEndpoint:  makeListSubscriptionsEndpoint(svc),
+		DeleteSubscriptionEndpoint: makeDeleteSubscriptionEndpoint(svc),
+		ListDeliveriesEndpoint:     makeListDeliveriesEndpoint(svc),
+	}
+}
+
+func makeCreateSubscriptionEndpoint(svc service.WebhooksService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(CreateWebhookSubscriptionRequest)
+
+		statuses := make([]models.OrderStatus, 0, len(req.Statuses))
+		for _, s := range req.Statuses {
+			statuses = append(statuses, models.OrderStatus(s))
+		}
+
+		sub, err := svc.CreateSubscription(ctx, req.URL, req.Secret, statuses)
+		if err != nil {
+			return nil, err
+		}
+
+		return WebhookSubscriptionResponseFromModel(sub, true), nil
+	}
+}
+
+func makeGetSubscriptionEndpoint(svc service.WebhooksService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetWebhookSubscriptionRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sub, err := svc.GetSubscription(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return WebhookSubscriptionResponseFromModel(sub, false), nil
+	}
+}
+
+func makeListSubscriptionsEndpoint(svc service.WebhooksService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		subs, err := svc.ListSubscriptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		out := WebhookSubscriptionList{Subscriptions: make([]WebhookSubscriptionResponse, 0, len(subs))}
+		for _, s := range subs {
+			out.Subscriptions = append(out.Subscriptions, WebhookSubscriptionResponseFromModel(s, false))
+		}
+
+		return out, nil
+	}
+}
+
+func makeDeleteSubscriptionEndpoint(svc service.WebhooksService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetWebhookSubscriptionRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = svc.DeleteSubscription(ctx, id); err != nil {
+			return DeleteOrderResponse{Deleted: "false"}, err
+		}
+
+		return DeleteOrderResponse{Deleted: "true"}, nil
+	}
+}
+
+func makeListDeliveriesEndpoint(svc service.WebhooksService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(GetWebhookSubscriptionRequest)
+
+		id, err := uuid.Parse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries, err := svc.ListDeliveries(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		out := WebhookDeliveryList{Deliveries: make([]WebhookDeliveryResponse, 0, len(deliveries))}
+		for _, d := range deliveries {
+			out.Deliveries = append(out.Deliveries, WebhookDeliveryResponseFromModel(d))
+		}
+
+		return out, nil
+	}
+}
@@ -12,9 +12,12 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	messages "github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
 	uuid "github.com/google/uuid"
+	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,17 +45,17 @@ func (m *MockCategoriesService) EXPECT() *MockCategoriesServiceMockRecorder {
 }
 
 // DeleteCategory mocks base method.
-func (m *MockCategoriesService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+func (m *MockCategoriesService) DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id, cascade)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCategory indicates an expected call of DeleteCategory.
-func (mr *MockCategoriesServiceMockRecorder) DeleteCategory(ctx, id any) *gomock.Call {
+func (mr *MockCategoriesServiceMockRecorder) DeleteCategory(ctx, id, cascade any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoriesService)(nil).DeleteCategory), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoriesService)(nil).DeleteCategory), ctx, id, cascade)
 }
 
 // GetCategory mocks base method.
@@ -70,6 +73,21 @@ func (mr *MockCategoriesServiceMockRecorder) GetCategory(ctx, id any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockCategoriesService)(nil).GetCategory), ctx, id)
 }
 
+// GetCategoryTree mocks base method.
+func (m *MockCategoriesService) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryTree", ctx)
+	ret0, _ := ret[0].([]*models.CategoryNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryTree indicates an expected call of GetCategoryTree.
+func (mr *MockCategoriesServiceMockRecorder) GetCategoryTree(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryTree", reflect.TypeOf((*MockCategoriesService)(nil).GetCategoryTree), ctx)
+}
+
 // InsertCategory mocks base method.
 func (m *MockCategoriesService) InsertCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
 	m.ctrl.T.Helper()
@@ -86,18 +104,48 @@ func (mr *MockCategoriesServiceMockRecorder) InsertCategory(ctx, in any) *gomock
 }
 
 // ListCategories mocks base method.
-func (m *MockCategoriesService) ListCategories(ctx context.Context, limit, offset int) (*models.CategoryList, error) {
+func (m *MockCategoriesService) ListCategories(ctx context.Context, page models.PageRequest) (*models.CategoryList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCategories", ctx, limit, offset)
+	ret := m.ctrl.Call(m, "ListCategories", ctx, page)
 	ret0, _ := ret[0].(*models.CategoryList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListCategories indicates an expected call of ListCategories.
-func (mr *MockCategoriesServiceMockRecorder) ListCategories(ctx, limit, offset any) *gomock.Call {
+func (mr *MockCategoriesServiceMockRecorder) ListCategories(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoriesService)(nil).ListCategories), ctx, page)
+}
+
+// SetCategoryWindow mocks base method.
+func (m *MockCategoriesService) SetCategoryWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCategoryWindow", ctx, id, window)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCategoryWindow indicates an expected call of SetCategoryWindow.
+func (mr *MockCategoriesServiceMockRecorder) SetCategoryWindow(ctx, id, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategoryWindow", reflect.TypeOf((*MockCategoriesService)(nil).SetCategoryWindow), ctx, id, window)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockCategoriesService) UpdateCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, in)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockCategoriesServiceMockRecorder) UpdateCategory(ctx, in any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoriesService)(nil).ListCategories), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCategoriesService)(nil).UpdateCategory), ctx, in)
 }
 
 // MockProductsService is a mock of ProductsService interface.
@@ -123,6 +171,36 @@ func (m *MockProductsService) EXPECT() *MockProductsServiceMockRecorder {
 	return m.recorder
 }
 
+// ApplyScheduledPrices mocks base method.
+func (m *MockProductsService) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyScheduledPrices", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyScheduledPrices indicates an expected call of ApplyScheduledPrices.
+func (mr *MockProductsServiceMockRecorder) ApplyScheduledPrices(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyScheduledPrices", reflect.TypeOf((*MockProductsService)(nil).ApplyScheduledPrices), ctx)
+}
+
+// CancelScheduledPrice mocks base method.
+func (m *MockProductsService) CancelScheduledPrice(ctx context.Context, productID, priceID uuid.UUID) (*models.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledPrice", ctx, productID, priceID)
+	ret0, _ := ret[0].(*models.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledPrice indicates an expected call of CancelScheduledPrice.
+func (mr *MockProductsServiceMockRecorder) CancelScheduledPrice(ctx, productID, priceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledPrice", reflect.TypeOf((*MockProductsService)(nil).CancelScheduledPrice), ctx, productID, priceID)
+}
+
 // DeleteProduct mocks base method.
 func (m *MockProductsService) DeleteProduct(ctx context.Context, uuid uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -182,19 +260,109 @@ func (mr *MockProductsServiceMockRecorder) InsertProduct(ctx, product any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProduct", reflect.TypeOf((*MockProductsService)(nil).InsertProduct), ctx, product)
 }
 
+// ListPriceHistory mocks base method.
+func (m *MockProductsService) ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceHistory", ctx, productID)
+	ret0, _ := ret[0].([]*models.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriceHistory indicates an expected call of ListPriceHistory.
+func (mr *MockProductsServiceMockRecorder) ListPriceHistory(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceHistory", reflect.TypeOf((*MockProductsService)(nil).ListPriceHistory), ctx, productID)
+}
+
 // ListProductsByCategory mocks base method.
-func (m *MockProductsService) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) (*models.ProductList, error) {
+func (m *MockProductsService) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, withDescendants bool, page models.PageRequest) (*models.ProductList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListProductsByCategory", ctx, categoryID, limit, offset)
+	ret := m.ctrl.Call(m, "ListProductsByCategory", ctx, categoryID, withDescendants, page)
 	ret0, _ := ret[0].(*models.ProductList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListProductsByCategory indicates an expected call of ListProductsByCategory.
-func (mr *MockProductsServiceMockRecorder) ListProductsByCategory(ctx, categoryID, limit, offset any) *gomock.Call {
+func (mr *MockProductsServiceMockRecorder) ListProductsByCategory(ctx, categoryID, withDescendants, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProductsByCategory", reflect.TypeOf((*MockProductsService)(nil).ListProductsByCategory), ctx, categoryID, withDescendants, page)
+}
+
+// RestoreProduct mocks base method.
+func (m *MockProductsService) RestoreProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreProduct", ctx, id)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreProduct indicates an expected call of RestoreProduct.
+func (mr *MockProductsServiceMockRecorder) RestoreProduct(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreProduct", reflect.TypeOf((*MockProductsService)(nil).RestoreProduct), ctx, id)
+}
+
+// SchedulePrice mocks base method.
+func (m *MockProductsService) SchedulePrice(ctx context.Context, productID uuid.UUID, price decimal.Decimal, effectiveAt time.Time) (*models.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePrice", ctx, productID, price, effectiveAt)
+	ret0, _ := ret[0].(*models.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePrice indicates an expected call of SchedulePrice.
+func (mr *MockProductsServiceMockRecorder) SchedulePrice(ctx, productID, price, effectiveAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePrice", reflect.TypeOf((*MockProductsService)(nil).SchedulePrice), ctx, productID, price, effectiveAt)
+}
+
+// SearchProducts mocks base method.
+func (m *MockProductsService) SearchProducts(ctx context.Context, in models.ProductSearch) (*models.ProductList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProducts", ctx, in)
+	ret0, _ := ret[0].(*models.ProductList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProducts indicates an expected call of SearchProducts.
+func (mr *MockProductsServiceMockRecorder) SearchProducts(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProducts", reflect.TypeOf((*MockProductsService)(nil).SearchProducts), ctx, in)
+}
+
+// SetProductAvailability mocks base method.
+func (m *MockProductsService) SetProductAvailability(ctx context.Context, id uuid.UUID, available bool, until time.Time) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductAvailability", ctx, id, available, until)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProductAvailability indicates an expected call of SetProductAvailability.
+func (mr *MockProductsServiceMockRecorder) SetProductAvailability(ctx, id, available, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductAvailability", reflect.TypeOf((*MockProductsService)(nil).SetProductAvailability), ctx, id, available, until)
+}
+
+// SetProductWindow mocks base method.
+func (m *MockProductsService) SetProductWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductWindow", ctx, id, window)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProductWindow indicates an expected call of SetProductWindow.
+func (mr *MockProductsServiceMockRecorder) SetProductWindow(ctx, id, window any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProductsByCategory", reflect.TypeOf((*MockProductsService)(nil).ListProductsByCategory), ctx, categoryID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductWindow", reflect.TypeOf((*MockProductsService)(nil).SetProductWindow), ctx, id, window)
 }
 
 // UpdateProduct mocks base method.
@@ -212,224 +380,1055 @@ func (mr *MockProductsServiceMockRecorder) UpdateProduct(ctx, product any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductsService)(nil).UpdateProduct), ctx, product)
 }
 
-// MockOrdersService is a mock of OrdersService interface.
-type MockOrdersService struct {
+// MockInventoryService is a mock of InventoryService interface.
+type MockInventoryService struct {
 	ctrl     *gomock.Controller
-	recorder *MockOrdersServiceMockRecorder
+	recorder *MockInventoryServiceMockRecorder
 }
 
-// MockOrdersServiceMockRecorder is the mock recorder for MockOrdersService.
-type MockOrdersServiceMockRecorder struct {
-	mock *MockOrdersService
+// MockInventoryServiceMockRecorder is the mock recorder for MockInventoryService.
+type MockInventoryServiceMockRecorder struct {
+	mock *MockInventoryService
 }
 
-// NewMockOrdersService creates a new mock instance.
-func NewMockOrdersService(ctrl *gomock.Controller) *MockOrdersService {
-	mock := &MockOrdersService{ctrl: ctrl}
-	mock.recorder = &MockOrdersServiceMockRecorder{mock}
+// NewMockInventoryService creates a new mock instance.
+func NewMockInventoryService(ctrl *gomock.Controller) *MockInventoryService {
+	mock := &MockInventoryService{ctrl: ctrl}
+	mock.recorder = &MockInventoryServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockOrdersService) EXPECT() *MockOrdersServiceMockRecorder {
+func (m *MockInventoryService) EXPECT() *MockInventoryServiceMockRecorder {
 	return m.recorder
 }
 
-// Checkout mocks base method.
-func (m *MockOrdersService) Checkout(ctx context.Context, paymentID uuid.UUID) (*models.Order, error) {
+// AdjustStock mocks base method.
+func (m *MockInventoryService) AdjustStock(ctx context.Context, id uuid.UUID, delta decimal.Decimal, reason, actor string) (*models.Ingredient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Checkout", ctx, paymentID)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "AdjustStock", ctx, id, delta, reason, actor)
+	ret0, _ := ret[0].(*models.Ingredient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Checkout indicates an expected call of Checkout.
-func (mr *MockOrdersServiceMockRecorder) Checkout(ctx, paymentID any) *gomock.Call {
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockInventoryServiceMockRecorder) AdjustStock(ctx, id, delta, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockOrdersService)(nil).Checkout), ctx, paymentID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockInventoryService)(nil).AdjustStock), ctx, id, delta, reason, actor)
 }
 
-// CreateOrder mocks base method.
-func (m *MockOrdersService) CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID) (*models.Order, error) {
+// CommitOrder mocks base method.
+func (m *MockInventoryService) CommitOrder(ctx context.Context, orderID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", ctx, products, userID)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "CommitOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitOrder indicates an expected call of CommitOrder.
+func (mr *MockInventoryServiceMockRecorder) CommitOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitOrder", reflect.TypeOf((*MockInventoryService)(nil).CommitOrder), ctx, orderID)
+}
+
+// CreateIngredient mocks base method.
+func (m *MockInventoryService) CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngredient", ctx, ingredient)
+	ret0, _ := ret[0].(*models.Ingredient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockOrdersServiceMockRecorder) CreateOrder(ctx, products, userID any) *gomock.Call {
+// CreateIngredient indicates an expected call of CreateIngredient.
+func (mr *MockInventoryServiceMockRecorder) CreateIngredient(ctx, ingredient any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrdersService)(nil).CreateOrder), ctx, products, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngredient", reflect.TypeOf((*MockInventoryService)(nil).CreateIngredient), ctx, ingredient)
 }
 
-// DeleteOrder mocks base method.
-func (m *MockOrdersService) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
+// GetIngredient mocks base method.
+func (m *MockInventoryService) GetIngredient(ctx context.Context, id uuid.UUID) (*models.Ingredient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOrder", ctx, orderID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetIngredient", ctx, id)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteOrder indicates an expected call of DeleteOrder.
-func (mr *MockOrdersServiceMockRecorder) DeleteOrder(ctx, orderID any) *gomock.Call {
+// GetIngredient indicates an expected call of GetIngredient.
+func (mr *MockInventoryServiceMockRecorder) GetIngredient(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrdersService)(nil).DeleteOrder), ctx, orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredient", reflect.TypeOf((*MockInventoryService)(nil).GetIngredient), ctx, id)
 }
 
-// GetOrder mocks base method.
-func (m *MockOrdersService) GetOrder(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+// GetRecipe mocks base method.
+func (m *MockInventoryService) GetRecipe(ctx context.Context, productID uuid.UUID) ([]models.RecipeItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "GetRecipe", ctx, productID)
+	ret0, _ := ret[0].([]models.RecipeItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrder indicates an expected call of GetOrder.
-func (mr *MockOrdersServiceMockRecorder) GetOrder(ctx, orderID any) *gomock.Call {
+// GetRecipe indicates an expected call of GetRecipe.
+func (mr *MockInventoryServiceMockRecorder) GetRecipe(ctx, productID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrdersService)(nil).GetOrder), ctx, orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipe", reflect.TypeOf((*MockInventoryService)(nil).GetRecipe), ctx, productID)
 }
 
-// GetOrderByPaymentID mocks base method.
-func (m *MockOrdersService) GetOrderByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Order, error) {
+// ListIngredients mocks base method.
+func (m *MockInventoryService) ListIngredients(ctx context.Context) ([]*models.Ingredient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderByPaymentID", ctx, paymentID)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "ListIngredients", ctx)
+	ret0, _ := ret[0].([]*models.Ingredient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrderByPaymentID indicates an expected call of GetOrderByPaymentID.
-func (mr *MockOrdersServiceMockRecorder) GetOrderByPaymentID(ctx, paymentID any) *gomock.Call {
+// ListIngredients indicates an expected call of ListIngredients.
+func (mr *MockInventoryServiceMockRecorder) ListIngredients(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByPaymentID", reflect.TypeOf((*MockOrdersService)(nil).GetOrderByPaymentID), ctx, paymentID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngredients", reflect.TypeOf((*MockInventoryService)(nil).ListIngredients), ctx)
 }
 
-// ListOrders mocks base method.
-func (m *MockOrdersService) ListOrders(ctx context.Context, limit, offset int) (*models.OrderList, error) {
+// ListStockMovements mocks base method.
+func (m *MockInventoryService) ListStockMovements(ctx context.Context, ingredientID uuid.UUID, limit int) ([]*models.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListOrders", ctx, limit, offset)
-	ret0, _ := ret[0].(*models.OrderList)
+	ret := m.ctrl.Call(m, "ListStockMovements", ctx, ingredientID, limit)
+	ret0, _ := ret[0].([]*models.StockMovement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListOrders indicates an expected call of ListOrders.
-func (mr *MockOrdersServiceMockRecorder) ListOrders(ctx, limit, offset any) *gomock.Call {
+// ListStockMovements indicates an expected call of ListStockMovements.
+func (mr *MockInventoryServiceMockRecorder) ListStockMovements(ctx, ingredientID, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrdersService)(nil).ListOrders), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockMovements", reflect.TypeOf((*MockInventoryService)(nil).ListStockMovements), ctx, ingredientID, limit)
 }
 
-// ProcessPayment mocks base method.
-func (m *MockOrdersService) ProcessPayment() {
+// ReleaseOrder mocks base method.
+func (m *MockInventoryService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ProcessPayment")
+	ret := m.ctrl.Call(m, "ReleaseOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ProcessPayment indicates an expected call of ProcessPayment.
-func (mr *MockOrdersServiceMockRecorder) ProcessPayment() *gomock.Call {
+// ReleaseOrder indicates an expected call of ReleaseOrder.
+func (mr *MockInventoryServiceMockRecorder) ReleaseOrder(ctx, orderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPayment", reflect.TypeOf((*MockOrdersService)(nil).ProcessPayment))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOrder", reflect.TypeOf((*MockInventoryService)(nil).ReleaseOrder), ctx, orderID)
 }
 
-// UpdateOrderItems mocks base method.
-func (m *MockOrdersService) UpdateOrderItems(ctx context.Context, orderID uuid.UUID, products []models.Product) (*models.Order, error) {
+// ReserveOrder mocks base method.
+func (m *MockInventoryService) ReserveOrder(ctx context.Context, order *models.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateOrderItems", ctx, orderID, products)
-	ret0, _ := ret[0].(*models.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ReserveOrder", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// UpdateOrderItems indicates an expected call of UpdateOrderItems.
-func (mr *MockOrdersServiceMockRecorder) UpdateOrderItems(ctx, orderID, products any) *gomock.Call {
+// ReserveOrder indicates an expected call of ReserveOrder.
+func (mr *MockInventoryServiceMockRecorder) ReserveOrder(ctx, order any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderItems", reflect.TypeOf((*MockOrdersService)(nil).UpdateOrderItems), ctx, orderID, products)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveOrder", reflect.TypeOf((*MockInventoryService)(nil).ReserveOrder), ctx, order)
 }
 
-// UpdateOrderStatus mocks base method.
-func (m *MockOrdersService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status models.OrderStatus) (*models.Order, error) {
+// SetRecipe mocks base method.
+func (m *MockInventoryService) SetRecipe(ctx context.Context, productID uuid.UUID, items []models.RecipeItem) ([]models.RecipeItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, orderID, status)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "SetRecipe", ctx, productID, items)
+	ret0, _ := ret[0].([]models.RecipeItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
-func (mr *MockOrdersServiceMockRecorder) UpdateOrderStatus(ctx, orderID, status any) *gomock.Call {
+// SetRecipe indicates an expected call of SetRecipe.
+func (mr *MockInventoryServiceMockRecorder) SetRecipe(ctx, productID, items any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrdersService)(nil).UpdateOrderStatus), ctx, orderID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecipe", reflect.TypeOf((*MockInventoryService)(nil).SetRecipe), ctx, productID, items)
 }
 
-// MockPaymentsService is a mock of PaymentsService interface.
-type MockPaymentsService struct {
+// MockStoreHoursService is a mock of StoreHoursService interface.
+type MockStoreHoursService struct {
 	ctrl     *gomock.Controller
-	recorder *MockPaymentsServiceMockRecorder
+	recorder *MockStoreHoursServiceMockRecorder
 }
 
-// MockPaymentsServiceMockRecorder is the mock recorder for MockPaymentsService.
-type MockPaymentsServiceMockRecorder struct {
-	mock *MockPaymentsService
+// MockStoreHoursServiceMockRecorder is the mock recorder for MockStoreHoursService.
+type MockStoreHoursServiceMockRecorder struct {
+	mock *MockStoreHoursService
 }
 
-// NewMockPaymentsService creates a new mock instance.
-func NewMockPaymentsService(ctrl *gomock.Controller) *MockPaymentsService {
-	mock := &MockPaymentsService{ctrl: ctrl}
-	mock.recorder = &MockPaymentsServiceMockRecorder{mock}
+// NewMockStoreHoursService creates a new mock instance.
+func NewMockStoreHoursService(ctrl *gomock.Controller) *MockStoreHoursService {
+	mock := &MockStoreHoursService{ctrl: ctrl}
+	mock.recorder = &MockStoreHoursServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockPaymentsService) EXPECT() *MockPaymentsServiceMockRecorder {
+func (m *MockStoreHoursService) EXPECT() *MockStoreHoursServiceMockRecorder {
 	return m.recorder
 }
 
-// CreatePayment mocks base method.
-func (m *MockPaymentsService) CreatePayment(ctx context.Context, orderID *models.Order) (*models.Payment, error) {
+// DeleteHoursException mocks base method.
+func (m *MockStoreHoursService) DeleteHoursException(ctx context.Context, day time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePayment", ctx, orderID)
-	ret0, _ := ret[0].(*models.Payment)
+	ret := m.ctrl.Call(m, "DeleteHoursException", ctx, day)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHoursException indicates an expected call of DeleteHoursException.
+func (mr *MockStoreHoursServiceMockRecorder) DeleteHoursException(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHoursException", reflect.TypeOf((*MockStoreHoursService)(nil).DeleteHoursException), ctx, day)
+}
+
+// EnsureOpen mocks base method.
+func (m *MockStoreHoursService) EnsureOpen(ctx context.Context, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureOpen", ctx, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureOpen indicates an expected call of EnsureOpen.
+func (mr *MockStoreHoursServiceMockRecorder) EnsureOpen(ctx, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureOpen", reflect.TypeOf((*MockStoreHoursService)(nil).EnsureOpen), ctx, at)
+}
+
+// GetSchedule mocks base method.
+func (m *MockStoreHoursService) GetSchedule(ctx context.Context) (*models.StoreSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx)
+	ret0, _ := ret[0].(*models.StoreSchedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreatePayment indicates an expected call of CreatePayment.
-func (mr *MockPaymentsServiceMockRecorder) CreatePayment(ctx, orderID any) *gomock.Call {
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockStoreHoursServiceMockRecorder) GetSchedule(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentsService)(nil).CreatePayment), ctx, orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockStoreHoursService)(nil).GetSchedule), ctx)
 }
 
-// GetPayment mocks base method.
-func (m *MockPaymentsService) GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+// SetBusinessHours mocks base method.
+func (m *MockStoreHoursService) SetBusinessHours(ctx context.Context, hours []models.BusinessHours) (*models.StoreSchedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPayment", ctx, paymentID)
-	ret0, _ := ret[0].(*models.Payment)
+	ret := m.ctrl.Call(m, "SetBusinessHours", ctx, hours)
+	ret0, _ := ret[0].(*models.StoreSchedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPayment indicates an expected call of GetPayment.
-func (mr *MockPaymentsServiceMockRecorder) GetPayment(ctx, paymentID any) *gomock.Call {
+// SetBusinessHours indicates an expected call of SetBusinessHours.
+func (mr *MockStoreHoursServiceMockRecorder) SetBusinessHours(ctx, hours any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPaymentsService)(nil).GetPayment), ctx, paymentID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBusinessHours", reflect.TypeOf((*MockStoreHoursService)(nil).SetBusinessHours), ctx, hours)
 }
 
-// UpdatePayment mocks base method.
-func (m *MockPaymentsService) UpdatePayment(ctx context.Context, paymentID uuid.UUID, status models.PaymentStatus) (*models.Payment, error) {
+// SetHoursException mocks base method.
+func (m *MockStoreHoursService) SetHoursException(ctx context.Context, exception models.HoursException) (*models.StoreSchedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePayment", ctx, paymentID, status)
-	ret0, _ := ret[0].(*models.Payment)
+	ret := m.ctrl.Call(m, "SetHoursException", ctx, exception)
+	ret0, _ := ret[0].(*models.StoreSchedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdatePayment indicates an expected call of UpdatePayment.
-func (mr *MockPaymentsServiceMockRecorder) UpdatePayment(ctx, paymentID, status any) *gomock.Call {
+// SetHoursException indicates an expected call of SetHoursException.
+func (mr *MockStoreHoursServiceMockRecorder) SetHoursException(ctx, exception any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockPaymentsService)(nil).UpdatePayment), ctx, paymentID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHoursException", reflect.TypeOf((*MockStoreHoursService)(nil).SetHoursException), ctx, exception)
+}
+
+// Status mocks base method.
+func (m *MockStoreHoursService) Status(ctx context.Context, at time.Time) (models.StoreStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, at)
+	ret0, _ := ret[0].(models.StoreStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockStoreHoursServiceMockRecorder) Status(ctx, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockStoreHoursService)(nil).Status), ctx, at)
+}
+
+// MockMenuService is a mock of MenuService interface.
+type MockMenuService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMenuServiceMockRecorder
+}
+
+// MockMenuServiceMockRecorder is the mock recorder for MockMenuService.
+type MockMenuServiceMockRecorder struct {
+	mock *MockMenuService
+}
+
+// NewMockMenuService creates a new mock instance.
+func NewMockMenuService(ctrl *gomock.Controller) *MockMenuService {
+	mock := &MockMenuService{ctrl: ctrl}
+	mock.recorder = &MockMenuServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMenuService) EXPECT() *MockMenuServiceMockRecorder {
+	return m.recorder
+}
+
+// CatalogChanged mocks base method.
+func (m *MockMenuService) CatalogChanged() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CatalogChanged")
+}
+
+// CatalogChanged indicates an expected call of CatalogChanged.
+func (mr *MockMenuServiceMockRecorder) CatalogChanged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CatalogChanged", reflect.TypeOf((*MockMenuService)(nil).CatalogChanged))
+}
+
+// GetMenu mocks base method.
+func (m *MockMenuService) GetMenu(ctx context.Context) (*models.Menu, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMenu", ctx)
+	ret0, _ := ret[0].(*models.Menu)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMenu indicates an expected call of GetMenu.
+func (mr *MockMenuServiceMockRecorder) GetMenu(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMenu", reflect.TypeOf((*MockMenuService)(nil).GetMenu), ctx)
+}
+
+// MockOrdersService is a mock of OrdersService interface.
+type MockOrdersService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrdersServiceMockRecorder
+}
+
+// MockOrdersServiceMockRecorder is the mock recorder for MockOrdersService.
+type MockOrdersServiceMockRecorder struct {
+	mock *MockOrdersService
+}
+
+// NewMockOrdersService creates a new mock instance.
+func NewMockOrdersService(ctrl *gomock.Controller) *MockOrdersService {
+	mock := &MockOrdersService{ctrl: ctrl}
+	mock.recorder = &MockOrdersServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrdersService) EXPECT() *MockOrdersServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelOrder mocks base method.
+func (m *MockOrdersService) CancelOrder(ctx context.Context, orderID uuid.UUID, reason models.CancelReason, actor string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderID, reason, actor)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrdersServiceMockRecorder) CancelOrder(ctx, orderID, reason, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrdersService)(nil).CancelOrder), ctx, orderID, reason, actor)
+}
+
+// Checkout mocks base method.
+func (m *MockOrdersService) Checkout(ctx context.Context, paymentID uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, paymentID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockOrdersServiceMockRecorder) Checkout(ctx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockOrdersService)(nil).Checkout), ctx, paymentID)
+}
+
+// CreateOrder mocks base method.
+func (m *MockOrdersService) CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID, customerName string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, products, userID, customerName)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockOrdersServiceMockRecorder) CreateOrder(ctx, products, userID, customerName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrdersService)(nil).CreateOrder), ctx, products, userID, customerName)
+}
+
+// DeleteOrder mocks base method.
+func (m *MockOrdersService) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrder indicates an expected call of DeleteOrder.
+func (mr *MockOrdersServiceMockRecorder) DeleteOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrdersService)(nil).DeleteOrder), ctx, orderID)
+}
+
+// ExpireAbandonedOrders mocks base method.
+func (m *MockOrdersService) ExpireAbandonedOrders(ctx context.Context, openTTL, paymentTimeout time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAbandonedOrders", ctx, openTTL, paymentTimeout)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAbandonedOrders indicates an expected call of ExpireAbandonedOrders.
+func (mr *MockOrdersServiceMockRecorder) ExpireAbandonedOrders(ctx, openTTL, paymentTimeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAbandonedOrders", reflect.TypeOf((*MockOrdersService)(nil).ExpireAbandonedOrders), ctx, openTTL, paymentTimeout)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrdersService) GetOrder(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrdersServiceMockRecorder) GetOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrdersService)(nil).GetOrder), ctx, orderID)
+}
+
+// GetOrderByPaymentID mocks base method.
+func (m *MockOrdersService) GetOrderByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByPaymentID", ctx, paymentID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByPaymentID indicates an expected call of GetOrderByPaymentID.
+func (mr *MockOrdersServiceMockRecorder) GetOrderByPaymentID(ctx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByPaymentID", reflect.TypeOf((*MockOrdersService)(nil).GetOrderByPaymentID), ctx, paymentID)
+}
+
+// GetOrderByPickupNumber mocks base method.
+func (m *MockOrdersService) GetOrderByPickupNumber(ctx context.Context, number int, day time.Time) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByPickupNumber", ctx, number, day)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByPickupNumber indicates an expected call of GetOrderByPickupNumber.
+func (mr *MockOrdersServiceMockRecorder) GetOrderByPickupNumber(ctx, number, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByPickupNumber", reflect.TypeOf((*MockOrdersService)(nil).GetOrderByPickupNumber), ctx, number, day)
+}
+
+// GetPickupPanel mocks base method.
+func (m *MockOrdersService) GetPickupPanel(ctx context.Context) (*models.PickupPanel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPickupPanel", ctx)
+	ret0, _ := ret[0].(*models.PickupPanel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPickupPanel indicates an expected call of GetPickupPanel.
+func (mr *MockOrdersServiceMockRecorder) GetPickupPanel(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPickupPanel", reflect.TypeOf((*MockOrdersService)(nil).GetPickupPanel), ctx)
+}
+
+// ListOrderPayments mocks base method.
+func (m *MockOrdersService) ListOrderPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderPayments", ctx, orderID)
+	ret0, _ := ret[0].([]*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderPayments indicates an expected call of ListOrderPayments.
+func (mr *MockOrdersServiceMockRecorder) ListOrderPayments(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderPayments", reflect.TypeOf((*MockOrdersService)(nil).ListOrderPayments), ctx, orderID)
+}
+
+// ListOrderRefunds mocks base method.
+func (m *MockOrdersService) ListOrderRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderRefunds", ctx, orderID)
+	ret0, _ := ret[0].([]*models.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderRefunds indicates an expected call of ListOrderRefunds.
+func (mr *MockOrdersServiceMockRecorder) ListOrderRefunds(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderRefunds", reflect.TypeOf((*MockOrdersService)(nil).ListOrderRefunds), ctx, orderID)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrdersService) ListOrders(ctx context.Context, page models.PageRequest) (*models.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, page)
+	ret0, _ := ret[0].(*models.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrdersServiceMockRecorder) ListOrders(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrdersService)(nil).ListOrders), ctx, page)
+}
+
+// ProcessPaymentStatusChange mocks base method.
+func (m *MockOrdersService) ProcessPaymentStatusChange(ctx context.Context, in messages.PaymentStatusChangedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPaymentStatusChange", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessPaymentStatusChange indicates an expected call of ProcessPaymentStatusChange.
+func (mr *MockOrdersServiceMockRecorder) ProcessPaymentStatusChange(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPaymentStatusChange", reflect.TypeOf((*MockOrdersService)(nil).ProcessPaymentStatusChange), ctx, in)
+}
+
+// RefundOrder mocks base method.
+func (m *MockOrdersService) RefundOrder(ctx context.Context, orderID, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundOrder", ctx, orderID, paymentID, items, reason)
+	ret0, _ := ret[0].(*models.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundOrder indicates an expected call of RefundOrder.
+func (mr *MockOrdersServiceMockRecorder) RefundOrder(ctx, orderID, paymentID, items, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundOrder", reflect.TypeOf((*MockOrdersService)(nil).RefundOrder), ctx, orderID, paymentID, items, reason)
+}
+
+// RetryPayment mocks base method.
+func (m *MockOrdersService) RetryPayment(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryPayment", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryPayment indicates an expected call of RetryPayment.
+func (mr *MockOrdersServiceMockRecorder) RetryPayment(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryPayment", reflect.TypeOf((*MockOrdersService)(nil).RetryPayment), ctx, orderID)
+}
+
+// SearchOrders mocks base method.
+func (m *MockOrdersService) SearchOrders(ctx context.Context, in models.OrderSearch) (*models.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchOrders", ctx, in)
+	ret0, _ := ret[0].(*models.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchOrders indicates an expected call of SearchOrders.
+func (mr *MockOrdersServiceMockRecorder) SearchOrders(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchOrders", reflect.TypeOf((*MockOrdersService)(nil).SearchOrders), ctx, in)
+}
+
+// SplitCheckout mocks base method.
+func (m *MockOrdersService) SplitCheckout(ctx context.Context, orderID uuid.UUID, parts []models.PaymentPart) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitCheckout", ctx, orderID, parts)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitCheckout indicates an expected call of SplitCheckout.
+func (mr *MockOrdersServiceMockRecorder) SplitCheckout(ctx, orderID, parts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitCheckout", reflect.TypeOf((*MockOrdersService)(nil).SplitCheckout), ctx, orderID, parts)
+}
+
+// SubscribeToPaymentUpdates mocks base method.
+func (m *MockOrdersService) SubscribeToPaymentUpdates() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SubscribeToPaymentUpdates")
+}
+
+// SubscribeToPaymentUpdates indicates an expected call of SubscribeToPaymentUpdates.
+func (mr *MockOrdersServiceMockRecorder) SubscribeToPaymentUpdates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToPaymentUpdates", reflect.TypeOf((*MockOrdersService)(nil).SubscribeToPaymentUpdates))
+}
+
+// SubscribeToProductionUpdates mocks base method.
+func (m *MockOrdersService) SubscribeToProductionUpdates() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SubscribeToProductionUpdates")
+}
+
+// SubscribeToProductionUpdates indicates an expected call of SubscribeToProductionUpdates.
+func (mr *MockOrdersServiceMockRecorder) SubscribeToProductionUpdates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToProductionUpdates", reflect.TypeOf((*MockOrdersService)(nil).SubscribeToProductionUpdates))
+}
+
+// UpdateOrderAdjustments mocks base method.
+func (m *MockOrdersService) UpdateOrderAdjustments(ctx context.Context, orderID uuid.UUID, tip *models.Adjustment, serviceCharge *bool) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderAdjustments", ctx, orderID, tip, serviceCharge)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderAdjustments indicates an expected call of UpdateOrderAdjustments.
+func (mr *MockOrdersServiceMockRecorder) UpdateOrderAdjustments(ctx, orderID, tip, serviceCharge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderAdjustments", reflect.TypeOf((*MockOrdersService)(nil).UpdateOrderAdjustments), ctx, orderID, tip, serviceCharge)
+}
+
+// UpdateOrderItems mocks base method.
+func (m *MockOrdersService) UpdateOrderItems(ctx context.Context, orderID uuid.UUID, products []models.Product) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderItems", ctx, orderID, products)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderItems indicates an expected call of UpdateOrderItems.
+func (mr *MockOrdersServiceMockRecorder) UpdateOrderItems(ctx, orderID, products any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderItems", reflect.TypeOf((*MockOrdersService)(nil).UpdateOrderItems), ctx, orderID, products)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrdersService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status models.OrderStatus) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, orderID, status)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrdersServiceMockRecorder) UpdateOrderStatus(ctx, orderID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrdersService)(nil).UpdateOrderStatus), ctx, orderID, status)
+}
+
+// MockPaymentsService is a mock of PaymentsService interface.
+type MockPaymentsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentsServiceMockRecorder
+}
+
+// MockPaymentsServiceMockRecorder is the mock recorder for MockPaymentsService.
+type MockPaymentsServiceMockRecorder struct {
+	mock *MockPaymentsService
+}
+
+// NewMockPaymentsService creates a new mock instance.
+func NewMockPaymentsService(ctrl *gomock.Controller) *MockPaymentsService {
+	mock := &MockPaymentsService{ctrl: ctrl}
+	mock.recorder = &MockPaymentsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentsService) EXPECT() *MockPaymentsServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelPayment mocks base method.
+func (m *MockPaymentsService) CancelPayment(ctx context.Context, paymentID uuid.UUID, reason string) (*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPayment", ctx, paymentID, reason)
+	ret0, _ := ret[0].(*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPayment indicates an expected call of CancelPayment.
+func (mr *MockPaymentsServiceMockRecorder) CancelPayment(ctx, paymentID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPayment", reflect.TypeOf((*MockPaymentsService)(nil).CancelPayment), ctx, paymentID, reason)
+}
+
+// ChangePaymentStatus mocks base method.
+func (m *MockPaymentsService) ChangePaymentStatus(ctx context.Context, payment *models.Payment, status models.PaymentStatus) (*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePaymentStatus", ctx, payment, status)
+	ret0, _ := ret[0].(*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePaymentStatus indicates an expected call of ChangePaymentStatus.
+func (mr *MockPaymentsServiceMockRecorder) ChangePaymentStatus(ctx, payment, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePaymentStatus", reflect.TypeOf((*MockPaymentsService)(nil).ChangePaymentStatus), ctx, payment, status)
+}
+
+// CreatePayment mocks base method.
+func (m *MockPaymentsService) CreatePayment(ctx context.Context, orderID *models.Order) (*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, orderID)
+	ret0, _ := ret[0].(*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPaymentsServiceMockRecorder) CreatePayment(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentsService)(nil).CreatePayment), ctx, orderID)
+}
+
+// CreateSplitPayments mocks base method.
+func (m *MockPaymentsService) CreateSplitPayments(ctx context.Context, order *models.Order, parts []models.PaymentPart) ([]*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSplitPayments", ctx, order, parts)
+	ret0, _ := ret[0].([]*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSplitPayments indicates an expected call of CreateSplitPayments.
+func (mr *MockPaymentsServiceMockRecorder) CreateSplitPayments(ctx, order, parts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSplitPayments", reflect.TypeOf((*MockPaymentsService)(nil).CreateSplitPayments), ctx, order, parts)
+}
+
+// GetPayment mocks base method.
+func (m *MockPaymentsService) GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayment", ctx, paymentID)
+	ret0, _ := ret[0].(*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockPaymentsServiceMockRecorder) GetPayment(ctx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPaymentsService)(nil).GetPayment), ctx, paymentID)
+}
+
+// ListPayments mocks base method.
+func (m *MockPaymentsService) ListPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, orderID)
+	ret0, _ := ret[0].([]*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockPaymentsServiceMockRecorder) ListPayments(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockPaymentsService)(nil).ListPayments), ctx, orderID)
+}
+
+// ListRefunds mocks base method.
+func (m *MockPaymentsService) ListRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefunds", ctx, orderID)
+	ret0, _ := ret[0].([]*models.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRefunds indicates an expected call of ListRefunds.
+func (mr *MockPaymentsServiceMockRecorder) ListRefunds(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefunds", reflect.TypeOf((*MockPaymentsService)(nil).ListRefunds), ctx, orderID)
+}
+
+// ListSplitPayments mocks base method.
+func (m *MockPaymentsService) ListSplitPayments(ctx context.Context, splitID uuid.UUID) ([]*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSplitPayments", ctx, splitID)
+	ret0, _ := ret[0].([]*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSplitPayments indicates an expected call of ListSplitPayments.
+func (mr *MockPaymentsServiceMockRecorder) ListSplitPayments(ctx, splitID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSplitPayments", reflect.TypeOf((*MockPaymentsService)(nil).ListSplitPayments), ctx, splitID)
+}
+
+// ListStalePayments mocks base method.
+func (m *MockPaymentsService) ListStalePayments(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStalePayments", ctx, olderThan, limit)
+	ret0, _ := ret[0].([]*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStalePayments indicates an expected call of ListStalePayments.
+func (mr *MockPaymentsServiceMockRecorder) ListStalePayments(ctx, olderThan, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStalePayments", reflect.TypeOf((*MockPaymentsService)(nil).ListStalePayments), ctx, olderThan, limit)
+}
+
+// PixPayload mocks base method.
+func (m *MockPaymentsService) PixPayload(payment *models.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PixPayload", payment)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PixPayload indicates an expected call of PixPayload.
+func (mr *MockPaymentsServiceMockRecorder) PixPayload(payment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PixPayload", reflect.TypeOf((*MockPaymentsService)(nil).PixPayload), payment)
+}
+
+// RequestRefund mocks base method.
+func (m *MockPaymentsService) RequestRefund(ctx context.Context, order *models.Order, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestRefund", ctx, order, paymentID, items, reason)
+	ret0, _ := ret[0].(*models.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestRefund indicates an expected call of RequestRefund.
+func (mr *MockPaymentsServiceMockRecorder) RequestRefund(ctx, order, paymentID, items, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestRefund", reflect.TypeOf((*MockPaymentsService)(nil).RequestRefund), ctx, order, paymentID, items, reason)
+}
+
+// SubscribeToRefundUpdates mocks base method.
+func (m *MockPaymentsService) SubscribeToRefundUpdates() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SubscribeToRefundUpdates")
+}
+
+// SubscribeToRefundUpdates indicates an expected call of SubscribeToRefundUpdates.
+func (mr *MockPaymentsServiceMockRecorder) SubscribeToRefundUpdates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToRefundUpdates", reflect.TypeOf((*MockPaymentsService)(nil).SubscribeToRefundUpdates))
+}
+
+// UpdatePayment mocks base method.
+func (m *MockPaymentsService) UpdatePayment(ctx context.Context, paymentID uuid.UUID, status models.PaymentStatus) (*models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayment", ctx, paymentID, status)
+	ret0, _ := ret[0].(*models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePayment indicates an expected call of UpdatePayment.
+func (mr *MockPaymentsServiceMockRecorder) UpdatePayment(ctx, paymentID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockPaymentsService)(nil).UpdatePayment), ctx, paymentID, status)
+}
+
+// MockWebhooksService is a mock of WebhooksService interface.
+type MockWebhooksService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksServiceMockRecorder
+}
+
+// MockWebhooksServiceMockRecorder is the mock recorder for MockWebhooksService.
+type MockWebhooksServiceMockRecorder struct {
+	mock *MockWebhooksService
+}
+
+// NewMockWebhooksService creates a new mock instance.
+func NewMockWebhooksService(ctrl *gomock.Controller) *MockWebhooksService {
+	mock := &MockWebhooksService{ctrl: ctrl}
+	mock.recorder = &MockWebhooksServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooksService) EXPECT() *MockWebhooksServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhooksService) CreateSubscription(ctx context.Context, url, secret string, statuses []models.OrderStatus) (*models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, url, secret, statuses)
+	ret0, _ := ret[0].(*models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhooksServiceMockRecorder) CreateSubscription(ctx, url, secret, statuses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhooksService)(nil).CreateSubscription), ctx, url, secret, statuses)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhooksService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhooksServiceMockRecorder) DeleteSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhooksService)(nil).DeleteSubscription), ctx, id)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhooksService) GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(*models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhooksServiceMockRecorder) GetSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhooksService)(nil).GetSubscription), ctx, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhooksService) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, subscriptionID)
+	ret0, _ := ret[0].([]*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhooksServiceMockRecorder) ListDeliveries(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhooksService)(nil).ListDeliveries), ctx, subscriptionID)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockWebhooksService) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx)
+	ret0, _ := ret[0].([]*models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockWebhooksServiceMockRecorder) ListSubscriptions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockWebhooksService)(nil).ListSubscriptions), ctx)
+}
+
+// Run mocks base method.
+func (m *MockWebhooksService) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockWebhooksServiceMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockWebhooksService)(nil).Run), ctx)
+}
+
+// MockOrderEventsHub is a mock of OrderEventsHub interface.
+type MockOrderEventsHub struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderEventsHubMockRecorder
+}
+
+// MockOrderEventsHubMockRecorder is the mock recorder for MockOrderEventsHub.
+type MockOrderEventsHubMockRecorder struct {
+	mock *MockOrderEventsHub
+}
+
+// NewMockOrderEventsHub creates a new mock instance.
+func NewMockOrderEventsHub(ctrl *gomock.Controller) *MockOrderEventsHub {
+	mock := &MockOrderEventsHub{ctrl: ctrl}
+	mock.recorder = &MockOrderEventsHubMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderEventsHub) EXPECT() *MockOrderEventsHubMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockOrderEventsHub) Publish(event models.OrderEvent) models.OrderEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", event)
+	ret0, _ := ret[0].(models.OrderEvent)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOrderEventsHubMockRecorder) Publish(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOrderEventsHub)(nil).Publish), event)
+}
+
+// Subscribe mocks base method.
+func (m *MockOrderEventsHub) Subscribe(filter models.OrderEventFilter, lastEventID uint64) (<-chan models.OrderEvent, func(), bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", filter, lastEventID)
+	ret0, _ := ret[0].(<-chan models.OrderEvent)
+	ret1, _ := ret[1].(func())
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockOrderEventsHubMockRecorder) Subscribe(filter, lastEventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockOrderEventsHub)(nil).Subscribe), filter, lastEventID)
 }
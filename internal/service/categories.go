@@ -4,20 +4,24 @@ import (
 	"context"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
 type categoriesSvc struct {
 	log         kitlog.Logger
 	persistence persistence.CategoriesRepository
+	catalog     CatalogObserver
 }
 
-func NewCategoriesService(persistence persistence.CategoriesRepository, log kitlog.Logger) CategoriesService {
+func NewCategoriesService(persistence persistence.CategoriesRepository, catalog CatalogObserver, log kitlog.Logger) CategoriesService {
 	return &categoriesSvc{
 		log:         log,
 		persistence: persistence,
+		catalog:     catalog,
 	}
 }
 
@@ -28,14 +32,79 @@ func (c *categoriesSvc) GetCategory(ctx context.Context, id uuid.UUID) (*models.
 func (c *categoriesSvc) InsertCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
 	in.ID = uuid.New()
 	in.CreatedAt = time.Now()
+	if err := c.validParent(ctx, in); err != nil {
+		return nil, err
+	}
 
-	return c.persistence.InsertCategory(ctx, in)
+	out, err := c.persistence.InsertCategory(ctx, in)
+	if err == nil {
+		c.catalog.CatalogChanged()
+	}
+	return out, err
 }
 
-func (c *categoriesSvc) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	return c.persistence.DeleteCategory(ctx, id)
+// UpdateCategory renames, reorders or moves a category, uuid.Nil as parent moves it to the top level
+func (c *categoriesSvc) UpdateCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" {
+		return nil, helpers.ErrInvalidInput
+	}
+	if in.ParentID == in.ID {
+		return nil, helpers.ErrCategoryCycle
+	}
+	if err := c.validParent(ctx, in); err != nil {
+		return nil, err
+	}
+
+	out, err := c.persistence.UpdateCategory(ctx, in)
+	if err == nil {
+		c.catalog.CatalogChanged()
+	}
+	return out, err
+}
+
+// SetCategoryWindow limits the hours its products are sold, the zero window lifts the limit
+func (c *categoriesSvc) SetCategoryWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Category, error) {
+	if !window.IsZero() && !window.IsValid() {
+		return nil, helpers.ErrInvalidInput
+	}
+	out, err := c.persistence.SetCategoryWindow(ctx, id, window)
+	if err == nil {
+		c.catalog.CatalogChanged()
+	}
+	return out, err
+}
+
+// GetCategoryTree returns every category nested under its parent, siblings in display order
+func (c *categoriesSvc) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	categories, err := c.persistence.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.CategoryTree(categories), nil
+}
+
+// validParent refuses parents that don't exist or were archived
+func (c *categoriesSvc) validParent(ctx context.Context, in *models.Category) error {
+	if in.ParentID == uuid.Nil {
+		return nil
+	}
+	_, err := c.persistence.GetCategoryByID(ctx, in.ParentID)
+	if err == helpers.ErrNotFound {
+		return helpers.ErrInvalidInput
+	}
+	return err
+}
+
+// DeleteCategory archives the category, cascade archives its active products and subcategories too instead of refusing
+func (c *categoriesSvc) DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error {
+	err := c.persistence.DeleteCategory(ctx, id, cascade)
+	if err == nil {
+		c.catalog.CatalogChanged()
+	}
+	return err
 }
 
-func (c *categoriesSvc) ListCategories(ctx context.Context, limit, offset int) (*models.CategoryList, error) {
-	return c.persistence.ListCategories(ctx, limit, offset)
+func (c *categoriesSvc) ListCategories(ctx context.Context, page models.PageRequest) (*models.CategoryList, error) {
+	return c.persistence.ListCategories(ctx, page.Normalized())
 }
@@ -3,15 +3,21 @@ package service
 import (
 	"context"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
 )
 
 //go:generate mockgen -source=contracts.go -package=mocks -destination=../mocks/contracts_mock.go
 type CategoriesService interface {
 	GetCategory(ctx context.Context, id uuid.UUID) (*models.Category, error)
 	InsertCategory(ctx context.Context, in *models.Category) (*models.Category, error)
-	DeleteCategory(ctx context.Context, id uuid.UUID) error
-	ListCategories(ctx context.Context, limit, offset int) (*models.CategoryList, error)
+	UpdateCategory(ctx context.Context, in *models.Category) (*models.Category, error)
+	GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error
+	ListCategories(ctx context.Context, page models.PageRequest) (*models.CategoryList, error)
+	SetCategoryWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Category, error)
 }
 
 type ProductsService interface {
@@ -19,19 +25,66 @@ type ProductsService interface {
 	InsertProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	DeleteProduct(ctx context.Context, uuid uuid.UUID) error
-	ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) (*models.ProductList, error)
+	RestoreProduct(ctx context.Context, id uuid.UUID) (*models.Product, error)
+	SetProductAvailability(ctx context.Context, id uuid.UUID, available bool, until time.Time) (*models.Product, error)
+	SetProductWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Product, error)
+	ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, withDescendants bool, page models.PageRequest) (*models.ProductList, error)
+	SearchProducts(ctx context.Context, in models.ProductSearch) (*models.ProductList, error)
 	GetProductsPriceSumByID(ctx context.Context, products []uuid.UUID) (*models.ProductsSum, error)
+	SchedulePrice(ctx context.Context, productID uuid.UUID, price decimal.Decimal, effectiveAt time.Time) (*models.ProductPrice, error)
+	CancelScheduledPrice(ctx context.Context, productID, priceID uuid.UUID) (*models.ProductPrice, error)
+	ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductPrice, error)
+	ApplyScheduledPrices(ctx context.Context) (int, error)
+}
+
+type InventoryService interface {
+	CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (*models.Ingredient, error)
+	GetIngredient(ctx context.Context, id uuid.UUID) (*models.Ingredient, error)
+	ListIngredients(ctx context.Context) ([]*models.Ingredient, error)
+	AdjustStock(ctx context.Context, id uuid.UUID, delta decimal.Decimal, reason, actor string) (*models.Ingredient, error)
+	ListStockMovements(ctx context.Context, ingredientID uuid.UUID, limit int) ([]*models.StockMovement, error)
+	SetRecipe(ctx context.Context, productID uuid.UUID, items []models.RecipeItem) ([]models.RecipeItem, error)
+	GetRecipe(ctx context.Context, productID uuid.UUID) ([]models.RecipeItem, error)
+	ReserveOrder(ctx context.Context, order *models.Order) error
+	CommitOrder(ctx context.Context, orderID uuid.UUID) error
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) error
+}
+
+type StoreHoursService interface {
+	GetSchedule(ctx context.Context) (*models.StoreSchedule, error)
+	SetBusinessHours(ctx context.Context, hours []models.BusinessHours) (*models.StoreSchedule, error)
+	SetHoursException(ctx context.Context, exception models.HoursException) (*models.StoreSchedule, error)
+	DeleteHoursException(ctx context.Context, day time.Time) error
+	Status(ctx context.Context, at time.Time) (models.StoreStatus, error)
+	EnsureOpen(ctx context.Context, at time.Time) error
+}
+
+type MenuService interface {
+	CatalogObserver
+	GetMenu(ctx context.Context) (*models.Menu, error)
 }
 
 type OrdersService interface {
 	GetOrder(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
 	GetOrderByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Order, error)
-	CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID) (*models.Order, error)
+	GetOrderByPickupNumber(ctx context.Context, number int, day time.Time) (*models.Order, error)
+	CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID, customerName string) (*models.Order, error)
 	UpdateOrderItems(ctx context.Context, orderID uuid.UUID, products []models.Product) (*models.Order, error)
+	UpdateOrderAdjustments(ctx context.Context, orderID uuid.UUID, tip *models.Adjustment, serviceCharge *bool) (*models.Order, error)
 	DeleteOrder(ctx context.Context, orderID uuid.UUID) error
-	ListOrders(ctx context.Context, limit, offset int) (*models.OrderList, error)
+	CancelOrder(ctx context.Context, orderID uuid.UUID, reason models.CancelReason, actor string) (*models.Order, error)
+	ListOrders(ctx context.Context, page models.PageRequest) (*models.OrderList, error)
+	SearchOrders(ctx context.Context, in models.OrderSearch) (*models.OrderList, error)
 	Checkout(ctx context.Context, paymentID uuid.UUID) (*models.Order, error)
+	SplitCheckout(ctx context.Context, orderID uuid.UUID, parts []models.PaymentPart) (*models.Order, error)
 	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status models.OrderStatus) (*models.Order, error)
+	GetPickupPanel(ctx context.Context) (*models.PickupPanel, error)
+	ListOrderPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error)
+	RetryPayment(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
+	RefundOrder(ctx context.Context, orderID, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error)
+	ListOrderRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error)
+	ExpireAbandonedOrders(ctx context.Context, openTTL, paymentTimeout time.Duration) (int, error)
+	ProcessPaymentStatusChange(ctx context.Context, in messages.PaymentStatusChangedMessage) error
 	SubscribeToPaymentUpdates()
 	SubscribeToProductionUpdates()
 }
@@ -39,5 +92,29 @@ type OrdersService interface {
 type PaymentsService interface {
 	GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
 	CreatePayment(ctx context.Context, orderID *models.Order) (*models.Payment, error)
+	CreateSplitPayments(ctx context.Context, order *models.Order, parts []models.PaymentPart) ([]*models.Payment, error)
 	UpdatePayment(ctx context.Context, paymentID uuid.UUID, status models.PaymentStatus) (*models.Payment, error)
+	ChangePaymentStatus(ctx context.Context, payment *models.Payment, status models.PaymentStatus) (*models.Payment, error)
+	CancelPayment(ctx context.Context, paymentID uuid.UUID, reason string) (*models.Payment, error)
+	ListPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error)
+	ListSplitPayments(ctx context.Context, splitID uuid.UUID) ([]*models.Payment, error)
+	ListStalePayments(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Payment, error)
+	PixPayload(payment *models.Payment) (string, error)
+	RequestRefund(ctx context.Context, order *models.Order, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error)
+	ListRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error)
+	SubscribeToRefundUpdates()
+}
+
+type WebhooksService interface {
+	CreateSubscription(ctx context.Context, url, secret string, statuses []models.OrderStatus) (*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]*models.WebhookDelivery, error)
+	Run(ctx context.Context)
+}
+
+type OrderEventsHub interface {
+	Publish(event models.OrderEvent) models.OrderEvent
+	Subscribe(filter models.OrderEventFilter, lastEventID uint64) (events <-chan models.OrderEvent, cancel func(), missed bool)
 }
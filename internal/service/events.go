@@ -0,0 +1,124 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultEventsHistorySize = 1024
+	defaultSubscriberBuffer  = 64
+)
+
+type eventSubscriber struct {
+	filter models.OrderEventFilter
+	ch     chan models.OrderEvent
+}
+
+type orderEventsHub struct {
+	mu          sync.Mutex
+	seq         uint64
+	history     []models.OrderEvent
+	historySize int
+	bufferSize  int
+	subscribers map[*eventSubscriber]struct{}
+	log         kitlog.Logger
+}
+
+// NewOrderEventsHub creates the in-process hub that fans order status changes out to streaming clients.
+// It keeps the last events in memory so reconnecting clients can resume from a Last-Event-ID. IDs start
+// from the boot time so the ones handed out before a restart are always seen as too old to resume from.
+func NewOrderEventsHub(log kitlog.Logger) OrderEventsHub {
+	return &orderEventsHub{
+		seq:         uint64(time.Now().UnixMicro()),
+		historySize: defaultEventsHistorySize,
+		bufferSize:  defaultSubscriberBuffer,
+		subscribers: make(map[*eventSubscriber]struct{}),
+		log:         log,
+	}
+}
+
+func (h *orderEventsHub) Publish(event models.OrderEvent) models.OrderEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	event.ID = h.seq
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// slow consumer: drop it so it reconnects with its Last-Event-ID instead of blocking everyone else
+			h.log.Log(
+				"dropping slow order events subscriber",
+				zap.Uint64("event_id", event.ID),
+				zap.String("order_id", sub.filter.OrderID.String()),
+			)
+			delete(h.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+
+	return event
+}
+
+// Subscribe streams the events matching filter, starting right after lastEventID when it is set. missed
+// tells that the events since lastEventID are no longer all kept, the caller has to catch up another way.
+func (h *orderEventsHub) Subscribe(filter models.OrderEventFilter, lastEventID uint64) (events <-chan models.OrderEvent, cancel func(), missed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []models.OrderEvent
+	if lastEventID > 0 {
+		oldest := h.seq + 1
+		if len(h.history) > 0 {
+			oldest = h.history[0].ID
+		}
+		missed = lastEventID > h.seq || lastEventID+1 < oldest
+
+		for _, e := range h.history {
+			if e.ID > lastEventID && filter.Matches(e) {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	sub := &eventSubscriber{
+		filter: filter,
+		ch:     make(chan models.OrderEvent, h.bufferSize+len(backlog)),
+	}
+	for _, e := range backlog {
+		sub.ch <- e
+	}
+	h.subscribers[sub] = struct{}{}
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := h.subscribers[sub]; ok {
+				delete(h.subscribers, sub)
+				close(sub.ch)
+			}
+		})
+	}
+
+	return sub.ch, cancel, missed
+}
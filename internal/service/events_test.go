@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+)
+
+func TestOrderEventsHubResume(t *testing.T) {
+	newHub := func(historySize int) (*orderEventsHub, []models.OrderEvent) {
+		h := NewOrderEventsHub(kitlog.NewNopLogger()).(*orderEventsHub)
+		h.historySize = historySize
+		var published []models.OrderEvent
+		for i := 0; i < 3; i++ {
+			published = append(published, h.Publish(models.OrderEvent{OrderID: uuid.New(), Status: models.ORDER_STATUS_RECEIVED}))
+		}
+		return h, published
+	}
+
+	tests := []struct {
+		name        string
+		historySize int
+		lastEventID func(published []models.OrderEvent) uint64
+		wantMissed  bool
+		wantBacklog int
+	}{
+		{
+			name:        "first connection",
+			historySize: 10,
+			lastEventID: func([]models.OrderEvent) uint64 { return 0 },
+		},
+		{
+			name:        "resume within history",
+			historySize: 10,
+			lastEventID: func(p []models.OrderEvent) uint64 { return p[0].ID },
+			wantBacklog: 2,
+		},
+		{
+			name:        "resume up to date",
+			historySize: 10,
+			lastEventID: func(p []models.OrderEvent) uint64 { return p[2].ID },
+		},
+		{
+			name:        "resume right before the oldest kept event",
+			historySize: 2,
+			lastEventID: func(p []models.OrderEvent) uint64 { return p[0].ID },
+			wantBacklog: 2,
+		},
+		{
+			name:        "resume past the history",
+			historySize: 1,
+			lastEventID: func(p []models.OrderEvent) uint64 { return p[0].ID },
+			wantMissed:  true,
+			wantBacklog: 1,
+		},
+		{
+			name:        "id from before a restart",
+			historySize: 10,
+			lastEventID: func([]models.OrderEvent) uint64 { return 42 },
+			wantMissed:  true,
+			wantBacklog: 3,
+		},
+		{
+			name:        "id ahead of the hub",
+			historySize: 10,
+			lastEventID: func(p []models.OrderEvent) uint64 { return p[2].ID + 1 },
+			wantMissed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, published := newHub(tt.historySize)
+
+			events, cancel, missed := h.Subscribe(models.OrderEventFilter{}, tt.lastEventID(published))
+			defer cancel()
+
+			if missed != tt.wantMissed {
+				t.Errorf("missed = %v, want %v", missed, tt.wantMissed)
+			}
+			if got := len(events); got != tt.wantBacklog {
+				t.Errorf("backlog = %d events, want %d", got, tt.wantBacklog)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+)
+
+const expirationBatchSize = 100
+
+// OrderExpirationConfig drives the abandoned cart job, zero TTLs disable the matching rule
+type OrderExpirationConfig struct {
+	Interval       time.Duration
+	OpenTTL        time.Duration
+	PaymentTimeout time.Duration
+}
+
+// RunOrderExpiration periodically cancels abandoned orders until ctx is done
+func RunOrderExpiration(ctx context.Context, svc OrdersService, cfg OrderExpirationConfig, log kitlog.Logger) {
+	if cfg.Interval <= 0 || (cfg.OpenTTL <= 0 && cfg.PaymentTimeout <= 0) {
+		log.Log("message", "order expiration job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := svc.ExpireAbandonedOrders(ctx, cfg.OpenTTL, cfg.PaymentTimeout)
+			if err != nil {
+				log.Log(
+					"order expiration run failed",
+					zap.Error(err),
+				)
+				continue
+			}
+			if expired > 0 {
+				log.Log(
+					"expired abandoned orders",
+					zap.Int("count", expired),
+				)
+			}
+		}
+	}
+}
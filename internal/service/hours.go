@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+)
+
+type storeHoursSvc struct {
+	repo  persistence.StoreHoursRepository
+	store models.Store
+	ttl   time.Duration
+	log   kitlog.Logger
+
+	mu       sync.RWMutex
+	schedule *models.StoreSchedule
+	loadedAt time.Time
+}
+
+// NewStoreHoursService keeps the schedule in memory for ttl, every order checks it
+func NewStoreHoursService(repo persistence.StoreHoursRepository, store models.Store, ttl time.Duration, log kitlog.Logger) StoreHoursService {
+	return &storeHoursSvc{
+		repo:  repo,
+		store: store,
+		ttl:   ttl,
+		log:   log,
+	}
+}
+
+func (s *storeHoursSvc) GetSchedule(ctx context.Context) (*models.StoreSchedule, error) {
+	s.mu.RLock()
+	schedule, fresh := s.schedule, s.schedule != nil && time.Since(s.loadedAt) < s.ttl
+	s.mu.RUnlock()
+	if fresh {
+		return schedule, nil
+	}
+
+	// yesterday still matters for hours going past midnight
+	schedule, err := s.repo.GetSchedule(ctx, s.store.ID, s.store.Today().AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.schedule = schedule
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return schedule, nil
+}
+
+// SetBusinessHours replaces the weekly hours, periods of the same weekday may not overlap
+func (s *storeHoursSvc) SetBusinessHours(ctx context.Context, hours []models.BusinessHours) (*models.StoreSchedule, error) {
+	sorted := append([]models.BusinessHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].From < sorted[j].From
+	})
+	for i, h := range sorted {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday || !h.IsValid() {
+			return nil, helpers.ErrInvalidInput
+		}
+		if i > 0 && sorted[i-1].Weekday == h.Weekday && !sorted[i-1].TimeWindow.Before(h.TimeWindow) {
+			return nil, helpers.ErrInvalidInput
+		}
+	}
+
+	if err := s.repo.SetBusinessHours(ctx, s.store.ID, sorted); err != nil {
+		return nil, err
+	}
+	return s.reload(ctx)
+}
+
+// SetHoursException closes the store on a day or gives it special hours
+func (s *storeHoursSvc) SetHoursException(ctx context.Context, exception models.HoursException) (*models.StoreSchedule, error) {
+	if exception.Day.IsZero() || (!exception.Closed && !exception.Window.IsValid()) {
+		return nil, helpers.ErrInvalidInput
+	}
+	exception.Reason = strings.TrimSpace(exception.Reason)
+	if exception.Closed {
+		exception.Window = models.TimeWindow{}
+	}
+
+	if err := s.repo.SetHoursException(ctx, s.store.ID, exception); err != nil {
+		return nil, err
+	}
+	return s.reload(ctx)
+}
+
+func (s *storeHoursSvc) DeleteHoursException(ctx context.Context, day time.Time) error {
+	if err := s.repo.DeleteHoursException(ctx, s.store.ID, day); err != nil {
+		return err
+	}
+	_, err := s.reload(ctx)
+	return err
+}
+
+// Status tells whether the store takes orders at at
+func (s *storeHoursSvc) Status(ctx context.Context, at time.Time) (models.StoreStatus, error) {
+	schedule, err := s.GetSchedule(ctx)
+	if err != nil {
+		return models.StoreStatus{}, err
+	}
+	return models.StoreStatus{Open: schedule.IsOpen(at, s.store.Location), At: at}, nil
+}
+
+// EnsureOpen fails with helpers.ErrStoreClosed outside business hours
+func (s *storeHoursSvc) EnsureOpen(ctx context.Context, at time.Time) error {
+	status, err := s.Status(ctx, at)
+	if err != nil {
+		return err
+	}
+	if !status.Open {
+		s.log.Log(
+			"order refused, store is closed",
+			zap.Time("at", at),
+			zap.Error(helpers.ErrStoreClosed),
+		)
+		return helpers.ErrStoreClosed
+	}
+	return nil
+}
+
+// reload drops the cached schedule after a write so this instance sees it at once
+func (s *storeHoursSvc) reload(ctx context.Context) (*models.StoreSchedule, error) {
+	s.mu.Lock()
+	s.schedule = nil
+	s.mu.Unlock()
+	return s.GetSchedule(ctx)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type inventorySvc struct {
+	repo    persistence.InventoryRepository
+	catalog CatalogObserver
+	log     kitlog.Logger
+}
+
+// NewInventoryService keeps ingredient stock in step with the orders, products whose recipe can't be made
+// anymore leave the menu on their own and come back once stock is adjusted or released
+func NewInventoryService(repo persistence.InventoryRepository, catalog CatalogObserver, log kitlog.Logger) InventoryService {
+	return &inventorySvc{
+		repo:    repo,
+		catalog: catalog,
+		log:     log,
+	}
+}
+
+func (i *inventorySvc) CreateIngredient(ctx context.Context, in *models.Ingredient) (*models.Ingredient, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || in.Unit == "" || in.Stock.IsNegative() {
+		return nil, helpers.ErrInvalidInput
+	}
+	in.ID = uuid.New()
+	in.CreatedAt = time.Now()
+	in.Reserved = decimal.Zero
+
+	return i.repo.CreateIngredient(ctx, in)
+}
+
+func (i *inventorySvc) GetIngredient(ctx context.Context, id uuid.UUID) (*models.Ingredient, error) {
+	return i.repo.GetIngredient(ctx, id)
+}
+
+func (i *inventorySvc) ListIngredients(ctx context.Context) ([]*models.Ingredient, error) {
+	return i.repo.ListIngredients(ctx)
+}
+
+// AdjustStock records a delivery, loss or count, delta is added to the stock on hand
+func (i *inventorySvc) AdjustStock(ctx context.Context, id uuid.UUID, delta decimal.Decimal, reason, actor string) (*models.Ingredient, error) {
+	reason = strings.TrimSpace(reason)
+	if delta.IsZero() || reason == "" {
+		return nil, helpers.ErrInvalidInput
+	}
+
+	out, err := i.repo.AdjustStock(ctx, id, delta, reason, strings.TrimSpace(actor))
+	if err == nil {
+		i.catalog.CatalogChanged()
+	}
+	return out, err
+}
+
+func (i *inventorySvc) ListStockMovements(ctx context.Context, ingredientID uuid.UUID, limit int) ([]*models.StockMovement, error) {
+	if _, err := i.repo.GetIngredient(ctx, ingredientID); err != nil {
+		return nil, err
+	}
+	return i.repo.ListStockMovements(ctx, ingredientID, limit)
+}
+
+// SetRecipe replaces the ingredients one unit of the product takes, an empty recipe stops tracking it
+func (i *inventorySvc) SetRecipe(ctx context.Context, productID uuid.UUID, items []models.RecipeItem) ([]models.RecipeItem, error) {
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if item.IngredientID == uuid.Nil || !item.Quantity.IsPositive() || seen[item.IngredientID] {
+			return nil, helpers.ErrInvalidInput
+		}
+		if _, err := i.repo.GetIngredient(ctx, item.IngredientID); err != nil {
+			return nil, helpers.ErrInvalidInput
+		}
+		seen[item.IngredientID] = true
+	}
+
+	if err := i.repo.SetRecipe(ctx, productID, items); err != nil {
+		return nil, err
+	}
+	i.catalog.CatalogChanged()
+
+	return i.GetRecipe(ctx, productID)
+}
+
+func (i *inventorySvc) GetRecipe(ctx context.Context, productID uuid.UUID) ([]models.RecipeItem, error) {
+	recipes, err := i.repo.ListRecipes(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, err
+	}
+	return recipes[productID], nil
+}
+
+// ReserveOrder holds the ingredients of every product in the order, failing with ErrOutOfStock when one is short.
+// Products without a recipe aren't tracked.
+func (i *inventorySvc) ReserveOrder(ctx context.Context, order *models.Order) error {
+	ids := make([]uuid.UUID, 0, len(order.Products))
+	for _, p := range order.Products {
+		ids = append(ids, p.ID)
+	}
+	recipes, err := i.repo.ListRecipes(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	// products repeat in the order once per unit
+	needs := make(map[uuid.UUID]decimal.Decimal)
+	for _, p := range order.Products {
+		for _, item := range recipes[p.ID] {
+			needs[item.IngredientID] = needs[item.IngredientID].Add(item.Quantity)
+		}
+	}
+	if len(needs) == 0 {
+		return nil
+	}
+
+	changed, err := i.repo.ReserveStock(ctx, order.ID, needs)
+	if err != nil {
+		if err == helpers.ErrOutOfStock {
+			i.log.Log(
+				"refusing checkout without stock",
+				zap.String("order_id", order.ID.String()),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+	i.notify(changed)
+	return nil
+}
+
+// CommitOrder consumes the stock held by the order, once it is paid
+func (i *inventorySvc) CommitOrder(ctx context.Context, orderID uuid.UUID) error {
+	changed, err := i.repo.CommitStock(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	i.notify(changed)
+	return nil
+}
+
+// ReleaseOrder gives back the stock held by an order that won't be paid
+func (i *inventorySvc) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	changed, err := i.repo.ReleaseStock(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	i.notify(changed)
+	return nil
+}
+
+// notify rebuilds the menu only when a product ran out or came back, not on every checkout
+func (i *inventorySvc) notify(changed bool) {
+	if changed {
+		i.catalog.CatalogChanged()
+	}
+}
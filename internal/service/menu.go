@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+)
+
+// CatalogObserver is told about every write to products or categories
+type CatalogObserver interface {
+	CatalogChanged()
+}
+
+type menuSvc struct {
+	repo  persistence.MenuRepository
+	hours StoreHoursService
+	store models.Store
+	ttl   time.Duration
+	log   kitlog.Logger
+
+	mu        sync.RWMutex
+	snapshot  *models.Menu
+	loadedAt  time.Time
+	changedAt time.Time
+}
+
+// NewMenuService serves the menu from an in-memory snapshot, rebuilt after catalog writes seen by this instance
+// and at least every ttl so writes made through other instances show up too
+func NewMenuService(repo persistence.MenuRepository, hours StoreHoursService, store models.Store, ttl time.Duration, log kitlog.Logger) MenuService {
+	return &menuSvc{
+		repo:  repo,
+		hours: hours,
+		store: store,
+		ttl:   ttl,
+		log:   log,
+	}
+}
+
+// GetMenu returns the products that can be ordered now, the snapshot keeps the unavailable ones so
+// products coming back at their unavailable until time or at the start of their hours show up without
+// waiting for a rebuild
+func (m *menuSvc) GetMenu(ctx context.Context) (*models.Menu, error) {
+	snapshot, err := m.loadSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	status, err := m.hours.Status(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	clock := models.ClockTimeOf(now, m.store.Location)
+	out := &models.Menu{UpdatedAt: snapshot.UpdatedAt, Open: status.Open}
+	for _, c := range snapshot.Categories {
+		category := models.MenuCategory{Category: c.Category}
+		for _, p := range c.Products {
+			// entering or leaving its hours is a change of the menu too
+			if window := p.OrderableWindow(); !window.IsZero() {
+				out.UpdatedAt = latest(out.UpdatedAt, window.LastChange(now, m.store.Location))
+			}
+			if !p.IsAvailable(now) || !p.InWindow(clock) {
+				continue
+			}
+			category.Products = append(category.Products, p)
+			// coming back on its own is a change of the menu too
+			if !p.Available && p.UnavailableUntil.After(out.UpdatedAt) {
+				out.UpdatedAt = p.UnavailableUntil
+			}
+		}
+		if len(category.Products) > 0 {
+			out.Categories = append(out.Categories, category)
+		}
+	}
+
+	return out, nil
+}
+
+func (m *menuSvc) loadSnapshot(ctx context.Context) (*models.Menu, error) {
+	m.mu.RLock()
+	snapshot, fresh := m.snapshot, m.isFresh()
+	m.mu.RUnlock()
+	if fresh {
+		return snapshot, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// another request may have rebuilt it while waiting for the lock
+	if m.isFresh() {
+		return m.snapshot, nil
+	}
+
+	menu, err := m.repo.LoadMenu(ctx)
+	if err != nil {
+		if m.snapshot != nil {
+			m.log.Log(
+				"serving stale menu, failed reloading it",
+				zap.Error(err),
+			)
+			return m.snapshot, nil
+		}
+		return nil, err
+	}
+	// deletions leave no timestamp behind, the local write time covers them
+	if m.changedAt.After(menu.UpdatedAt) {
+		menu.UpdatedAt = m.changedAt
+	}
+
+	m.snapshot = menu
+	m.loadedAt = time.Now()
+
+	return menu, nil
+}
+
+func (m *menuSvc) isFresh() bool {
+	return m.snapshot != nil && time.Since(m.loadedAt) < m.ttl
+}
+
+// CatalogChanged drops the snapshot so the next request rebuilds it
+func (m *menuSvc) CatalogChanged() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot = nil
+	m.changedAt = time.Now()
+}
+
+func latest(current, candidate time.Time) time.Time {
+	if candidate.After(current) {
+		return candidate
+	}
+	return current
+}
@@ -0,0 +1,62 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// AdjustmentKind tells what an order adjustment charges for, adjustments are never product lines
+type AdjustmentKind string
+
+const (
+	ADJUSTMENT_KIND_TIP            AdjustmentKind = "tip"
+	ADJUSTMENT_KIND_SERVICE_CHARGE AdjustmentKind = "service_charge"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Adjustment is an amount added to the order subtotal. Percentage adjustments keep their
+// Percentage and have Amount recalculated whenever the items change, fixed ones keep Amount
+type Adjustment struct {
+	Kind       AdjustmentKind
+	Percentage decimal.Decimal
+	Amount     decimal.Decimal
+}
+
+// IsPercentage reports whether the adjustment follows the order subtotal
+func (a Adjustment) IsPercentage() bool {
+	return a.Percentage.IsPositive()
+}
+
+// Subtotal sums the products of the order, without adjustments
+func (o *Order) Subtotal() decimal.Decimal {
+	subtotal := decimal.Zero
+	for _, p := range o.Products {
+		subtotal = subtotal.Add(p.Price)
+	}
+	return subtotal
+}
+
+// SetAdjustment replaces the adjustment of the same kind, a zero amount and percentage removes it
+func (o *Order) SetAdjustment(in Adjustment) {
+	var out []Adjustment
+	for _, a := range o.Adjustments {
+		if a.Kind != in.Kind {
+			out = append(out, a)
+		}
+	}
+	if in.IsPercentage() || in.Amount.IsPositive() {
+		out = append(out, in)
+	}
+	o.Adjustments = out
+}
+
+// Reprice recalculates percentage adjustments and sets Price to the subtotal plus every adjustment
+func (o *Order) Reprice() {
+	subtotal := o.Subtotal()
+	price := subtotal
+	for k, a := range o.Adjustments {
+		if a.IsPercentage() {
+			o.Adjustments[k].Amount = subtotal.Mul(a.Percentage).Div(hundred).Round(2)
+		}
+		price = price.Add(o.Adjustments[k].Amount)
+	}
+	o.Price = price
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func productsPriced(prices ...string) []Product {
+	out := make([]Product, 0, len(prices))
+	for _, p := range prices {
+		out = append(out, Product{Price: dec(p)})
+	}
+	return out
+}
+
+func TestOrderSetAdjustment(t *testing.T) {
+	tip := Adjustment{Kind: ADJUSTMENT_KIND_TIP, Amount: dec("5")}
+	charge := Adjustment{Kind: ADJUSTMENT_KIND_SERVICE_CHARGE, Percentage: dec("10")}
+
+	tests := []struct {
+		name    string
+		current []Adjustment
+		in      Adjustment
+		want    []Adjustment
+	}{
+		{
+			name: "adds to an order without adjustments",
+			in:   tip,
+			want: []Adjustment{tip},
+		},
+		{
+			name:    "replaces the one of the same kind",
+			current: []Adjustment{tip, charge},
+			in:      Adjustment{Kind: ADJUSTMENT_KIND_TIP, Percentage: dec("15")},
+			want:    []Adjustment{charge, {Kind: ADJUSTMENT_KIND_TIP, Percentage: dec("15")}},
+		},
+		{
+			name:    "zero removes it",
+			current: []Adjustment{tip, charge},
+			in:      Adjustment{Kind: ADJUSTMENT_KIND_SERVICE_CHARGE},
+			want:    []Adjustment{tip},
+		},
+		{
+			name:    "zero of a missing kind changes nothing",
+			current: []Adjustment{charge},
+			in:      Adjustment{Kind: ADJUSTMENT_KIND_TIP},
+			want:    []Adjustment{charge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Adjustments: append([]Adjustment(nil), tt.current...)}
+			o.SetAdjustment(tt.in)
+
+			if len(o.Adjustments) != len(tt.want) {
+				t.Fatalf("Adjustments = %+v, want %+v", o.Adjustments, tt.want)
+			}
+			for i, a := range o.Adjustments {
+				w := tt.want[i]
+				if a.Kind != w.Kind || !a.Amount.Equal(w.Amount) || !a.Percentage.Equal(w.Percentage) {
+					t.Errorf("Adjustments[%d] = %+v, want %+v", i, a, w)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderReprice(t *testing.T) {
+	tests := []struct {
+		name        string
+		products    []Product
+		adjustments []Adjustment
+		wantPrice   string
+		wantAmounts []string
+	}{
+		{
+			name:      "no adjustments",
+			products:  productsPriced("10.00", "5.50"),
+			wantPrice: "15.50",
+		},
+		{
+			name:        "fixed tip is kept",
+			products:    productsPriced("10.00"),
+			adjustments: []Adjustment{{Kind: ADJUSTMENT_KIND_TIP, Amount: dec("2.00")}},
+			wantPrice:   "12.00",
+			wantAmounts: []string{"2.00"},
+		},
+		{
+			name:     "percentages follow the subtotal",
+			products: productsPriced("20.00", "5.00"),
+			adjustments: []Adjustment{
+				{Kind: ADJUSTMENT_KIND_SERVICE_CHARGE, Percentage: dec("10"), Amount: dec("1.00")},
+				{Kind: ADJUSTMENT_KIND_TIP, Percentage: dec("5")},
+			},
+			wantPrice:   "28.75",
+			wantAmounts: []string{"2.50", "1.25"},
+		},
+		{
+			name:        "percentages are rounded to cents",
+			products:    productsPriced("9.99"),
+			adjustments: []Adjustment{{Kind: ADJUSTMENT_KIND_SERVICE_CHARGE, Percentage: dec("12.5")}},
+			wantPrice:   "11.24",
+			wantAmounts: []string{"1.25"},
+		},
+		{
+			name:        "empty order keeps only fixed amounts",
+			adjustments: []Adjustment{{Kind: ADJUSTMENT_KIND_TIP, Amount: dec("3")}, {Kind: ADJUSTMENT_KIND_SERVICE_CHARGE, Percentage: dec("10")}},
+			wantPrice:   "3",
+			wantAmounts: []string{"3", "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Products: tt.products, Adjustments: tt.adjustments}
+			o.Reprice()
+
+			if !o.Price.Equal(dec(tt.wantPrice)) {
+				t.Errorf("Price = %s, want %s", o.Price, tt.wantPrice)
+			}
+			for i, want := range tt.wantAmounts {
+				if !o.Adjustments[i].Amount.Equal(dec(want)) {
+					t.Errorf("Adjustments[%d].Amount = %s, want %s", i, o.Adjustments[i].Amount, want)
+				}
+			}
+		})
+	}
+}
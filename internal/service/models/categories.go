@@ -10,10 +10,46 @@ type Category struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
+
+	// ParentID nests the category under another one, uuid.Nil for top level categories
+	ParentID uuid.UUID
+	// DisplayOrder sorts categories among their siblings on the menu, ties are sorted by name
+	DisplayOrder int
+	// Window limits the hours of the day its products can be ordered, subcategories without one inherit it
+	Window TimeWindow
 }
 
 type CategoryList struct {
 	Categories    []*Category
 	Limit, Offset int
 	Total         int64
+
+	// Next and Prev are the cursors of the pages around this one, zero at the ends of the list
+	Next, Prev Cursor
+}
+
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode
+}
+
+// CategoryTree nests the categories under their parents keeping the given order among siblings.
+// Categories whose parent is not in the list are kept at the top level.
+func CategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &CategoryNode{Category: *c, Children: []*CategoryNode{}}
+	}
+
+	roots := make([]*CategoryNode, 0)
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok && c.ParentID != uuid.Nil {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// OrderEvent is a status change emitted by the orders service to in-process subscribers
+type OrderEvent struct {
+	ID             uint64
+	OrderID        uuid.UUID
+	Status         OrderStatus
+	PreviousStatus OrderStatus
+	OccurredAt     time.Time
+}
+
+// OrderEventFilter selects which events a subscriber receives.
+// A nil OrderID matches every order and an empty Statuses matches every status.
+type OrderEventFilter struct {
+	OrderID  uuid.UUID
+	Statuses []OrderStatus
+}
+
+func (f OrderEventFilter) Matches(e OrderEvent) bool {
+	if f.OrderID != uuid.Nil && f.OrderID != e.OrderID {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if s == e.Status {
+			return true
+		}
+	}
+	return false
+}
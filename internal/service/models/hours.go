@@ -0,0 +1,166 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidClockTime = errors.New("invalid time of day, expected HH:MM")
+
+// ClockTime is a time of day in the store timezone, in minutes after midnight. 24:00 closes a day.
+type ClockTime int
+
+const clockDay ClockTime = 24 * 60
+
+// ParseClockTime reads an HH:MM time of day
+func ParseClockTime(in string) (ClockTime, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(in, "%d:%d", &h, &m); err != nil || n != 2 || len(in) != 5 {
+		return 0, ErrInvalidClockTime
+	}
+	c := ClockTime(h*60 + m)
+	if h < 0 || m < 0 || m > 59 || c > clockDay {
+		return 0, ErrInvalidClockTime
+	}
+	return c, nil
+}
+
+// ClockTimeOf returns the time of day of t in loc
+func ClockTimeOf(t time.Time, loc *time.Location) ClockTime {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	return ClockTime(local.Hour()*60 + local.Minute())
+}
+
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+// TimeWindow is a daily interval from From until To. When To is not after From it goes past midnight,
+// 22:00-02:00 covers the late night. The zero value is no window at all.
+type TimeWindow struct {
+	From, To ClockTime
+}
+
+func (w TimeWindow) IsZero() bool {
+	return w == TimeWindow{}
+}
+
+func (w TimeWindow) IsValid() bool {
+	return w.From >= 0 && w.From < clockDay && w.To >= 0 && w.To <= clockDay && w.From != w.To
+}
+
+func (w TimeWindow) crossesMidnight() bool {
+	return w.To < w.From
+}
+
+// Contains reports whether c falls in the window, counting the part past midnight
+func (w TimeWindow) Contains(c ClockTime) bool {
+	if w.crossesMidnight() {
+		return c >= w.From || c < w.To
+	}
+	return c >= w.From && c < w.To
+}
+
+// LastChange returns the latest time at or before now the window opened or closed, loc is the store timezone
+func (w TimeWindow) LastChange(now time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	var out time.Time
+	for _, day := range []time.Time{local.AddDate(0, 0, -1), local} {
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		for _, c := range []ClockTime{w.From, w.To} {
+			at := midnight.Add(time.Duration(c) * time.Minute)
+			if !at.After(now) && at.After(out) {
+				out = at
+			}
+		}
+	}
+	return out
+}
+
+// Before reports whether w ends no later than next starts on the same day, windows past midnight end last
+func (w TimeWindow) Before(next TimeWindow) bool {
+	return !w.crossesMidnight() && w.To <= next.From
+}
+
+// BusinessHours is a period the store is open on a weekday, a weekday may have several
+type BusinessHours struct {
+	Weekday time.Weekday
+	TimeWindow
+}
+
+// HoursException replaces the weekly hours on a calendar day, for holidays and special events
+type HoursException struct {
+	// Day is the store calendar day, midnight in the store timezone
+	Day    time.Time
+	Closed bool
+	// Window are the hours of the day when it is not Closed
+	Window TimeWindow
+	Reason string
+}
+
+// StoreSchedule holds the hours of the store. A store without weekly hours is always open, except on
+// the days closed by an exception.
+type StoreSchedule struct {
+	Hours      []BusinessHours
+	Exceptions []HoursException
+}
+
+// windowsOn returns the hours of a calendar day, an exception takes the place of the weekly hours
+func (s StoreSchedule) windowsOn(day time.Time) (windows []TimeWindow, configured bool) {
+	y, m, d := day.Date()
+	for _, e := range s.Exceptions {
+		if ey, em, ed := e.Day.Date(); ey == y && em == m && ed == d {
+			if e.Closed {
+				return nil, true
+			}
+			return []TimeWindow{e.Window}, true
+		}
+	}
+	for _, h := range s.Hours {
+		if h.Weekday == day.Weekday() {
+			windows = append(windows, h.TimeWindow)
+		}
+	}
+	return windows, len(s.Hours) > 0
+}
+
+// IsOpen reports whether the store is open at t, loc is the store timezone
+func (s StoreSchedule) IsOpen(t time.Time, loc *time.Location) bool {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	c := ClockTimeOf(local, loc)
+
+	today, configured := s.windowsOn(local)
+	if !configured {
+		return true
+	}
+	for _, w := range today {
+		if w.Contains(c) && (!w.crossesMidnight() || c >= w.From) {
+			return true
+		}
+	}
+	// late hours started the day before
+	yesterday, _ := s.windowsOn(local.AddDate(0, 0, -1))
+	for _, w := range yesterday {
+		if w.crossesMidnight() && c < w.To {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreStatus tells whether the store takes orders at At
+type StoreStatus struct {
+	Open bool
+	At   time.Time
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClockTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ClockTime
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"08:30", 8*60 + 30, false},
+		{"23:59", 23*60 + 59, false},
+		{"24:00", clockDay, false},
+		{"24:01", 0, true},
+		{"12:60", 0, true},
+		{"8:30", 0, true},
+		{"08h30", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseClockTime(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClockTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClockTime(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.in {
+				t.Errorf("ParseClockTime(%q).String() = %q", tt.in, got.String())
+			}
+		})
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	lunch := TimeWindow{From: 11 * 60, To: 15 * 60}
+	night := TimeWindow{From: 22 * 60, To: 2 * 60}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		at     ClockTime
+		want   bool
+	}{
+		{"before opening", lunch, 10*60 + 59, false},
+		{"at opening", lunch, 11 * 60, true},
+		{"before closing", lunch, 14*60 + 59, true},
+		{"at closing", lunch, 15 * 60, false},
+		{"overnight before midnight", night, 23 * 60, true},
+		{"overnight after midnight", night, 60, true},
+		{"overnight at closing", night, 2 * 60, false},
+		{"overnight during the day", night, 12 * 60, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.at); got != tt.want {
+				t.Errorf("%v.Contains(%s) = %v, want %v", tt.window, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreScheduleIsOpen(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, loc)
+	}
+	window := func(from, to string) TimeWindow {
+		f, _ := ParseClockTime(from)
+		t, _ := ParseClockTime(to)
+		return TimeWindow{From: f, To: t}
+	}
+
+	// 2024-03-01 is a friday
+	weekly := StoreSchedule{
+		Hours: []BusinessHours{
+			{Weekday: time.Friday, TimeWindow: window("11:00", "15:00")},
+			{Weekday: time.Friday, TimeWindow: window("18:00", "02:00")},
+			{Weekday: time.Saturday, TimeWindow: window("12:00", "16:00")},
+		},
+		Exceptions: []HoursException{
+			{Day: at(9, 0, 0), Closed: true, Reason: "feriado"},
+			{Day: at(16, 0, 0), Window: window("08:00", "10:00"), Reason: "evento"},
+		},
+	}
+	holidayOnly := StoreSchedule{
+		Exceptions: []HoursException{{Day: at(9, 0, 0), Closed: true}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule StoreSchedule
+		at       time.Time
+		want     bool
+	}{
+		{"before lunch", weekly, at(1, 10, 59), false},
+		{"lunch opening", weekly, at(1, 11, 0), true},
+		{"lunch closing", weekly, at(1, 15, 0), false},
+		{"night opening", weekly, at(1, 18, 0), true},
+		{"night before midnight", weekly, at(1, 23, 59), true},
+		{"night past midnight", weekly, at(2, 1, 59), true},
+		{"night closing", weekly, at(2, 2, 0), false},
+		{"no late hours the day before", weekly, at(1, 1, 0), false},
+		{"weekday without hours", weekly, at(3, 12, 0), false},
+		{"saturday hours", weekly, at(2, 12, 0), true},
+		{"closed by an exception", weekly, at(9, 13, 0), false},
+		{"late hours of the day before a closed day", weekly, at(9, 1, 0), true},
+		{"special hours", weekly, at(16, 9, 0), true},
+		{"outside special hours", weekly, at(16, 13, 0), false},
+		{"other timezone", weekly, time.Date(2024, time.March, 1, 14, 0, 0, 0, time.UTC), true},
+		{"no hours is always open", StoreSchedule{}, at(3, 4, 0), true},
+		{"no hours but a holiday", holidayOnly, at(9, 12, 0), false},
+		{"no hours after a holiday", holidayOnly, at(10, 12, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.IsOpen(tt.at, loc); got != tt.want {
+				t.Errorf("IsOpen(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Ingredient is an item kept in stock. Reserved is held by checked out orders waiting for payment,
+// so only Stock minus Reserved can still be sold.
+type Ingredient struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Unit      string
+	Stock     decimal.Decimal
+	Reserved  decimal.Decimal
+}
+
+// Available is the quantity new orders can still take
+func (i Ingredient) Available() decimal.Decimal {
+	return i.Stock.Sub(i.Reserved)
+}
+
+// RecipeItem is how much of an ingredient one unit of a product takes
+type RecipeItem struct {
+	IngredientID uuid.UUID
+	Quantity     decimal.Decimal
+}
+
+// StockMovement is an entry of the append-only history of an ingredient stock
+type StockMovement struct {
+	ID            int64
+	IngredientID  uuid.UUID
+	OrderID       uuid.UUID
+	Kind          StockMovementKind
+	Quantity      decimal.Decimal
+	StockAfter    decimal.Decimal
+	ReservedAfter decimal.Decimal
+	Reason        string
+	Actor         string
+	OccurredAt    time.Time
+}
+
+type StockMovementKind string
+
+const (
+	// STOCK_MOVEMENT_RESERVE holds stock for an order at checkout
+	STOCK_MOVEMENT_RESERVE StockMovementKind = "reserve"
+	// STOCK_MOVEMENT_COMMIT takes the held stock out once the payment is approved
+	STOCK_MOVEMENT_COMMIT StockMovementKind = "commit"
+	// STOCK_MOVEMENT_RELEASE gives the held stock back when the order is canceled or its payment refused
+	STOCK_MOVEMENT_RELEASE StockMovementKind = "release"
+	// STOCK_MOVEMENT_ADJUST is a manual change, deliveries, losses and counts
+	STOCK_MOVEMENT_ADJUST StockMovementKind = "adjust"
+)
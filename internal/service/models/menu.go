@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Menu is the whole catalog as shown on the kiosks, categories without products are left out
+type Menu struct {
+	Categories []MenuCategory
+	UpdatedAt  time.Time
+	// Open tells whether the store takes orders now, the menu is still shown while it is closed
+	Open bool
+}
+
+type MenuCategory struct {
+	Category
+	Products []Product
+}
@@ -16,6 +16,23 @@ type Order struct {
 	Price     decimal.Decimal
 	Status    OrderStatus
 	Products  []Product
+
+	// Adjustments are charged on top of the products, Price already includes them
+	Adjustments []Adjustment
+
+	StoreID      string
+	PickupNumber int
+	PickupDate   time.Time
+	CustomerName string
+
+	CancelReason CancelReason
+	CanceledAt   time.Time
+	CanceledBy   string
+
+	// PixCode is the "copia e cola" of the pending charge, filled at checkout and never stored
+	PixCode string
+	// PaymentParts are the charges of a split bill, filled at checkout and never stored
+	PaymentParts []*Payment
 }
 
 type OrderProductionNotification struct {
@@ -36,10 +53,100 @@ const (
 	ORDER_STATUS_FINISHED                    = "Finalizado"
 	ORDER_STATUS_CANCELED                    = "Cancelado"
 	ORDER_STATUS_FAILED_PAYMENT              = "Falha no Pagamento"
+	ORDER_STATUS_PAYMENT_REFUSED             = "Pagamento Recusado"
 )
 
+// CancelReason records why an order was canceled
+type CancelReason string
+
+const (
+	CANCEL_REASON_CUSTOMER_REQUEST CancelReason = "customer_request"
+	CANCEL_REASON_STORE_REQUEST    CancelReason = "store_request"
+	CANCEL_REASON_OUT_OF_STOCK     CancelReason = "out_of_stock"
+	CANCEL_REASON_DUPLICATE_ORDER  CancelReason = "duplicate_order"
+	CANCEL_REASON_OTHER            CancelReason = "other"
+
+	// set by the service itself, never accepted from clients
+	CANCEL_REASON_OPEN_EXPIRED    CancelReason = "open_expired"
+	CANCEL_REASON_PAYMENT_TIMEOUT CancelReason = "payment_timeout"
+)
+
+const (
+	// CANCEL_ACTOR_SYSTEM is recorded as the actor of cancellations triggered by the service itself
+	CANCEL_ACTOR_SYSTEM = "system"
+	// CANCEL_ACTOR_API is recorded when an order is canceled through DELETE /order/{id}, which carries no actor
+	CANCEL_ACTOR_API = "api"
+)
+
+// IsRequestable reports whether clients may cancel an order giving r as the reason
+func (r CancelReason) IsRequestable() bool {
+	switch r {
+	case CANCEL_REASON_CUSTOMER_REQUEST,
+		CANCEL_REASON_STORE_REQUEST,
+		CANCEL_REASON_OUT_OF_STOCK,
+		CANCEL_REASON_DUPLICATE_ORDER,
+		CANCEL_REASON_OTHER:
+		return true
+	default:
+		return false
+	}
+}
+
+// orderStatusTransitions is the order state machine, statuses not listed as keys are terminal
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	ORDER_STATUS_OPEN:            {ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_WAITING_PAYMENT: {ORDER_STATUS_RECEIVED, ORDER_STATUS_FAILED_PAYMENT, ORDER_STATUS_PAYMENT_REFUSED, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_FAILED_PAYMENT:  {ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_PAYMENT_REFUSED: {ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_RECEIVED:        {ORDER_STATUS_PREPARING, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_PREPARING:       {ORDER_STATUS_DONE, ORDER_STATUS_CANCELED},
+	ORDER_STATUS_DONE:            {ORDER_STATUS_FINISHED},
+}
+
+// IsKnown reports whether s is one of the statuses an order can be in
+func (s OrderStatus) IsKnown() bool {
+	if _, ok := orderStatusTransitions[s]; ok {
+		return true
+	}
+	return s == ORDER_STATUS_FINISHED || s == ORDER_STATUS_CANCELED
+}
+
+// CanTransitionTo reports whether an order in status s may move to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type OrderList struct {
 	Orders        []*Order
 	Limit, Offset int
 	Total         int64
+
+	// Next and Prev are the cursors of the pages around this one, zero at the ends of the list
+	Next, Prev Cursor
+}
+
+// OrderSearch filters the orders for the back-office, zero values don't filter and the filters combine.
+// Deleted orders are never found.
+type OrderSearch struct {
+	// Statuses keeps the orders in any of them
+	Statuses []OrderStatus
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom, CreatedTo time.Time
+	// FromDay and ToDay are store calendar days, both inclusive. They replace CreatedFrom and CreatedTo.
+	FromDay, ToDay time.Time
+	UserID         uuid.UUID
+	// CustomerName is matched anywhere in the name given at the counter, ignoring case
+	CustomerName string
+	// PaymentID finds the order of any part of a split bill too
+	PaymentID uuid.UUID
+	// ProductID keeps the orders with that product among their items
+	ProductID          uuid.UUID
+	MinPrice, MaxPrice decimal.NullDecimal
+
+	Page PageRequest
 }
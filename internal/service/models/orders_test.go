@@ -0,0 +1,63 @@
+package models
+
+import "testing"
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		want     bool
+	}{
+		{ORDER_STATUS_OPEN, ORDER_STATUS_WAITING_PAYMENT, true},
+		{ORDER_STATUS_OPEN, ORDER_STATUS_CANCELED, true},
+		{ORDER_STATUS_OPEN, ORDER_STATUS_RECEIVED, false},
+		{ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_RECEIVED, true},
+		{ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_FAILED_PAYMENT, true},
+		{ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_PAYMENT_REFUSED, true},
+		{ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_WAITING_PAYMENT, false},
+		{ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_PREPARING, false},
+		{ORDER_STATUS_FAILED_PAYMENT, ORDER_STATUS_WAITING_PAYMENT, true},
+		{ORDER_STATUS_PAYMENT_REFUSED, ORDER_STATUS_WAITING_PAYMENT, true},
+		{ORDER_STATUS_PAYMENT_REFUSED, ORDER_STATUS_RECEIVED, false},
+		{ORDER_STATUS_RECEIVED, ORDER_STATUS_PREPARING, true},
+		{ORDER_STATUS_RECEIVED, ORDER_STATUS_WAITING_PAYMENT, false},
+		{ORDER_STATUS_PREPARING, ORDER_STATUS_DONE, true},
+		{ORDER_STATUS_PREPARING, ORDER_STATUS_CANCELED, true},
+		{ORDER_STATUS_PREPARING, ORDER_STATUS_WAITING_PAYMENT, false},
+		{ORDER_STATUS_DONE, ORDER_STATUS_FINISHED, true},
+		{ORDER_STATUS_DONE, ORDER_STATUS_CANCELED, false},
+		{ORDER_STATUS_FINISHED, ORDER_STATUS_WAITING_PAYMENT, false},
+		{ORDER_STATUS_CANCELED, ORDER_STATUS_RECEIVED, false},
+		{ORDER_STATUS_CANCELED, ORDER_STATUS_WAITING_PAYMENT, false},
+		{ORDER_STATUS_UNSET, ORDER_STATUS_OPEN, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("%q.CanTransitionTo(%q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderStatusIsKnown(t *testing.T) {
+	tests := []struct {
+		status OrderStatus
+		want   bool
+	}{
+		{ORDER_STATUS_OPEN, true},
+		{ORDER_STATUS_DONE, true},
+		{ORDER_STATUS_FINISHED, true},
+		{ORDER_STATUS_CANCELED, true},
+		{ORDER_STATUS_UNSET, false},
+		{"Entregue", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsKnown(); got != tt.want {
+				t.Errorf("%q.IsKnown() = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
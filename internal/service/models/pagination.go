@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PAGE_DEFAULT_LIMIT is the page size when the client doesn't ask for one
+	PAGE_DEFAULT_LIMIT = 20
+	// PAGE_MAX_LIMIT keeps a single page cheap, larger limits are lowered to it
+	PAGE_MAX_LIMIT = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at a row of a list sorted by creation time and id. Clients get it encoded, as an opaque string.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+	// Backward asks for the rows before the cursor instead of the ones after it
+	Backward bool
+}
+
+func (c Cursor) IsZero() bool {
+	return c.ID == uuid.Nil
+}
+
+// Encode returns the opaque form handed to clients, empty for the zero cursor
+func (c Cursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+	direction := "n"
+	if c.Backward {
+		direction = "p"
+	}
+	raw := direction + "|" + c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reads a cursor given by Encode, an empty string is the first page
+func DecodeCursor(in string) (Cursor, error) {
+	if in == "" {
+		return Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(in)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || (parts[0] != "n" && parts[0] != "p") {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	out := Cursor{Backward: parts[0] == "p"}
+	if out.CreatedAt, err = time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if out.ID, err = uuid.Parse(parts[2]); err != nil || out.ID == uuid.Nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return out, nil
+}
+
+// PageRequest asks for the rows next to Cursor, the first ones when it is zero
+type PageRequest struct {
+	Limit  int
+	Cursor Cursor
+}
+
+// Normalized applies the default and maximum page sizes
+func (p PageRequest) Normalized() PageRequest {
+	if p.Limit <= 0 {
+		p.Limit = PAGE_DEFAULT_LIMIT
+	}
+	if p.Limit > PAGE_MAX_LIMIT {
+		p.Limit = PAGE_MAX_LIMIT
+	}
+	return p
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 15, 123456789, time.FixedZone("BRT", -3*60*60))
+	id := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+
+	tests := []struct {
+		name string
+		in   Cursor
+	}{
+		{"forward", Cursor{CreatedAt: createdAt, ID: id}},
+		{"backward", Cursor{CreatedAt: createdAt, ID: id, Backward: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.in.Encode()
+			if encoded == "" {
+				t.Fatal("Encode() is empty")
+			}
+
+			got, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.in.CreatedAt) || got.ID != tt.in.ID || got.Backward != tt.in.Backward {
+				t.Errorf("DecodeCursor() = %+v, want %+v", got, tt.in)
+			}
+		})
+	}
+}
+
+func TestCursorZero(t *testing.T) {
+	if got := (Cursor{CreatedAt: time.Now()}).Encode(); got != "" {
+		t.Errorf("Encode() of a zero cursor = %q, want empty", got)
+	}
+
+	got, err := DecodeCursor("")
+	if err != nil || !got.IsZero() {
+		t.Errorf("DecodeCursor(\"\") = %+v, %v, want the zero cursor", got, err)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	id := "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"not base64", "not base64!"},
+		{"missing parts", encode("n|2024-03-01T12:00:00Z")},
+		{"unknown direction", encode("x|2024-03-01T12:00:00Z|" + id)},
+		{"invalid time", encode("n|yesterday|" + id)},
+		{"invalid id", encode("n|2024-03-01T12:00:00Z|42")},
+		{"nil id", encode("n|2024-03-01T12:00:00Z|" + uuid.Nil.String())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.in); err != ErrInvalidCursor {
+				t.Errorf("DecodeCursor(%q) error = %v, want %v", tt.in, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestPageRequestNormalized(t *testing.T) {
+	tests := []struct {
+		limit, want int
+	}{
+		{0, PAGE_DEFAULT_LIMIT},
+		{-5, PAGE_DEFAULT_LIMIT},
+		{10, 10},
+		{PAGE_MAX_LIMIT, PAGE_MAX_LIMIT},
+		{PAGE_MAX_LIMIT + 1, PAGE_MAX_LIMIT},
+	}
+
+	for _, tt := range tests {
+		if got := (PageRequest{Limit: tt.limit}).Normalized().Limit; got != tt.want {
+			t.Errorf("PageRequest{Limit: %d}.Normalized().Limit = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PickupPanelEntry is what the public panel shows of an order, never its contents
+type PickupPanelEntry struct {
+	PickupNumber int
+	CustomerName string
+	Status       OrderStatus
+	UpdatedAt    time.Time
+}
+
+type PickupPanel struct {
+	Preparing   []PickupPanelEntry
+	Ready       []PickupPanelEntry
+	GeneratedAt time.Time
+}
@@ -14,27 +14,159 @@ type Payment struct {
 	Price     decimal.Decimal
 	OrderID   uuid.UUID
 	Status    PaymentStatus
+
+	// Attempt counts the payments created for the same order, starting at 1
+	Attempt  int
+	Timeline []PaymentEvent
+
+	// SplitID groups the parts of a split bill, every part is a separate charge. It is Nil for single payments
+	SplitID uuid.UUID
+	Payer   string
+
+	// PixCode is the "copia e cola" of the charge while pending, never stored
+	PixCode string
+}
+
+// PaymentPart is the share of a split bill taken by one payer, either a fixed Amount or the price of Items
+type PaymentPart struct {
+	Payer  string
+	Amount decimal.Decimal
+	Items  []PaymentPartItem
+}
+
+type PaymentPartItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// SplitPaidAmount sums the approved parts of a split bill
+func SplitPaidAmount(parts []*Payment) decimal.Decimal {
+	paid := decimal.Zero
+	for _, p := range parts {
+		if p.Status == PAYMENT_STATUS_APPROVED {
+			paid = paid.Add(p.Price)
+		}
+	}
+	return paid
 }
 
+// PaymentEvent is an entry of the append-only history of a payment
+type PaymentEvent struct {
+	PaymentID  uuid.UUID
+	Kind       PaymentEventKind
+	Status     PaymentStatus
+	OccurredAt time.Time
+}
+
+type PaymentEventKind string
+
+const (
+	PAYMENT_EVENT_CREATED        PaymentEventKind = "created"
+	PAYMENT_EVENT_SENT           PaymentEventKind = "sent"
+	PAYMENT_EVENT_STATUS_CHANGED PaymentEventKind = "status_changed"
+)
+
 type PaymentStatus string
 
 const (
-	PAYMENT_STATUS_OPEN     PaymentStatus = "Aberto"
-	PAYMENT_STATUS_APPROVED               = "Aprovado"
-	PAYMENT_SATUS_REFUSED                 = "Recusado"
+	PAYMENT_STATUS_OPEN               PaymentStatus = "Aberto"
+	PAYMENT_STATUS_APPROVED                         = "Aprovado"
+	PAYMENT_SATUS_REFUSED                           = "Recusado"
+	PAYMENT_STATUS_CANCELED                         = "Cancelado"
+	PAYMENT_STATUS_REFUND_REQUESTED                 = "Estorno Solicitado"
+	PAYMENT_STATUS_REFUNDED                         = "Estornado"
+	PAYMENT_STATUS_PARTIALLY_REFUNDED               = "Parcialmente Estornado"
+	PAYMENT_STATUS_PROCESSING                       = "Processando"
+	PAYMENT_STATUS_EXPIRED                          = "Expirado"
+	PAYMENT_STATUS_FAILED                           = "Falha"
+)
+
+// reasons sent along when the service voids a charge by itself
+const (
+	PAYMENT_CANCEL_REASON_CHECKOUT_FAILED = "checkout_failed"
+)
+
+// statuses msvc-payments sends that its api package does not declare yet
+const (
+	clearingStatusProcessing api.PaymentStatus = "processing"
+	clearingStatusExpired    api.PaymentStatus = "expired"
+	clearingStatusError      api.PaymentStatus = "error"
+	clearingStatusRefused    api.PaymentStatus = "refused"
 )
 
+// IsRefundable reports whether money can still be given back from a payment in status s
+func (s PaymentStatus) IsRefundable() bool {
+	switch s {
+	case PAYMENT_STATUS_APPROVED, PAYMENT_STATUS_PARTIALLY_REFUNDED, PAYMENT_STATUS_REFUND_REQUESTED:
+		return true
+	default:
+		return false
+	}
+}
+
+// paymentStatusStages orders the payment statuses along the life of a charge: pending, settled by
+// msvc-payments and then refunded. A payment never goes back to an earlier stage.
+var paymentStatusStages = map[PaymentStatus]int{
+	PAYMENT_STATUS_OPEN:               0,
+	PAYMENT_STATUS_PROCESSING:         1,
+	PAYMENT_STATUS_APPROVED:           2,
+	PAYMENT_SATUS_REFUSED:             2,
+	PAYMENT_STATUS_FAILED:             2,
+	PAYMENT_STATUS_EXPIRED:            2,
+	PAYMENT_STATUS_CANCELED:           2,
+	PAYMENT_STATUS_REFUND_REQUESTED:   3,
+	PAYMENT_STATUS_PARTIALLY_REFUNDED: 4,
+	PAYMENT_STATUS_REFUNDED:           5,
+}
+
+// CanMoveTo reports whether a payment in status s takes a status reported by msvc-payments, telling
+// replayed and out of order notifications apart. Once settled a payment only changes again when money
+// arrives for a charge already canceled or expired here, so that it can be refunded.
+func (s PaymentStatus) CanMoveTo(next PaymentStatus) bool {
+	if s == next {
+		return false
+	}
+	if stage, nextStage := paymentStatusStages[s], paymentStatusStages[next]; stage != nextStage {
+		return stage < nextStage
+	}
+	return next == PAYMENT_STATUS_APPROVED && (s == PAYMENT_STATUS_CANCELED || s == PAYMENT_STATUS_EXPIRED)
+}
+
+// PaymentStatusFromClearingService maps every status msvc-payments reports. Unknown statuses are
+// treated as failures so the customer can still retry instead of being refused for good.
 func PaymentStatusFromClearingService(status string) PaymentStatus {
-	switch status {
-	case string(api.PaymentStatusPaid):
+	switch api.PaymentStatus(status) {
+	case api.PaymentStatusPaid:
 		return PAYMENT_STATUS_APPROVED
-	case string(api.PaymentStatusPending):
+	case api.PaymentStatusPending:
 		return PAYMENT_STATUS_OPEN
-	default:
+	case clearingStatusProcessing:
+		return PAYMENT_STATUS_PROCESSING
+	case clearingStatusExpired:
+		return PAYMENT_STATUS_EXPIRED
+	case clearingStatusRefused:
 		return PAYMENT_SATUS_REFUSED
+	default:
+		return PAYMENT_STATUS_FAILED
+	}
+}
+
+// IsClearingStatusKnown reports whether msvc-payments documents status, anything else is mapped to a failure
+func IsClearingStatusKnown(status string) bool {
+	switch api.PaymentStatus(status) {
+	case api.PaymentStatusPaid, api.PaymentStatusPending, api.PaymentStatusFailed,
+		clearingStatusProcessing, clearingStatusExpired, clearingStatusError, clearingStatusRefused:
+		return true
+	default:
+		return false
 	}
 }
 
+// IsPending reports whether the charge may still be paid
+func (s PaymentStatus) IsPending() bool {
+	return s == PAYMENT_STATUS_OPEN || s == PAYMENT_STATUS_PROCESSING
+}
+
 type PaymentStatusNotification struct {
 	PaymentID uuid.UUID
 	OrderID   uuid.UUID
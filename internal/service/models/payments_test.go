@@ -0,0 +1,34 @@
+package models
+
+import "testing"
+
+func TestPaymentStatusCanMoveTo(t *testing.T) {
+	tests := []struct {
+		from, to PaymentStatus
+		want     bool
+	}{
+		{PAYMENT_STATUS_OPEN, PAYMENT_STATUS_PROCESSING, true},
+		{PAYMENT_STATUS_OPEN, PAYMENT_STATUS_APPROVED, true},
+		{PAYMENT_STATUS_PROCESSING, PAYMENT_SATUS_REFUSED, true},
+		{PAYMENT_STATUS_OPEN, PAYMENT_STATUS_OPEN, false},
+		{PAYMENT_STATUS_APPROVED, PAYMENT_STATUS_APPROVED, false},
+		{PAYMENT_STATUS_PROCESSING, PAYMENT_STATUS_OPEN, false},
+		{PAYMENT_STATUS_APPROVED, PAYMENT_STATUS_OPEN, false},
+		{PAYMENT_STATUS_APPROVED, PAYMENT_STATUS_FAILED, false},
+		{PAYMENT_SATUS_REFUSED, PAYMENT_STATUS_APPROVED, false},
+		{PAYMENT_STATUS_CANCELED, PAYMENT_STATUS_OPEN, false},
+		{PAYMENT_STATUS_CANCELED, PAYMENT_STATUS_APPROVED, true},
+		{PAYMENT_STATUS_EXPIRED, PAYMENT_STATUS_APPROVED, true},
+		{PAYMENT_STATUS_REFUNDED, PAYMENT_STATUS_APPROVED, false},
+		{PAYMENT_STATUS_REFUND_REQUESTED, PAYMENT_STATUS_APPROVED, false},
+		{PAYMENT_STATUS_PARTIALLY_REFUNDED, PAYMENT_STATUS_OPEN, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanMoveTo(tt.to); got != tt.want {
+				t.Errorf("%q.CanMoveTo(%q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
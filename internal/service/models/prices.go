@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type PriceStatus string
+
+const (
+	// PRICE_STATUS_SCHEDULED waits for EffectiveAt to become the product price
+	PRICE_STATUS_SCHEDULED PriceStatus = "scheduled"
+	// PRICE_STATUS_APPLIED was the product price from EffectiveAt until the next applied one
+	PRICE_STATUS_APPLIED PriceStatus = "applied"
+	// PRICE_STATUS_SUPERSEDED came due together with a later scheduled price and was never charged
+	PRICE_STATUS_SUPERSEDED PriceStatus = "superseded"
+	PRICE_STATUS_CANCELED   PriceStatus = "canceled"
+)
+
+// ProductPrice is an entry of the price history of a product, past or scheduled
+type ProductPrice struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	ProductID   uuid.UUID
+	Price       decimal.Decimal
+	EffectiveAt time.Time
+	Status      PriceStatus
+	// AppliedAt is when the product price was actually changed, the job may run a little after EffectiveAt
+	AppliedAt time.Time
+}
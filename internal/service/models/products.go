@@ -14,12 +14,55 @@ type Product struct {
 	Name        string
 	Description string
 	Price       decimal.Decimal
+
+	// Available is switched off when the kitchen runs out, UnavailableUntil brings it back by itself when set
+	Available        bool
+	UnavailableUntil time.Time
+	// OutOfStock is kept by the inventory, set while any ingredient of the recipe lacks stock for one more unit
+	OutOfStock bool
+	// DeletedAt is set once the product is archived, it leaves the catalog but orders still resolve it
+	DeletedAt time.Time
+
+	// Window limits the hours of the day the product can be ordered, zero for all day
+	Window TimeWindow
+	// CategoryWindow is inherited from the nearest category with a window, used when the product has none
+	CategoryWindow TimeWindow
+}
+
+// OrderableWindow is the window the product follows, its own or the one of its category
+func (p Product) OrderableWindow() TimeWindow {
+	if !p.Window.IsZero() {
+		return p.Window
+	}
+	return p.CategoryWindow
+}
+
+// InWindow reports whether c, a time of day in the store timezone, is within the hours the product is sold
+func (p Product) InWindow(c ClockTime) bool {
+	w := p.OrderableWindow()
+	return w.IsZero() || w.Contains(c)
+}
+
+// IsArchived reports whether the product was deleted from the catalog
+func (p Product) IsArchived() bool {
+	return !p.DeletedAt.IsZero()
+}
+
+// IsAvailable reports whether the product can be ordered at now
+func (p Product) IsAvailable(now time.Time) bool {
+	if p.OutOfStock || p.IsArchived() {
+		return false
+	}
+	return p.Available || (!p.UnavailableUntil.IsZero() && !now.Before(p.UnavailableUntil))
 }
 
 type ProductList struct {
 	Products      []*Product
 	Limit, Offset int
 	Total         int64
+
+	// Next and Prev are the cursors of the pages around this one, zero at the ends of the list
+	Next, Prev Cursor
 }
 
 type ProductsSum struct {
@@ -27,3 +70,53 @@ type ProductsSum struct {
 	RequestedAt time.Time
 	Sum         decimal.Decimal
 }
+
+type ProductSort string
+
+const (
+	// PRODUCT_SORT_RELEVANCE ranks the best matches of the text first, it falls back to name without a text
+	PRODUCT_SORT_RELEVANCE  ProductSort = "relevance"
+	PRODUCT_SORT_NAME       ProductSort = "name"
+	PRODUCT_SORT_PRICE_ASC  ProductSort = "price_asc"
+	PRODUCT_SORT_PRICE_DESC ProductSort = "price_desc"
+	PRODUCT_SORT_NEWEST     ProductSort = "newest"
+)
+
+func (s ProductSort) IsValid() bool {
+	switch s {
+	case PRODUCT_SORT_RELEVANCE, PRODUCT_SORT_NAME, PRODUCT_SORT_PRICE_ASC, PRODUCT_SORT_PRICE_DESC, PRODUCT_SORT_NEWEST:
+		return true
+	}
+	return false
+}
+
+type ProductAvailability string
+
+const (
+	// PRODUCT_AVAILABILITY_AVAILABLE keeps the products that can be ordered now, like the listings
+	PRODUCT_AVAILABILITY_AVAILABLE   ProductAvailability = "available"
+	PRODUCT_AVAILABILITY_UNAVAILABLE ProductAvailability = "unavailable"
+	PRODUCT_AVAILABILITY_ALL         ProductAvailability = "all"
+)
+
+func (a ProductAvailability) IsValid() bool {
+	switch a {
+	case PRODUCT_AVAILABILITY_AVAILABLE, PRODUCT_AVAILABILITY_UNAVAILABLE, PRODUCT_AVAILABILITY_ALL:
+		return true
+	}
+	return false
+}
+
+// ProductSearch filters the catalog, zero values don't filter. Archived products are never found.
+type ProductSearch struct {
+	// Query is matched against name and description in Portuguese, ignoring accents
+	Query string
+	// CategoryID also finds the products of its subcategories
+	CategoryID   uuid.UUID
+	MinPrice     decimal.NullDecimal
+	MaxPrice     decimal.NullDecimal
+	Availability ProductAvailability
+	Sort         ProductSort
+
+	Limit, Offset int
+}
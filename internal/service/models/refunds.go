@@ -0,0 +1,75 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Refund returns part or all of an approved payment. Items is empty for refunds of the whole remaining amount.
+type Refund struct {
+	ID        uuid.UUID
+	PaymentID uuid.UUID
+	OrderID   uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Amount    decimal.Decimal
+	Items     []RefundItem
+	Reason    string
+	Status    RefundStatus
+}
+
+// RefundItem is a line of the order being given back, priced as it was sold
+type RefundItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Amount    decimal.Decimal
+}
+
+// reasons of the refunds the service requests by itself
+const (
+	REFUND_REASON_SPLIT_PAYMENT_FAILED = "split_payment_failed"
+	REFUND_REASON_LATE_PAYMENT         = "late_payment"
+)
+
+type RefundStatus string
+
+const (
+	REFUND_STATUS_REQUESTED RefundStatus = "Solicitado"
+	REFUND_STATUS_COMPLETED RefundStatus = "Estornado"
+	REFUND_STATUS_FAILED    RefundStatus = "Recusado"
+)
+
+// RefundStatusFromClearingService maps the refund confirmations sent by msvc-payments
+func RefundStatusFromClearingService(status string) RefundStatus {
+	switch status {
+	case "refunded":
+		return REFUND_STATUS_COMPLETED
+	case "pending":
+		return REFUND_STATUS_REQUESTED
+	default:
+		return REFUND_STATUS_FAILED
+	}
+}
+
+// RefundedPaymentStatus is the status a payment of the given price ends up in after its refunds
+func RefundedPaymentStatus(price decimal.Decimal, refunds []*Refund) PaymentStatus {
+	refunded := decimal.Zero
+	for _, r := range refunds {
+		switch r.Status {
+		case REFUND_STATUS_REQUESTED:
+			return PAYMENT_STATUS_REFUND_REQUESTED
+		case REFUND_STATUS_COMPLETED:
+			refunded = refunded.Add(r.Amount)
+		}
+	}
+
+	switch {
+	case refunded.GreaterThanOrEqual(price):
+		return PAYMENT_STATUS_REFUNDED
+	case refunded.IsPositive():
+		return PAYMENT_STATUS_PARTIALLY_REFUNDED
+	default:
+		return PAYMENT_STATUS_APPROVED
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Store identifies the restaurant this instance serves and the timezone its days are counted in
+type Store struct {
+	ID       string
+	Location *time.Location
+
+	// ServiceChargePercentage is added to every new order when positive, customers may still waive it
+	ServiceChargePercentage decimal.Decimal
+}
+
+// Today returns the current store day truncated to midnight in the store timezone
+func (s Store) Today() time.Time {
+	return s.Day(time.Now())
+}
+
+// StartOf returns midnight of the calendar day of day in the store timezone, whatever zone day is in
+func (s Store) StartOf(day time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// Day returns the store day t belongs to, truncated to midnight in the store timezone
+func (s Store) Day(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
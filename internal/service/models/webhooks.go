@@ -0,0 +1,35 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// WebhookSubscription is an external endpoint notified of order status changes.
+// An empty Statuses list subscribes to every status.
+type WebhookSubscription struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	URL       string
+	Secret    string
+	Statuses  []OrderStatus
+}
+
+func (s *WebhookSubscription) Filter() OrderEventFilter {
+	return OrderEventFilter{Statuses: s.Statuses}
+}
+
+// WebhookDelivery is one attempt of sending an event to a subscription
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID uuid.UUID
+	EventID        uint64
+	OrderID        uuid.UUID
+	OrderStatus    OrderStatus
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Succeeded      bool
+	AttemptedAt    time.Time
+}
@@ -6,13 +6,16 @@ import (
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
 	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	ordermsgs "github.com/SOAT1StackGoLang/msvc-orders/pkg/messages"
 	"github.com/SOAT1StackGoLang/msvc-payments/pkg/datastore"
 	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
 	logger "github.com/SOAT1StackGoLang/msvc-payments/pkg/middleware"
 	productionmsgs "github.com/SOAT1StackGoLang/msvc-production/pkg/messages"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
@@ -21,6 +24,10 @@ type ordersSvc struct {
 	ordersRepo  persistence.OrdersRepository
 	productsSvc ProductsService
 	paymentsSvc PaymentsService
+	inventory   InventoryService
+	hours       StoreHoursService
+	events      OrderEventsHub
+	store       models.Store
 	log         kitlog.Logger
 }
 
@@ -28,14 +35,22 @@ func NewOrdersService(
 	repo persistence.OrdersRepository,
 	prodSvc ProductsService,
 	paySvc PaymentsService,
+	inventory InventoryService,
+	hours StoreHoursService,
 	log kitlog.Logger,
 	cache datastore.RedisStore,
+	events OrderEventsHub,
+	store models.Store,
 ) OrdersService {
 	svc := &ordersSvc{
 		cache:       cache,
 		ordersRepo:  repo,
 		productsSvc: prodSvc,
 		paymentsSvc: paySvc,
+		inventory:   inventory,
+		hours:       hours,
+		events:      events,
+		store:       store,
 		log:         log,
 	}
 
@@ -102,11 +117,18 @@ func (o *ordersSvc) GetOrder(ctx context.Context, id uuid.UUID) (*models.Order,
 	return o.ordersRepo.GetOrder(ctx, id)
 }
 
+func (o *ordersSvc) GetOrderByPickupNumber(ctx context.Context, number int, day time.Time) (*models.Order, error) {
+	if day.IsZero() {
+		day = o.store.Today()
+	}
+	return o.ordersRepo.GetOrderByPickupNumber(ctx, o.store.ID, day, number)
+}
+
 func (o *ordersSvc) GetOrderByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Order, error) {
 	return o.ordersRepo.GetOrderByPaymentID(ctx, paymentID)
 }
 
-func (o *ordersSvc) CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID) (*models.Order, error) {
+func (o *ordersSvc) CreateOrder(ctx context.Context, products []models.Product, userID uuid.UUID, customerName string) (*models.Order, error) {
 	var order *models.Order
 
 	if len(products) == 0 {
@@ -118,32 +140,106 @@ func (o *ordersSvc) CreateOrder(ctx context.Context, products []models.Product,
 		return nil, helpers.ErrInvalidInput
 	}
 
-	for k, p := range products {
+	if err := o.hours.EnsureOpen(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	products, err := o.orderableProducts(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	order = &models.Order{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		Status:    models.ORDER_STATUS_OPEN,
+		Products:  products,
+		StoreID:   o.store.ID,
+
+		CustomerName: strings.TrimSpace(customerName),
+	}
+	order.UserID = userID
+
+	if o.store.ServiceChargePercentage.IsPositive() {
+		order.SetAdjustment(models.Adjustment{
+			Kind:       models.ADJUSTMENT_KIND_SERVICE_CHARGE,
+			Percentage: o.store.ServiceChargePercentage,
+		})
+	}
+	order.Reprice()
+
+	return o.ordersRepo.CreateOrder(ctx, order)
+}
+
+// orderableProducts loads the requested products as they are sold now, refusing the ones out of the menu
+func (o *ordersSvc) orderableProducts(ctx context.Context, products []models.Product) ([]models.Product, error) {
+	now := time.Now()
+	clock := models.ClockTimeOf(now, o.store.Location)
+	out := make([]models.Product, 0, len(products))
+	for _, p := range products {
 		fullProduct, err := o.productsSvc.GetProduct(ctx, p.ID)
 		if err != nil {
-			o.log.Log("CreateOrder failed due to invalid product",
+			o.log.Log("order refused due to invalid product",
 				zap.String("product_id", p.ID.String()),
 				zap.Any("requested_products", products),
 				zap.Error(err),
 			)
 			return nil, err
 		}
-		products[k] = *fullProduct
+		if !fullProduct.IsAvailable(now) {
+			o.log.Log("order refused due to unavailable product",
+				zap.String("product_id", p.ID.String()),
+				zap.Time("unavailable_until", fullProduct.UnavailableUntil),
+				zap.Error(helpers.ErrProductUnavailable),
+			)
+			return nil, helpers.ErrProductUnavailable
+		}
+		if !fullProduct.InWindow(clock) {
+			window := fullProduct.OrderableWindow()
+			o.log.Log("order refused, product is out of its hours",
+				zap.String("product_id", p.ID.String()),
+				zap.String("available_from", window.From.String()),
+				zap.String("available_to", window.To.String()),
+				zap.Error(helpers.ErrProductUnavailable),
+			)
+			return nil, helpers.ErrProductUnavailable
+		}
+		out = append(out, *fullProduct)
 	}
 
-	order = &models.Order{
-		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		Status:    models.ORDER_STATUS_OPEN,
-		Products:  products,
+	return out, nil
+}
+
+// GetPickupPanel lists the numbers being prepared and ready for pickup today, finished orders leave the panel
+func (o *ordersSvc) GetPickupPanel(ctx context.Context) (*models.PickupPanel, error) {
+	entries, err := o.ordersRepo.ListPickupPanelEntries(ctx, o.store.ID, o.store.Today(), []models.OrderStatus{
+		models.ORDER_STATUS_PREPARING,
+		models.ORDER_STATUS_DONE,
+	})
+	if err != nil {
+		return nil, err
 	}
-	order.UserID = userID
 
-	for _, v := range products {
-		order.Price = order.Price.Add(v.Price)
+	panel := &models.PickupPanel{
+		Preparing:   make([]models.PickupPanelEntry, 0),
+		Ready:       make([]models.PickupPanelEntry, 0),
+		GeneratedAt: time.Now(),
 	}
+	for _, e := range entries {
+		// only the first name is shown on a public screen
+		if names := strings.Fields(e.CustomerName); len(names) > 0 {
+			e.CustomerName = names[0]
+		}
 
-	return o.ordersRepo.CreateOrder(ctx, order)
+		switch e.Status {
+		case models.ORDER_STATUS_PREPARING:
+			panel.Preparing = append(panel.Preparing, e)
+		case models.ORDER_STATUS_DONE:
+			panel.Ready = append(panel.Ready, e)
+		}
+	}
+
+	return panel, nil
 }
 
 func (o *ordersSvc) UpdateOrderItems(ctx context.Context, orderID uuid.UUID, products []models.Product) (*models.Order, error) {
@@ -151,6 +247,9 @@ func (o *ordersSvc) UpdateOrderItems(ctx context.Context, orderID uuid.UUID, pro
 	if err != nil {
 		return nil, err
 	}
+	if order.Status != models.ORDER_STATUS_OPEN {
+		return nil, helpers.ErrOrderNotEditable
+	}
 
 	if len(products) == 0 {
 		o.log.Log(
@@ -161,68 +260,260 @@ func (o *ordersSvc) UpdateOrderItems(ctx context.Context, orderID uuid.UUID, pro
 		return nil, helpers.ErrBadRequest
 	}
 
-	for _, v := range products {
-		order.Products = append(order.Products, v)
-		order.Price = order.Price.Add(v.Price)
+	if products, err = o.orderableProducts(ctx, products); err != nil {
+		return nil, err
 	}
 
+	order.Products = append(order.Products, products...)
+	order.Reprice()
+
 	return o.ordersRepo.UpdateOrder(ctx, order)
 }
 
-func (o *ordersSvc) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
-	err := o.publishMessage(context.Background(), productionmsgs.OrderSentMessage{
-		OrderID: orderID.String(),
-		Status:  models.ORDER_STATUS_CANCELED,
-	}, productionmsgs.ProductionChannel)
-	if err != nil {
+// UpdateOrderAdjustments sets the customer tip and whether the store service charge applies, a nil one is kept
+// as it is. Both can only change before checkout since the payment is created for the total.
+func (o *ordersSvc) UpdateOrderAdjustments(ctx context.Context, orderID uuid.UUID, tip *models.Adjustment, serviceCharge *bool) (*models.Order, error) {
+	if tip != nil && (tip.Amount.IsNegative() || tip.Percentage.IsNegative() || tip.Percentage.GreaterThan(decimal.NewFromInt(100)) ||
+		(tip.Amount.IsPositive() && tip.IsPercentage())) {
 		o.log.Log(
-			"error publishing message",
-			err,
+			"error at UpdateOrderAdjustments, invalid tip",
+			zap.String("order_id", orderID.String()),
+			zap.String("amount", tip.Amount.String()),
+			zap.String("percentage", tip.Percentage.String()),
+			zap.Error(helpers.ErrInvalidInput),
 		)
+		return nil, helpers.ErrInvalidInput
 	}
 
 	order, err := o.GetOrder(ctx, orderID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if order.Status != models.ORDER_STATUS_OPEN {
+		return nil, helpers.ErrOrderNotEditable
 	}
 
-	_, err = o.paymentsSvc.UpdatePayment(ctx, order.PaymentID, models.PAYMENT_SATUS_REFUSED)
+	if tip != nil {
+		tip.Kind = models.ADJUSTMENT_KIND_TIP
+		tip.Amount = tip.Amount.Round(2)
+		order.SetAdjustment(*tip)
+	}
+
+	if serviceCharge != nil {
+		charge := models.Adjustment{Kind: models.ADJUSTMENT_KIND_SERVICE_CHARGE}
+		if *serviceCharge {
+			charge.Percentage = o.store.ServiceChargePercentage
+		}
+		order.SetAdjustment(charge)
+	}
+
+	order.Reprice()
+	order.UpdatedAt = time.Now()
+
+	return o.ordersRepo.UpdateOrder(ctx, order)
+}
+
+// DeleteOrder cancels the order as a customer request, unless it is already closed, and archives it
+func (o *ordersSvc) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := o.GetOrder(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
+	if order.Status != models.ORDER_STATUS_CANCELED && order.Status != models.ORDER_STATUS_FINISHED {
+		if _, err = o.cancelOrder(ctx, order, models.CANCEL_REASON_CUSTOMER_REQUEST, models.CANCEL_ACTOR_API); err != nil {
+			return err
+		}
+	}
+
 	return o.ordersRepo.DeleteOrder(ctx, orderID)
 }
 
-func (o *ordersSvc) ListOrders(ctx context.Context, limit, offset int) (*models.OrderList, error) {
-	return o.ordersRepo.ListOrders(ctx, limit, offset)
+func (o *ordersSvc) CancelOrder(ctx context.Context, orderID uuid.UUID, reason models.CancelReason, actor string) (*models.Order, error) {
+	if !reason.IsRequestable() || strings.TrimSpace(actor) == "" {
+		o.log.Log(
+			"error at CancelOrder, invalid reason or actor",
+			zap.String("order_id", orderID.String()),
+			zap.String("reason", string(reason)),
+			zap.String("actor", actor),
+			zap.Error(helpers.ErrInvalidInput),
+		)
+		return nil, helpers.ErrInvalidInput
+	}
+
+	order, err := o.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.cancelOrder(ctx, order, reason, strings.TrimSpace(actor))
 }
 
-func (o *ordersSvc) Checkout(ctx context.Context, id uuid.UUID) (*models.Order, error) {
-	var order *models.Order
+func (o *ordersSvc) ListOrders(ctx context.Context, page models.PageRequest) (*models.OrderList, error) {
+	return o.ordersRepo.ListOrders(ctx, page.Normalized())
+}
 
+// SearchOrders refuses unknown statuses and empty date or price ranges before reaching the database
+func (o *ordersSvc) SearchOrders(ctx context.Context, in models.OrderSearch) (*models.OrderList, error) {
+	for _, s := range in.Statuses {
+		if !s.IsKnown() {
+			return nil, helpers.ErrInvalidInput
+		}
+	}
+	if !in.FromDay.IsZero() {
+		in.CreatedFrom = o.store.StartOf(in.FromDay)
+	}
+	if !in.ToDay.IsZero() {
+		in.CreatedTo = o.store.StartOf(in.ToDay).AddDate(0, 0, 1)
+	}
+	if !in.CreatedFrom.IsZero() && !in.CreatedTo.IsZero() && !in.CreatedFrom.Before(in.CreatedTo) {
+		return nil, helpers.ErrInvalidInput
+	}
+	if in.MinPrice.Valid && in.MaxPrice.Valid && in.MinPrice.Decimal.GreaterThan(in.MaxPrice.Decimal) {
+		return nil, helpers.ErrInvalidInput
+	}
+	if (in.MinPrice.Valid && in.MinPrice.Decimal.IsNegative()) || (in.MaxPrice.Valid && in.MaxPrice.Decimal.IsNegative()) {
+		return nil, helpers.ErrInvalidInput
+	}
+	in.CustomerName = strings.TrimSpace(in.CustomerName)
+	in.Page = in.Page.Normalized()
+
+	return o.ordersRepo.SearchOrders(ctx, in)
+}
+
+func (o *ordersSvc) Checkout(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	order, err := o.GetOrder(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	order.Status = models.ORDER_STATUS_WAITING_PAYMENT
+	if !order.Status.CanTransitionTo(models.ORDER_STATUS_WAITING_PAYMENT) {
+		o.log.Log(
+			"refusing checkout",
+			zap.String("order_id", id.String()),
+			zap.String("status", string(order.Status)),
+			zap.Error(helpers.ErrInvalidStatusTransition),
+		)
+		return nil, helpers.ErrInvalidStatusTransition
+	}
 
+	if err = o.inventory.ReserveOrder(ctx, order); err != nil {
+		return nil, err
+	}
 	payment, err := o.paymentsSvc.CreatePayment(ctx, order)
 	if err != nil {
+		o.releaseStock(ctx, order.ID)
 		return nil, err
 	}
-	order.PaymentID = payment.ID
+
+	if order, err = o.awaitPayment(ctx, order, payment.ID); err != nil {
+		o.abandonCheckout(ctx, id, payment)
+		return nil, err
+	}
+	order.PixCode = o.pixCode(payment)
+
+	return order, nil
+}
+
+// SplitCheckout charges every part of the bill separately, the order is received once the approved parts cover its price
+func (o *ordersSvc) SplitCheckout(ctx context.Context, id uuid.UUID, parts []models.PaymentPart) (*models.Order, error) {
+	order, err := o.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(models.ORDER_STATUS_WAITING_PAYMENT) {
+		o.log.Log(
+			"refusing split checkout",
+			zap.String("order_id", id.String()),
+			zap.String("status", string(order.Status)),
+			zap.Error(helpers.ErrInvalidStatusTransition),
+		)
+		return nil, helpers.ErrInvalidStatusTransition
+	}
+
+	if err = o.inventory.ReserveOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	payments, err := o.paymentsSvc.CreateSplitPayments(ctx, order, parts)
+	if err != nil {
+		o.releaseStock(ctx, order.ID)
+		return nil, err
+	}
+
+	// the first part stands for the bill on the order, the others are found through it
+	if order, err = o.awaitPayment(ctx, order, payments[0].ID); err != nil {
+		o.abandonCheckout(ctx, id, payments...)
+		return nil, err
+	}
+	for _, p := range payments {
+		p.PixCode = o.pixCode(p)
+	}
+	order.PaymentParts = payments
+
+	return order, nil
+}
+
+// awaitPayment moves the order to Aguardando Pagamento on the charge just sent, giving it a pickup number on its first checkout
+func (o *ordersSvc) awaitPayment(ctx context.Context, order *models.Order, paymentID uuid.UUID) (*models.Order, error) {
+	var err error
+
+	previous := order.Status
+	if !previous.CanTransitionTo(models.ORDER_STATUS_WAITING_PAYMENT) {
+		o.log.Log(
+			"refusing to await payment",
+			zap.String("order_id", order.ID.String()),
+			zap.String("status", string(previous)),
+			zap.Error(helpers.ErrInvalidStatusTransition),
+		)
+		return nil, helpers.ErrInvalidStatusTransition
+	}
+	order.Status = models.ORDER_STATUS_WAITING_PAYMENT
+	order.PaymentID = paymentID
+
+	if order.PickupNumber == 0 {
+		if order.PickupNumber, err = o.ordersRepo.NextPickupNumber(ctx, o.store.ID, o.store.Today()); err != nil {
+			return nil, err
+		}
+		order.StoreID = o.store.ID
+		order.PickupDate = o.store.Today()
+	}
 
 	order.UpdatedAt = time.Now()
-	order, err = o.ordersRepo.UpdateOrder(ctx, order)
+	out, err := o.ordersRepo.UpdateOrder(ctx, order)
 	if err != nil {
 		o.log.Log(
 			"failed updating order status after checkout",
 			zap.Error(err),
 		)
+		return nil, err
+	}
+	o.publishStatusChange(previous, out)
+
+	return out, nil
+}
+
+// abandonCheckout voids the charges just sent for an order that could not be moved to Aguardando Pagamento
+// and gives back its stock, failures are logged to be handled by hand
+func (o *ordersSvc) abandonCheckout(ctx context.Context, orderID uuid.UUID, payments ...*models.Payment) {
+	o.releaseStock(ctx, orderID)
+	for _, p := range payments {
+		if _, err := o.paymentsSvc.CancelPayment(ctx, p.ID, models.PAYMENT_CANCEL_REASON_CHECKOUT_FAILED); err != nil {
+			o.log.Log(
+				"failed canceling payment of abandoned checkout",
+				zap.String("order_id", orderID.String()),
+				zap.String("payment_id", p.ID.String()),
+				zap.Error(err),
+			)
+		}
 	}
+}
 
-	return order, err
+// pixCode is best effort, customers can still pay through msvc-payments without it
+func (o *ordersSvc) pixCode(payment *models.Payment) string {
+	code, err := o.paymentsSvc.PixPayload(payment)
+	if err != nil {
+		return ""
+	}
+	return code
 }
 
 func (o *ordersSvc) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status models.OrderStatus) (*models.Order, error) {
@@ -231,10 +522,279 @@ func (o *ordersSvc) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, st
 		return nil, err
 	}
 
+	if order.Status == status {
+		return order, nil
+	}
+	if !order.Status.CanTransitionTo(status) {
+		o.log.Log(
+			"refusing order status transition",
+			zap.String("order_id", orderID.String()),
+			zap.String("from", string(order.Status)),
+			zap.String("to", string(status)),
+			zap.Error(helpers.ErrInvalidStatusTransition),
+		)
+		return nil, helpers.ErrInvalidStatusTransition
+	}
+
+	previous := order.Status
 	order.Status = status
 	order.UpdatedAt = time.Now()
 
-	return o.ordersRepo.UpdateOrder(ctx, order)
+	out, err := o.ordersRepo.UpdateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	o.publishStatusChange(previous, out)
+
+	return out, nil
+}
+
+func (o *ordersSvc) ListOrderPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	if _, err := o.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return o.paymentsSvc.ListPayments(ctx, orderID)
+}
+
+// RetryPayment charges an order whose last payment was refused or failed again, as a new payment attempt
+func (o *ordersSvc) RetryPayment(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	order, err := o.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.ORDER_STATUS_FAILED_PAYMENT && order.Status != models.ORDER_STATUS_PAYMENT_REFUSED {
+		o.log.Log(
+			"refusing payment retry",
+			zap.String("order_id", orderID.String()),
+			zap.String("status", string(order.Status)),
+			zap.Error(helpers.ErrPaymentNotRetryable),
+		)
+		return nil, helpers.ErrPaymentNotRetryable
+	}
+
+	if err = o.inventory.ReserveOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	payment, err := o.paymentsSvc.CreatePayment(ctx, order)
+	if err != nil {
+		o.releaseStock(ctx, order.ID)
+		return nil, err
+	}
+
+	out, err := o.awaitPayment(ctx, order, payment.ID)
+	if err != nil {
+		o.abandonCheckout(ctx, orderID, payment)
+		return nil, err
+	}
+	out.PixCode = o.pixCode(payment)
+
+	o.log.Log("Payment retried",
+		zap.String("order_id", out.ID.String()),
+		zap.String("payment_id", payment.ID.String()),
+		zap.Int("attempt", payment.Attempt),
+	)
+
+	return out, nil
+}
+
+// RefundOrder gives back part of a paid order, or all of it when items is empty, without changing its status.
+// Split bills are refunded part by part, paymentID picks the part and defaults to the payment recorded on the order.
+func (o *ordersSvc) RefundOrder(ctx context.Context, orderID, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error) {
+	order, err := o.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if paymentID == uuid.Nil {
+		paymentID = order.PaymentID
+	}
+	if paymentID == uuid.Nil {
+		return nil, helpers.ErrRefundNotAllowed
+	}
+
+	return o.paymentsSvc.RequestRefund(ctx, order, paymentID, items, reason)
+}
+
+func (o *ordersSvc) ListOrderRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error) {
+	if _, err := o.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return o.paymentsSvc.ListRefunds(ctx, orderID)
+}
+
+// cancelOrder closes an order recording why and who asked for it, compensating what the order went
+// through so far: an open order is just closed, a pending charge is canceled and an order production
+// already received is withdrawn from the kitchen and has its payment refunded
+func (o *ordersSvc) cancelOrder(ctx context.Context, order *models.Order, reason models.CancelReason, actor string) (*models.Order, error) {
+	if !order.Status.CanTransitionTo(models.ORDER_STATUS_CANCELED) {
+		o.log.Log(
+			"refusing to cancel order",
+			zap.String("order_id", order.ID.String()),
+			zap.String("status", string(order.Status)),
+			zap.Error(helpers.ErrOrderNotCancelable),
+		)
+		return nil, helpers.ErrOrderNotCancelable
+	}
+
+	previous := order.Status
+	now := time.Now()
+	order.Status = models.ORDER_STATUS_CANCELED
+	order.CancelReason = reason
+	order.CanceledBy = actor
+	order.CanceledAt = now
+	order.UpdatedAt = now
+
+	out, err := o.ordersRepo.UpdateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	o.publishStatusChange(previous, out)
+
+	o.log.Log("Order canceled",
+		zap.String("order_id", out.ID.String()),
+		zap.String("previous_status", string(previous)),
+		zap.String("reason", string(reason)),
+		zap.String("actor", actor),
+	)
+
+	// the order is already canceled, failed compensations are logged to be handled by hand.
+	// Stock already committed stays consumed, the kitchen may have used it.
+	o.releaseStock(ctx, out.ID)
+	switch previous {
+	case models.ORDER_STATUS_WAITING_PAYMENT:
+		o.compensatePayments(ctx, out, string(reason))
+	case models.ORDER_STATUS_RECEIVED, models.ORDER_STATUS_PREPARING:
+		if err = o.publishMessage(ctx, productionMessage(out), productionmsgs.ProductionChannel); err != nil {
+			o.log.Log(
+				"failed notifying production of canceled order",
+				zap.String("order_id", out.ID.String()),
+				zap.Error(err),
+			)
+		}
+		o.compensatePayments(ctx, out, string(reason))
+	}
+
+	return out, nil
+}
+
+// currentPayments returns the charge the order went through, or every part of it when the bill was split
+func (o *ordersSvc) currentPayments(ctx context.Context, order *models.Order) ([]*models.Payment, error) {
+	if order.PaymentID == uuid.Nil {
+		return nil, nil
+	}
+	payment, err := o.paymentsSvc.GetPayment(ctx, order.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.SplitID == uuid.Nil {
+		return []*models.Payment{payment}, nil
+	}
+	return o.paymentsSvc.ListSplitPayments(ctx, payment.SplitID)
+}
+
+// compensatePayments closes the charges of an order that will not be served: pending ones are canceled
+// and what msvc-payments already captured and was not refunded yet is given back
+func (o *ordersSvc) compensatePayments(ctx context.Context, order *models.Order, reason string) {
+	payments, err := o.currentPayments(ctx, order)
+	if err != nil {
+		o.log.Log(
+			"failed loading payments to compensate",
+			zap.String("order_id", order.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, p := range payments {
+		switch {
+		case p.Status.IsPending():
+			_, err = o.paymentsSvc.CancelPayment(ctx, p.ID, reason)
+		case p.Status.IsRefundable():
+			// refunds still pending count as given back, only the balance left is asked for
+			_, err = o.paymentsSvc.RequestRefund(ctx, order, p.ID, nil, reason)
+			if err == helpers.ErrRefundNotAllowed && p.Status == models.PAYMENT_STATUS_REFUND_REQUESTED {
+				continue
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			o.log.Log(
+				"failed compensating payment",
+				zap.String("order_id", order.ID.String()),
+				zap.String("payment_id", p.ID.String()),
+				zap.String("payment_status", string(p.Status)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// ExpireAbandonedOrders cancels open orders that never reached checkout within openTTL and orders
+// still waiting for a payment, or for a retry of a refused one, after paymentTimeout. A zero duration disables that rule.
+func (o *ordersSvc) ExpireAbandonedOrders(ctx context.Context, openTTL, paymentTimeout time.Duration) (int, error) {
+	rules := []struct {
+		status models.OrderStatus
+		ttl    time.Duration
+		reason models.CancelReason
+	}{
+		{models.ORDER_STATUS_OPEN, openTTL, models.CANCEL_REASON_OPEN_EXPIRED},
+		{models.ORDER_STATUS_WAITING_PAYMENT, paymentTimeout, models.CANCEL_REASON_PAYMENT_TIMEOUT},
+		{models.ORDER_STATUS_FAILED_PAYMENT, paymentTimeout, models.CANCEL_REASON_PAYMENT_TIMEOUT},
+		{models.ORDER_STATUS_PAYMENT_REFUSED, paymentTimeout, models.CANCEL_REASON_PAYMENT_TIMEOUT},
+	}
+
+	expired := 0
+	for _, rule := range rules {
+		if rule.ttl <= 0 {
+			continue
+		}
+
+		orders, err := o.ordersRepo.ListStaleOrders(ctx, rule.status, time.Now().Add(-rule.ttl), expirationBatchSize)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, order := range orders {
+			if _, err = o.cancelOrder(ctx, order, rule.reason, models.CANCEL_ACTOR_SYSTEM); err != nil {
+				o.log.Log(
+					"failed expiring order",
+					zap.String("order_id", order.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// releaseStock gives back what an order held at checkout, failures are logged since the
+// order moves on anyway and stock is fixed with an adjustment
+func (o *ordersSvc) releaseStock(ctx context.Context, orderID uuid.UUID) {
+	if err := o.inventory.ReleaseOrder(ctx, orderID); err != nil {
+		o.log.Log(
+			"failed releasing order stock",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// publishStatusChange feeds the in-process events hub consumed by the streaming endpoints
+func (o *ordersSvc) publishStatusChange(previous models.OrderStatus, order *models.Order) {
+	if o.events == nil || previous == order.Status {
+		return
+	}
+	o.events.Publish(models.OrderEvent{
+		OrderID:        order.ID,
+		Status:         order.Status,
+		PreviousStatus: previous,
+		OccurredAt:     order.UpdatedAt,
+	})
 }
 
 func (o *ordersSvc) SubscribeToPaymentUpdates() {
@@ -255,14 +815,9 @@ func (o *ordersSvc) SubscribeToPaymentUpdates() {
 }
 
 func (o *ordersSvc) handlePaymentStatusChangedMessage(msg string) {
-	var (
-		in  messages.PaymentStatusChangedMessage
-		err error
-		out *models.Order
-	)
+	var in messages.PaymentStatusChangedMessage
 
-	err = json.Unmarshal([]byte(msg), &in)
-	if err != nil {
+	if err := json.Unmarshal([]byte(msg), &in); err != nil {
 		o.log.Log(
 			"error unmarshalling order status update",
 			err,
@@ -270,47 +825,182 @@ func (o *ordersSvc) handlePaymentStatusChangedMessage(msg string) {
 		return
 	}
 
+	o.ProcessPaymentStatusChange(context.Background(), in)
+}
+
+// ProcessPaymentStatusChange applies a status reported by msvc-payments to the payment and moves its order
+// along the saga. It is shared by every way notifications arrive, replayed and outdated statuses are ignored.
+func (o *ordersSvc) ProcessPaymentStatusChange(ctx context.Context, in messages.PaymentStatusChangedMessage) error {
+	var out *models.Order
+
+	paymentID, err := uuid.Parse(in.ID)
+	if err != nil {
+		o.log.Log(
+			"error parsing payment id",
+			zap.String("payment_id", in.ID),
+			zap.Error(err),
+		)
+		return helpers.ErrInvalidInput
+	}
+	orderID, err := uuid.Parse(in.OrderID)
+	if err != nil {
+		o.log.Log(
+			"error parsing order id",
+			zap.String("order_id", in.OrderID),
+			zap.Error(err),
+		)
+		return helpers.ErrInvalidInput
+	}
+
 	status := models.PaymentStatusFromClearingService(in.Status)
+	payment, err := o.paymentsSvc.GetPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+	if payment.Status.CanMoveTo(status) {
+		payment, err = o.paymentsSvc.ChangePaymentStatus(ctx, payment, status)
+	} else {
+		err = helpers.ErrPaymentStatusChanged
+	}
+	if err == helpers.ErrPaymentStatusChanged {
+		// replayed, arrived after a later status or applied meanwhile by another copy, the saga already went past it
+		o.log.Log("Ignoring outdated payment status",
+			zap.String("payment_id", paymentID.String()),
+			zap.String("reported_status", string(status)),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if payment.SplitID != uuid.Nil {
+		if settled, err := o.settleSplitPart(ctx, orderID, payment); err != nil || !settled {
+			return err
+		}
+	} else if status == models.PAYMENT_STATUS_APPROVED {
+		if refunded, err := o.refundLatePayment(ctx, orderID, payment); err != nil || refunded {
+			return err
+		}
+	}
 
 	switch status {
 	case models.PAYMENT_STATUS_APPROVED:
-		orderID, err := uuid.Parse(in.OrderID)
-		if err != nil {
+		if out, err = o.UpdateOrderStatus(ctx, orderID, models.ORDER_STATUS_RECEIVED); err != nil {
+			return err
+		}
+		if err = o.inventory.CommitOrder(ctx, orderID); err != nil {
+			// the order is paid and goes to the kitchen anyway, stock is fixed with an adjustment
 			o.log.Log(
-				"error parsing order id",
-				err,
+				"failed committing order stock",
+				zap.String("order_id", orderID.String()),
+				zap.Error(err),
 			)
-			return
 		}
-		_, err = o.paymentsSvc.UpdatePayment(context.Background(), uuid.MustParse(in.ID), models.PAYMENT_STATUS_APPROVED)
-		if err != nil {
-			return
+		if err = o.publishMessage(ctx, productionMessage(out), productionmsgs.ProductionChannel); err != nil {
+			return err
 		}
+		_, err = o.UpdateOrderStatus(ctx, orderID, models.ORDER_STATUS_PREPARING)
 
-		if out, err = o.UpdateOrderStatus(context.Background(), orderID, models.ORDER_STATUS_RECEIVED); err != nil {
-			return
+	case models.PAYMENT_SATUS_REFUSED:
+		// production never saw this order, the customer may pay again with another method
+		if _, err = o.UpdateOrderStatus(ctx, orderID, models.ORDER_STATUS_PAYMENT_REFUSED); err == nil {
+			o.releaseStock(ctx, orderID)
 		}
-		err = o.publishMessage(context.Background(), productionmsgs.OrderSentMessage{
-			OrderID: out.ID.String(),
-			Status:  productionmsgs.OrderStatus(string(out.Status)),
-		}, productionmsgs.ProductionChannel)
 
-		if out, err = o.UpdateOrderStatus(context.Background(), uuid.MustParse(in.OrderID), models.ORDER_STATUS_PREPARING); err != nil {
-			return
+	case models.PAYMENT_STATUS_FAILED:
+		if _, err = o.UpdateOrderStatus(ctx, orderID, models.ORDER_STATUS_FAILED_PAYMENT); err == nil {
+			o.releaseStock(ctx, orderID)
 		}
 
-	case models.PAYMENT_SATUS_REFUSED:
-		_, err = o.paymentsSvc.UpdatePayment(context.Background(), uuid.MustParse(in.ID), models.PAYMENT_SATUS_REFUSED)
-		if err != nil {
-			return
+	case models.PAYMENT_STATUS_EXPIRED:
+		var order *models.Order
+		if order, err = o.GetOrder(ctx, orderID); err != nil {
+			return err
 		}
-		if out, err = o.UpdateOrderStatus(context.Background(), uuid.MustParse(in.OrderID), models.ORDER_STATUS_CANCELED); err != nil {
-			return
+		_, err = o.cancelOrder(ctx, order, models.CANCEL_REASON_PAYMENT_TIMEOUT, models.CANCEL_ACTOR_SYSTEM)
+	}
+
+	return err
+}
+
+// refundLatePayment gives back a charge paid after its order was canceled or moved on to another charge,
+// telling whether it did so the order is left as it is
+func (o *ordersSvc) refundLatePayment(ctx context.Context, orderID uuid.UUID, payment *models.Payment) (bool, error) {
+	order, err := o.GetOrder(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != models.ORDER_STATUS_CANCELED && order.PaymentID == payment.ID {
+		return false, nil
+	}
+
+	o.log.Log("Refunding late payment",
+		zap.String("order_id", order.ID.String()),
+		zap.String("payment_id", payment.ID.String()),
+		zap.String("order_status", string(order.Status)),
+	)
+	if _, err = o.paymentsSvc.RequestRefund(ctx, order, payment.ID, nil, models.REFUND_REASON_LATE_PAYMENT); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// settleSplitPart tells whether a status change of one part of a split bill moves the order. Approvals only do
+// once the approved parts cover the price, failures do right away after canceling or refunding the other parts.
+func (o *ordersSvc) settleSplitPart(ctx context.Context, orderID uuid.UUID, part *models.Payment) (bool, error) {
+	order, err := o.GetOrder(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	parts, err := o.paymentsSvc.ListSplitPayments(ctx, part.SplitID)
+	if err != nil {
+		return false, err
+	}
+
+	current := false
+	for _, p := range parts {
+		current = current || p.ID == order.PaymentID
+	}
+	if !current || order.Status != models.ORDER_STATUS_WAITING_PAYMENT {
+		// money arriving after the bill failed or the order was canceled goes back, replays of a settled bill change nothing
+		late := !current ||
+			order.Status == models.ORDER_STATUS_CANCELED ||
+			order.Status == models.ORDER_STATUS_PAYMENT_REFUSED ||
+			order.Status == models.ORDER_STATUS_FAILED_PAYMENT
+		if late && part.Status == models.PAYMENT_STATUS_APPROVED {
+			if _, err = o.paymentsSvc.RequestRefund(ctx, order, part.ID, nil, models.REFUND_REASON_LATE_PAYMENT); err != nil {
+				return false, err
+			}
 		}
-		err = o.publishMessage(context.Background(), productionmsgs.OrderSentMessage{
-			OrderID: out.ID.String(),
-			Status:  productionmsgs.OrderStatus(string(out.Status)),
-		}, productionmsgs.ProductionChannel)
+		return false, nil
+	}
+
+	switch part.Status {
+	case models.PAYMENT_STATUS_APPROVED:
+		paid := models.SplitPaidAmount(parts)
+		o.log.Log("Split payment part approved",
+			zap.String("order_id", order.ID.String()),
+			zap.String("payment_id", part.ID.String()),
+			zap.String("paid", paid.String()),
+			zap.String("price", order.Price.String()),
+		)
+		return paid.GreaterThanOrEqual(order.Price), nil
+	case models.PAYMENT_SATUS_REFUSED, models.PAYMENT_STATUS_FAILED, models.PAYMENT_STATUS_EXPIRED:
+		o.compensatePayments(ctx, order, models.REFUND_REASON_SPLIT_PAYMENT_FAILED)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func productionMessage(order *models.Order) ordermsgs.OrderSentMessage {
+	return ordermsgs.OrderSentMessage{
+		OrderSentMessage: productionmsgs.OrderSentMessage{
+			OrderID: order.ID.String(),
+			Status:  productionmsgs.OrderStatus(string(order.Status)),
+		},
+		PickupNumber: order.PickupNumber,
 	}
 }
 
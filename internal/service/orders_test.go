@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/mocks"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// TestProcessPaymentStatusChangeOutdated checks that copies of a notification that don't change the
+// payment leave the order alone: the mocks fail on any call not expected and the order repository is nil
+func TestProcessPaymentStatusChangeOutdated(t *testing.T) {
+	paymentID, orderID := uuid.New(), uuid.New()
+	msg := messages.PaymentStatusChangedMessage{ID: paymentID.String(), OrderID: orderID.String(), Status: "paid"}
+
+	tests := []struct {
+		name   string
+		expect func(payments *mocks.MockPaymentsService)
+	}{
+		{
+			name: "duplicate approval applied meanwhile by another copy",
+			expect: func(payments *mocks.MockPaymentsService) {
+				open := &models.Payment{ID: paymentID, OrderID: orderID, Status: models.PAYMENT_STATUS_OPEN}
+				payments.EXPECT().GetPayment(gomock.Any(), paymentID).Return(open, nil)
+				payments.EXPECT().ChangePaymentStatus(gomock.Any(), open, models.PaymentStatus(models.PAYMENT_STATUS_APPROVED)).
+					Return(nil, helpers.ErrPaymentStatusChanged)
+			},
+		},
+		{
+			name: "approval replayed after it was applied",
+			expect: func(payments *mocks.MockPaymentsService) {
+				approved := &models.Payment{ID: paymentID, OrderID: orderID, Status: models.PAYMENT_STATUS_APPROVED}
+				payments.EXPECT().GetPayment(gomock.Any(), paymentID).Return(approved, nil)
+			},
+		},
+		{
+			name: "approval arriving after a refund",
+			expect: func(payments *mocks.MockPaymentsService) {
+				refunded := &models.Payment{ID: paymentID, OrderID: orderID, Status: models.PAYMENT_STATUS_REFUNDED}
+				payments.EXPECT().GetPayment(gomock.Any(), paymentID).Return(refunded, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			payments := mocks.NewMockPaymentsService(ctrl)
+			tt.expect(payments)
+
+			svc := &ordersSvc{paymentsSvc: payments, log: kitlog.NewNopLogger()}
+			if err := svc.ProcessPaymentStatusChange(context.Background(), msg); err != nil {
+				t.Errorf("ProcessPaymentStatusChange() error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -6,18 +6,26 @@ import (
 	"fmt"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	ordermsgs "github.com/SOAT1StackGoLang/msvc-orders/pkg/messages"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/pix"
 	"github.com/SOAT1StackGoLang/msvc-payments/pkg/datastore"
 	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
 	logger "github.com/SOAT1StackGoLang/msvc-payments/pkg/middleware"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
 type paymentsSvc struct {
-	repo  persistence.PaymentRepository
-	log   kitlog.Logger
-	redis datastore.RedisStore
+	repo    persistence.PaymentRepository
+	refunds persistence.RefundRepository
+	log     kitlog.Logger
+	redis   datastore.RedisStore
+	pix     pix.Merchant
 }
 
 func (p *paymentsSvc) GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
@@ -26,15 +34,125 @@ func (p *paymentsSvc) GetPayment(ctx context.Context, paymentID uuid.UUID) (*mod
 }
 
 func (p *paymentsSvc) CreatePayment(ctx context.Context, order *models.Order) (*models.Payment, error) {
-	payment := &models.Payment{
+	return p.sendPayment(ctx, &models.Payment{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		Price:     order.Price,
 		OrderID:   order.ID,
 		Status:    models.PAYMENT_STATUS_OPEN,
+	})
+}
+
+// CreateSplitPayments charges each part of a split bill separately, the parts must add up to the order price.
+// When a part can't be sent the ones already sent are canceled, so the order is never partially charged.
+func (p *paymentsSvc) CreateSplitPayments(ctx context.Context, order *models.Order, parts []models.PaymentPart) ([]*models.Payment, error) {
+	amounts, err := priceSplitParts(order, parts)
+	if err != nil {
+		p.log.Log(
+			"refusing split payment",
+			zap.String("order_id", order.ID.String()),
+			zap.Int("parts", len(parts)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	splitID := uuid.New()
+	out := make([]*models.Payment, 0, len(parts))
+	for k, part := range parts {
+		payment, err := p.sendPayment(ctx, &models.Payment{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			Price:     amounts[k],
+			OrderID:   order.ID,
+			Status:    models.PAYMENT_STATUS_OPEN,
+			SplitID:   splitID,
+			Payer:     part.Payer,
+		})
+		if err != nil {
+			for _, sent := range out {
+				if _, cancelErr := p.CancelPayment(ctx, sent.ID, models.PAYMENT_CANCEL_REASON_CHECKOUT_FAILED); cancelErr != nil {
+					p.log.Log(
+						"failed canceling split payment part",
+						zap.String("order_id", order.ID.String()),
+						zap.String("payment_id", sent.ID.String()),
+						zap.Error(cancelErr),
+					)
+				}
+			}
+			return nil, err
+		}
+		out = append(out, payment)
+	}
+
+	return out, nil
+}
+
+// priceSplitParts returns the amount of each part. Parts by items pay the items plus their share of the
+// order adjustments, the last of them takes the cents left by rounding when the parts cover the whole order.
+func priceSplitParts(order *models.Order, parts []models.PaymentPart) ([]decimal.Decimal, error) {
+	if len(parts) < 2 {
+		return nil, helpers.ErrInvalidInput
+	}
+
+	sold := make(map[uuid.UUID]int)
+	prices := make(map[uuid.UUID]decimal.Decimal)
+	for _, prod := range order.Products {
+		sold[prod.ID]++
+		prices[prod.ID] = prod.Price
+	}
+
+	subtotal := order.Subtotal()
+	adjustments := order.Price.Sub(subtotal)
+
+	amounts := make([]decimal.Decimal, len(parts))
+	taken := make(map[uuid.UUID]int)
+	total := decimal.Zero
+	lastByItems := -1
+	for k, part := range parts {
+		if strings.TrimSpace(part.Payer) == "" || (len(part.Items) > 0) == part.Amount.IsPositive() {
+			return nil, helpers.ErrInvalidInput
+		}
+
+		amount := part.Amount
+		if len(part.Items) > 0 {
+			items := decimal.Zero
+			for _, i := range part.Items {
+				taken[i.ProductID] += i.Quantity
+				if i.Quantity <= 0 || taken[i.ProductID] > sold[i.ProductID] {
+					return nil, helpers.ErrInvalidInput
+				}
+				items = items.Add(prices[i.ProductID].Mul(decimal.NewFromInt(int64(i.Quantity))))
+			}
+			amount = items
+			if subtotal.IsPositive() {
+				amount = amount.Add(adjustments.Mul(items).Div(subtotal).Round(2))
+			}
+			lastByItems = k
+		}
+
+		amounts[k] = amount.Round(2)
+		total = total.Add(amounts[k])
+	}
+
+	diff := order.Price.Sub(total)
+	if !diff.IsZero() && lastByItems >= 0 && diff.Abs().LessThanOrEqual(decimal.New(int64(len(parts)), -2)) {
+		amounts[lastByItems] = amounts[lastByItems].Add(diff)
+		diff = decimal.Zero
+	}
+	if !diff.IsZero() {
+		return nil, helpers.ErrInvalidInput
 	}
 
+	return amounts, nil
+}
+
+// sendPayment stores the charge and asks msvc-payments to collect it
+func (p *paymentsSvc) sendPayment(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
 	receipt, err := p.repo.CreatePayment(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
 
 	outPayment := messages.PaymentCreationRequestMessage{
 		ID:        receipt.ID.String(),
@@ -54,10 +172,52 @@ func (p *paymentsSvc) CreatePayment(ctx context.Context, order *models.Order) (*
 	if err != nil {
 		return nil, err
 	}
+	// the timeline is informative, a missing entry must not fail a charge already sent
+	p.repo.RecordPaymentEvent(ctx, receipt, models.PAYMENT_EVENT_SENT)
 
 	return receipt, nil
 }
 
+// PixPayload renders the PIX "copia e cola" code of a charge still waiting to be paid
+func (p *paymentsSvc) PixPayload(payment *models.Payment) (string, error) {
+	if !p.pix.Enabled() || !payment.Status.IsPending() {
+		return "", helpers.ErrPixUnavailable
+	}
+
+	payload, err := p.pix.Payload(pix.Charge{
+		Amount: payment.Price,
+		TxID:   pix.TxIDFromUUID(payment.ID),
+	})
+	if err != nil {
+		p.log.Log(
+			"failed building pix payload",
+			zap.String("payment_id", payment.ID.String()),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return payload, nil
+}
+
+// ListStalePayments returns charges still pending whose last change is older than olderThan
+func (p *paymentsSvc) ListStalePayments(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Payment, error) {
+	return p.repo.ListStalePayments(
+		ctx,
+		[]models.PaymentStatus{models.PAYMENT_STATUS_OPEN, models.PAYMENT_STATUS_PROCESSING},
+		time.Now().Add(-olderThan),
+		limit,
+	)
+}
+
+func (p *paymentsSvc) ListPayments(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	return p.repo.ListPaymentsByOrder(ctx, orderID)
+}
+
+func (p *paymentsSvc) ListSplitPayments(ctx context.Context, splitID uuid.UUID) ([]*models.Payment, error) {
+	return p.repo.ListPaymentsBySplit(ctx, splitID)
+}
+
 func (p *paymentsSvc) UpdatePayment(ctx context.Context, paymentID uuid.UUID, status models.PaymentStatus) (*models.Payment, error) {
 	payment, err := p.GetPayment(ctx, paymentID)
 	if err != nil {
@@ -72,14 +232,276 @@ func (p *paymentsSvc) UpdatePayment(ctx context.Context, paymentID uuid.UUID, st
 	return updated, err
 }
 
+// ChangePaymentStatus moves a payment read by the caller to status, failing with helpers.ErrPaymentStatusChanged
+// when it no longer is in the status read
+func (p *paymentsSvc) ChangePaymentStatus(ctx context.Context, payment *models.Payment, status models.PaymentStatus) (*models.Payment, error) {
+	next := *payment
+	next.Status = status
+	next.UpdatedAt = time.Now()
+
+	return p.repo.ChangePaymentStatus(ctx, &next, payment.Status)
+}
+
+// CancelPayment asks msvc-payments to void a charge still pending so it can no longer be paid, then marks it Cancelado.
+// Money that still arrives is refunded when its approval comes in.
+func (p *paymentsSvc) CancelPayment(ctx context.Context, paymentID uuid.UUID, reason string) (*models.Payment, error) {
+	payment, err := p.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := json.Marshal(ordermsgs.PaymentCancelRequestMessage{
+		ID:        payment.ID.String(),
+		OrderID:   payment.OrderID.String(),
+		Reason:    reason,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("%s: %s", "failed marshalling payment cancel request", err.Error()))
+		return nil, err
+	}
+	if err = p.redis.Publish(ctx, ordermsgs.OrderPaymentCancelRequestChannel, bytes); err != nil {
+		return nil, err
+	}
+
+	return p.UpdatePayment(ctx, payment.ID, models.PAYMENT_STATUS_CANCELED)
+}
+
+// RequestRefund gives back the given order items, or everything not refunded yet when items is empty, from one
+// of the order payments and asks msvc-payments to return the money. The payment stays Estorno Solicitado until it confirms.
+func (p *paymentsSvc) RequestRefund(ctx context.Context, order *models.Order, paymentID uuid.UUID, items []models.RefundItem, reason string) (*models.Refund, error) {
+	payment, err := p.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.OrderID != order.ID || !payment.Status.IsRefundable() {
+		p.log.Log(
+			"refusing refund of payment",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("payment_status", string(payment.Status)),
+			zap.Error(helpers.ErrRefundNotAllowed),
+		)
+		return nil, helpers.ErrRefundNotAllowed
+	}
+
+	previous, err := p.refunds.ListRefundsByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := decimal.Zero
+	refundedQty := make(map[uuid.UUID]int)
+	for _, r := range previous {
+		if r.Status == models.REFUND_STATUS_FAILED {
+			continue
+		}
+		// items are counted across the order, amounts only against the payment they come from
+		if r.PaymentID == payment.ID {
+			refunded = refunded.Add(r.Amount)
+		}
+		for _, i := range r.Items {
+			refundedQty[i.ProductID] += i.Quantity
+		}
+	}
+
+	amount := payment.Price.Sub(refunded)
+	if len(items) > 0 {
+		if items, err = priceRefundItems(order, items, refundedQty); err != nil {
+			return nil, err
+		}
+		amount = decimal.Zero
+		for _, i := range items {
+			amount = amount.Add(i.Amount)
+		}
+	}
+	if !amount.IsPositive() || refunded.Add(amount).GreaterThan(payment.Price) {
+		p.log.Log(
+			"refusing refund above the paid amount",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("amount", amount.String()),
+			zap.String("refunded", refunded.String()),
+			zap.Error(helpers.ErrRefundNotAllowed),
+		)
+		return nil, helpers.ErrRefundNotAllowed
+	}
+
+	refund, err := p.refunds.CreateRefund(ctx, &models.Refund{
+		ID:        uuid.New(),
+		PaymentID: payment.ID,
+		OrderID:   order.ID,
+		CreatedAt: time.Now(),
+		Amount:    amount,
+		Items:     items,
+		Reason:    reason,
+		Status:    models.REFUND_STATUS_REQUESTED,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.publishRefundRequest(ctx, refund); err != nil {
+		refund.Status = models.REFUND_STATUS_FAILED
+		refund.UpdatedAt = time.Now()
+		p.refunds.UpdateRefund(ctx, refund)
+		return nil, err
+	}
+
+	if _, err = p.UpdatePayment(ctx, payment.ID, models.PAYMENT_STATUS_REFUND_REQUESTED); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// priceRefundItems checks the items against what was sold and not refunded yet, pricing them as they were sold
+func priceRefundItems(order *models.Order, items []models.RefundItem, refundedQty map[uuid.UUID]int) ([]models.RefundItem, error) {
+	sold := make(map[uuid.UUID]int)
+	prices := make(map[uuid.UUID]decimal.Decimal)
+	for _, prod := range order.Products {
+		sold[prod.ID]++
+		prices[prod.ID] = prod.Price
+	}
+
+	requested := make(map[uuid.UUID]int)
+	out := make([]models.RefundItem, 0, len(items))
+	for _, i := range items {
+		requested[i.ProductID] += i.Quantity
+		if i.Quantity <= 0 || requested[i.ProductID]+refundedQty[i.ProductID] > sold[i.ProductID] {
+			return nil, helpers.ErrInvalidInput
+		}
+		out = append(out, models.RefundItem{
+			ProductID: i.ProductID,
+			Quantity:  i.Quantity,
+			Amount:    prices[i.ProductID].Mul(decimal.NewFromInt(int64(i.Quantity))),
+		})
+	}
+
+	return out, nil
+}
+
+func (p *paymentsSvc) publishRefundRequest(ctx context.Context, refund *models.Refund) error {
+	msg := ordermsgs.RefundRequestMessage{
+		ID:        refund.ID.String(),
+		PaymentID: refund.PaymentID.String(),
+		OrderID:   refund.OrderID.String(),
+		Amount:    refund.Amount.InexactFloat64(),
+		Reason:    refund.Reason,
+		CreatedAt: refund.CreatedAt.Format(time.RFC3339),
+	}
+	for _, i := range refund.Items {
+		msg.Items = append(msg.Items, ordermsgs.RefundItemMessage{
+			ProductID: i.ProductID.String(),
+			Quantity:  i.Quantity,
+			Amount:    i.Amount.InexactFloat64(),
+		})
+	}
+
+	bytes, err := json.Marshal(msg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("%s: %s", "failed marshalling refund request", err.Error()))
+		return err
+	}
+
+	return p.redis.Publish(ctx, ordermsgs.OrderRefundRequestChannel, bytes)
+}
+
+func (p *paymentsSvc) ListRefunds(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error) {
+	return p.refunds.ListRefundsByOrder(ctx, orderID)
+}
+
+func (p *paymentsSvc) SubscribeToRefundUpdates() {
+	ctx := context.Background()
+	sub, err := p.redis.Subscribe(ctx, ordermsgs.RefundStatusResponseChannel)
+	if err != nil {
+		logger.Info("error subscribing to refund status updates")
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-sub:
+			p.handleRefundStatusChangedMessage(ctx, msg.Payload)
+		}
+	}
+}
+
+func (p *paymentsSvc) handleRefundStatusChangedMessage(ctx context.Context, msg string) {
+	var in ordermsgs.RefundStatusChangedMessage
+	if err := json.Unmarshal([]byte(msg), &in); err != nil {
+		p.log.Log(
+			"error unmarshalling refund status update",
+			zap.Error(err),
+		)
+		return
+	}
+
+	refundID, err := uuid.Parse(in.ID)
+	if err != nil {
+		p.log.Log(
+			"error parsing refund id",
+			zap.String("refund_id", in.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	refund, err := p.refunds.GetRefund(ctx, refundID)
+	if err != nil {
+		return
+	}
+
+	refund.Status = models.RefundStatusFromClearingService(in.Status)
+	refund.UpdatedAt = time.Now()
+	if _, err = p.refunds.UpdateRefund(ctx, refund); err != nil {
+		return
+	}
+
+	payment, err := p.GetPayment(ctx, refund.PaymentID)
+	if err != nil {
+		return
+	}
+	refunds, err := p.refunds.ListRefundsByOrder(ctx, refund.OrderID)
+	if err != nil {
+		return
+	}
+
+	var paymentRefunds []*models.Refund
+	for _, r := range refunds {
+		if r.PaymentID == payment.ID {
+			paymentRefunds = append(paymentRefunds, r)
+		}
+	}
+
+	status := models.RefundedPaymentStatus(payment.Price, paymentRefunds)
+	if _, err = p.UpdatePayment(ctx, payment.ID, status); err != nil {
+		return
+	}
+
+	p.log.Log("Refund status changed",
+		zap.String("refund_id", refund.ID.String()),
+		zap.String("order_id", refund.OrderID.String()),
+		zap.String("refund_status", string(refund.Status)),
+		zap.String("payment_status", string(status)),
+	)
+}
+
 func NewPaymentsService(
 	repo persistence.PaymentRepository,
+	refunds persistence.RefundRepository,
 	log kitlog.Logger,
 	cache datastore.RedisStore,
+	pixMerchant pix.Merchant,
 ) PaymentsService {
-	return &paymentsSvc{
-		repo:  repo,
-		log:   log,
-		redis: cache,
+	svc := &paymentsSvc{
+		repo:    repo,
+		refunds: refunds,
+		log:     log,
+		redis:   cache,
+		pix:     pixMerchant,
 	}
+
+	go svc.SubscribeToRefundUpdates()
+
+	return svc
 }
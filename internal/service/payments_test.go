@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestPriceSplitParts(t *testing.T) {
+	burger, fries, soda := uuid.New(), uuid.New(), uuid.New()
+	dec := decimal.RequireFromString
+
+	order := func(price string, products ...models.Product) *models.Order {
+		return &models.Order{ID: uuid.New(), Price: dec(price), Products: products}
+	}
+	product := func(id uuid.UUID, price string) models.Product {
+		return models.Product{ID: id, Price: dec(price)}
+	}
+	byAmount := func(payer, amount string) models.PaymentPart {
+		return models.PaymentPart{Payer: payer, Amount: dec(amount)}
+	}
+	byItems := func(payer string, items ...uuid.UUID) models.PaymentPart {
+		part := models.PaymentPart{Payer: payer}
+		for _, id := range items {
+			part.Items = append(part.Items, models.PaymentPartItem{ProductID: id, Quantity: 1})
+		}
+		return part
+	}
+
+	tests := []struct {
+		name    string
+		order   *models.Order
+		parts   []models.PaymentPart
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "fixed amounts covering the price",
+			order: order("25.50", product(burger, "20.00"), product(fries, "5.50")),
+			parts: []models.PaymentPart{byAmount("ana", "10.00"), byAmount("bia", "15.50")},
+			want:  []string{"10.00", "15.50"},
+		},
+		{
+			name:  "items share the adjustments by their price",
+			order: order("33.00", product(burger, "20.00"), product(fries, "10.00")),
+			parts: []models.PaymentPart{byItems("ana", burger), byItems("bia", fries)},
+			want:  []string{"22.00", "11.00"},
+		},
+		{
+			name:  "the last part by items takes the rounding cents",
+			order: order("31.00", product(burger, "10.00"), product(fries, "10.00"), product(soda, "10.00")),
+			parts: []models.PaymentPart{byItems("ana", burger), byItems("bia", fries), byItems("caio", soda)},
+			want:  []string{"10.33", "10.33", "10.34"},
+		},
+		{
+			name:  "amounts and items mixed",
+			order: order("30.00", product(burger, "20.00"), product(fries, "10.00")),
+			parts: []models.PaymentPart{byAmount("ana", "20.00"), byItems("bia", fries)},
+			want:  []string{"20.00", "10.00"},
+		},
+		{
+			name:    "a single part",
+			order:   order("30.00", product(burger, "30.00")),
+			parts:   []models.PaymentPart{byAmount("ana", "30.00")},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:    "amounts not covering the price",
+			order:   order("30.00", product(burger, "30.00")),
+			parts:   []models.PaymentPart{byAmount("ana", "10.00"), byAmount("bia", "10.00")},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:    "amounts above the price",
+			order:   order("30.00", product(burger, "30.00")),
+			parts:   []models.PaymentPart{byAmount("ana", "20.00"), byAmount("bia", "20.00")},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:    "part without payer",
+			order:   order("30.00", product(burger, "30.00")),
+			parts:   []models.PaymentPart{byAmount(" ", "10.00"), byAmount("bia", "20.00")},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:  "part with both amount and items",
+			order: order("30.00", product(burger, "20.00"), product(fries, "10.00")),
+			parts: []models.PaymentPart{
+				{Payer: "ana", Amount: dec("20.00"), Items: []models.PaymentPartItem{{ProductID: burger, Quantity: 1}}},
+				byItems("bia", fries),
+			},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:    "item taken twice",
+			order:   order("30.00", product(burger, "20.00"), product(fries, "10.00")),
+			parts:   []models.PaymentPart{byItems("ana", burger), byItems("bia", burger)},
+			wantErr: helpers.ErrInvalidInput,
+		},
+		{
+			name:    "item not in the order",
+			order:   order("30.00", product(burger, "30.00")),
+			parts:   []models.PaymentPart{byItems("ana", soda), byAmount("bia", "30.00")},
+			wantErr: helpers.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := priceSplitParts(tt.order, tt.parts)
+			if err != tt.wantErr {
+				t.Fatalf("priceSplitParts() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("priceSplitParts() = %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !got[i].Equal(dec(want)) {
+					t.Errorf("part %d = %s, want %s", i, got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -2,30 +2,45 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 const categoriesTable = "lanchonete_categories"
 
+// categorySubtree selects the active category given as argument and every active category below it
+const categorySubtree = `WITH RECURSIVE subtree AS (
+	SELECT id FROM ` + categoriesTable + ` WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM ` + categoriesTable + ` c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+// categoryAncestors counts how many times the second argument shows up from the first one up to the top level
+const categoryAncestors = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM ` + categoriesTable + ` WHERE id = ?
+	UNION
+	SELECT c.id, c.parent_id FROM ` + categoriesTable + ` c JOIN ancestors a ON c.id = a.parent_id
+) SELECT count(*) FROM ancestors WHERE id = ?`
+
+// categoryOrder sorts siblings the way the menu shows them
+const categoryOrder = "display_order ASC, name ASC"
+
 type catPersistence struct {
 	db  *gorm.DB
 	log kitlog.Logger
 }
 
 func (p *catPersistence) InsertCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
-	var out *models.Category
-	cat := Category{
-		ID:        in.ID,
-		CreatedAt: in.CreatedAt,
-		Name:      in.Name,
-	}
+	cat := categoryFromModels(in)
 
 	if err := p.db.WithContext(ctx).Table(categoriesTable).
-		Create(&cat).Error; err != nil {
+		Create(cat).Error; err != nil {
 		p.log.Log(
 			"db failed inserting category",
 			zap.Any("in_category", in),
@@ -34,24 +49,17 @@ func (p *catPersistence) InsertCategory(ctx context.Context, in *models.Category
 		return nil, err
 	}
 
-	out = &models.Category{
-		ID:        cat.ID,
-		CreatedAt: cat.CreatedAt,
-		Name:      cat.Name,
-	}
-
-	if cat.UpdatedAt.Valid {
-		out.CreatedAt = cat.UpdatedAt.Time
-	}
-
-	return out, nil
+	return cat.toModels(), nil
 }
 
 func (p *catPersistence) GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
 	cat := Category{}
 
 	if err := p.db.WithContext(ctx).Table(categoriesTable).
-		Select("*").Where("id = ?", id).First(&cat).Error; err != nil {
+		Select("*").Where("id = ?", id).Where(notArchived).First(&cat).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.ErrNotFound
+		}
 		_ = p.log.Log(
 			"db failed getting category",
 			zap.String("category_id", id.String()),
@@ -60,25 +68,115 @@ func (p *catPersistence) GetCategoryByID(ctx context.Context, id uuid.UUID) (*mo
 		return nil, err
 	}
 
-	out := &models.Category{
-		ID:        cat.ID,
-		CreatedAt: cat.CreatedAt,
-		Name:      cat.Name,
-	}
+	return cat.toModels(), nil
+}
+
+// UpdateCategory renames, reorders or moves the category, refusing with ErrCategoryCycle to nest it under
+// itself or one of its descendants
+func (p *catPersistence) UpdateCategory(ctx context.Context, in *models.Category) (*models.Category, error) {
+	cat := categoryFromModels(in)
+	cat.UpdatedAt.Time, cat.UpdatedAt.Valid = time.Now(), true
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// moves are serialized, two concurrent ones could otherwise close a cycle that neither sees
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", categoriesTable).Error; err != nil {
+			return err
+		}
+
+		if cat.ParentID.Valid {
+			var loops int64
+			if err := tx.Raw(categoryAncestors, cat.ParentID.UUID, cat.ID).Scan(&loops).Error; err != nil {
+				return err
+			}
+			if loops > 0 {
+				return helpers.ErrCategoryCycle
+			}
+		}
 
-	if cat.UpdatedAt.Valid {
-		out.CreatedAt = cat.UpdatedAt.Time
+		res := tx.Table(categoriesTable).
+			Where("id = ?", cat.ID).Where(notArchived).
+			Updates(map[string]any{
+				"name":          cat.Name,
+				"parent_id":     cat.ParentID,
+				"display_order": cat.DisplayOrder,
+				"updated_at":    cat.UpdatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return helpers.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed updating category",
+			zap.Any("in_category", in),
+			zap.Error(err),
+		)
+		return nil, err
 	}
 
-	return out, nil
+	return p.GetCategoryByID(ctx, in.ID)
 }
 
-func (p *catPersistence) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	cat := Category{ID: id}
-	if err := p.db.WithContext(ctx).Table(categoriesTable).Delete(&cat).Error; err != nil {
+// DeleteCategory archives the category. It is refused with ErrCategoryNotEmpty while the category has
+// active products or subcategories, unless cascade is set and they are all archived along with it.
+func (p *catPersistence) DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if cascade {
+			if err := tx.Table(productsTable).
+				Where("category_id IN ("+categorySubtree+")", id).Where(notArchived).
+				UpdateColumn("deleted_at", now).Error; err != nil {
+				return err
+			}
+			res := tx.Table(categoriesTable).
+				Where("id IN ("+categorySubtree+")", id).
+				UpdateColumn("deleted_at", now)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return helpers.ErrNotFound
+			}
+			return nil
+		}
+
+		var active int64
+		if err := tx.Table(productsTable).
+			Where("category_id = ?", id).Where(notArchived).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		var children int64
+		if err := tx.Table(categoriesTable).
+			Where("parent_id = ?", id).Where(notArchived).
+			Count(&children).Error; err != nil {
+			return err
+		}
+		if active > 0 || children > 0 {
+			return helpers.ErrCategoryNotEmpty
+		}
+
+		res := tx.Table(categoriesTable).
+			Where("id = ?", id).Where(notArchived).
+			UpdateColumn("deleted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return helpers.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
 		p.log.Log(
 			"db failed deleting category",
 			zap.Any("category_id", id.String()),
+			zap.Bool("cascade", cascade),
 			zap.Error(err),
 		)
 		return err
@@ -87,56 +185,70 @@ func (p *catPersistence) DeleteCategory(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
-func (p *catPersistence) ListCategories(ctx context.Context, limit int, offset int) (*models.CategoryList, error) {
+// ListCategories pages through the active categories in creation order, the tree keeps the display order
+func (p *catPersistence) ListCategories(ctx context.Context, page models.PageRequest) (*models.CategoryList, error) {
 	var total int64
 	var savedCats []Category
-	var err error
 
-	// First, perform the count operation
-	if err = p.db.WithContext(ctx).Table(categoriesTable).
-		Count(&total).
-		Error; err != nil {
+	filter := func(tx *gorm.DB) *gorm.DB {
+		return tx.Table(categoriesTable).Where(notArchived)
+	}
+
+	if err := keysetPage(filter(p.db.WithContext(ctx)), page).
+		Find(&savedCats).Error; err != nil {
 		p.log.Log(
-			"failed counting categories",
+			"failed listing categories",
 			zap.Error(err),
 		)
 		return nil, err
 	}
 
-	if err = p.db.WithContext(ctx).Table(categoriesTable).
-		Limit(limit).
-		Offset(offset).
-		Find(&savedCats).
-		Error; err != nil {
+	if err := filter(p.db.WithContext(ctx)).Count(&total).Error; err != nil {
 		p.log.Log(
-			"failed listing categories",
+			"failed counting categories",
 			zap.Error(err),
 		)
 		return nil, err
 	}
 
-	out := &models.CategoryList{}
-	outList := make([]*models.Category, 0)
+	savedCats, next, prev := keysetResult(savedCats, page, func(c Category) models.Cursor {
+		return models.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
 
+	outList := make([]*models.Category, 0, len(savedCats))
 	for _, c := range savedCats {
-		out := &models.Category{
-			ID:        c.ID,
-			CreatedAt: c.CreatedAt,
-			Name:      c.Name,
-		}
-
-		if c.UpdatedAt.Valid {
-			out.CreatedAt = c.UpdatedAt.Time
-		}
-		outList = append(outList, out)
+		outList = append(outList, c.toModels())
 	}
 
-	out.Categories = outList
-	out.Total = total
-	out.Limit = limit
-	out.Offset = offset
+	return &models.CategoryList{
+		Categories: outList,
+		Limit:      page.Limit,
+		Total:      total,
+		Next:       next,
+		Prev:       prev,
+	}, nil
+}
+
+// ListAllCategories returns every active category in menu order, for building the tree
+func (p *catPersistence) ListAllCategories(ctx context.Context) ([]*models.Category, error) {
+	var savedCats []Category
+
+	if err := p.db.WithContext(ctx).Table(categoriesTable).
+		Where(notArchived).
+		Order(categoryOrder).
+		Find(&savedCats).Error; err != nil {
+		p.log.Log(
+			"failed listing all categories",
+			zap.Error(err),
+		)
+		return nil, err
+	}
 
-	return out, err
+	out := make([]*models.Category, 0, len(savedCats))
+	for _, c := range savedCats {
+		out = append(out, c.toModels())
+	}
+	return out, nil
 }
 
 func NewCategoriesPersistence(db *gorm.DB, log kitlog.Logger) CategoriesRepository {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
 )
 
 type ProductsRepository interface {
@@ -11,21 +13,44 @@ type ProductsRepository interface {
 	InsertProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	DeleteProduct(ctx context.Context, uuid uuid.UUID) error
-	ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) (*models.ProductList, error)
+	RestoreProduct(ctx context.Context, id uuid.UUID) (*models.Product, error)
+	SetProductAvailability(ctx context.Context, id uuid.UUID, available bool, until time.Time) (*models.Product, error)
+	SetProductWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Product, error)
+	ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, withDescendants bool, page models.PageRequest) (*models.ProductList, error)
+	SearchProducts(ctx context.Context, in models.ProductSearch) (*models.ProductList, error)
 	GetProductsPriceSumByID(ctx context.Context, ids []uuid.UUID) (*models.ProductsSum, error)
+	SchedulePrice(ctx context.Context, productID uuid.UUID, price decimal.Decimal, effectiveAt time.Time) (*models.ProductPrice, error)
+	CancelScheduledPrice(ctx context.Context, productID, priceID uuid.UUID) (*models.ProductPrice, error)
+	ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductPrice, error)
+	ApplyDuePrices(ctx context.Context, now time.Time, limit int) (applied, fetched int, err error)
 }
 
 type CategoriesRepository interface {
 	InsertCategory(ctx context.Context, in *models.Category) (*models.Category, error)
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.Category, error)
-	DeleteCategory(ctx context.Context, id uuid.UUID) error
-	ListCategories(ctx context.Context, limit int, offset int) (*models.CategoryList, error)
+	UpdateCategory(ctx context.Context, in *models.Category) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error
+	ListCategories(ctx context.Context, page models.PageRequest) (*models.CategoryList, error)
+	ListAllCategories(ctx context.Context) ([]*models.Category, error)
+	SetCategoryWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Category, error)
 }
 
 type PaymentRepository interface {
 	CreatePayment(ctx context.Context, payment *models.Payment) (*models.Payment, error)
 	GetPayment(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
 	UpdatePayment(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+	ChangePaymentStatus(ctx context.Context, payment *models.Payment, from models.PaymentStatus) (*models.Payment, error)
+	RecordPaymentEvent(ctx context.Context, payment *models.Payment, kind models.PaymentEventKind) error
+	ListPaymentsByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error)
+	ListPaymentsBySplit(ctx context.Context, splitID uuid.UUID) ([]*models.Payment, error)
+	ListStalePayments(ctx context.Context, statuses []models.PaymentStatus, lastChangeBefore time.Time, limit int) ([]*models.Payment, error)
+}
+
+type RefundRepository interface {
+	CreateRefund(ctx context.Context, refund *models.Refund) (*models.Refund, error)
+	GetRefund(ctx context.Context, refundID uuid.UUID) (*models.Refund, error)
+	UpdateRefund(ctx context.Context, refund *models.Refund) (*models.Refund, error)
+	ListRefundsByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error)
 }
 
 type OrdersRepository interface {
@@ -35,5 +60,43 @@ type OrdersRepository interface {
 	UpdateOrder(ctx context.Context, order *models.Order) (*models.Order, error)
 	DeleteOrder(ctx context.Context, orderID uuid.UUID) error
 	ListOrdersByUser(ctx context.Context, limit, offset int, userID uuid.UUID) (*models.OrderList, error)
-	ListOrders(ctx context.Context, limit, offset int) (*models.OrderList, error)
+	ListOrders(ctx context.Context, page models.PageRequest) (*models.OrderList, error)
+	SearchOrders(ctx context.Context, in models.OrderSearch) (*models.OrderList, error)
+	NextPickupNumber(ctx context.Context, storeID string, day time.Time) (int, error)
+	GetOrderByPickupNumber(ctx context.Context, storeID string, day time.Time, number int) (*models.Order, error)
+	ListStaleOrders(ctx context.Context, status models.OrderStatus, lastChangeBefore time.Time, limit int) ([]*models.Order, error)
+	ListPickupPanelEntries(ctx context.Context, storeID string, day time.Time, statuses []models.OrderStatus) ([]models.PickupPanelEntry, error)
+}
+
+type WebhooksRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+}
+
+type StoreHoursRepository interface {
+	GetSchedule(ctx context.Context, storeID string, exceptionsSince time.Time) (*models.StoreSchedule, error)
+	SetBusinessHours(ctx context.Context, storeID string, hours []models.BusinessHours) error
+	SetHoursException(ctx context.Context, storeID string, exception models.HoursException) error
+	DeleteHoursException(ctx context.Context, storeID string, day time.Time) error
+}
+
+type MenuRepository interface {
+	LoadMenu(ctx context.Context) (*models.Menu, error)
+}
+
+type InventoryRepository interface {
+	CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (*models.Ingredient, error)
+	GetIngredient(ctx context.Context, id uuid.UUID) (*models.Ingredient, error)
+	ListIngredients(ctx context.Context) ([]*models.Ingredient, error)
+	AdjustStock(ctx context.Context, id uuid.UUID, delta decimal.Decimal, reason, actor string) (*models.Ingredient, error)
+	SetRecipe(ctx context.Context, productID uuid.UUID, items []models.RecipeItem) error
+	ListRecipes(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]models.RecipeItem, error)
+	ReserveStock(ctx context.Context, orderID uuid.UUID, needs map[uuid.UUID]decimal.Decimal) (bool, error)
+	CommitStock(ctx context.Context, orderID uuid.UUID) (bool, error)
+	ReleaseStock(ctx context.Context, orderID uuid.UUID) (bool, error)
+	ListStockMovements(ctx context.Context, ingredientID uuid.UUID, limit int) ([]*models.StockMovement, error)
 }
@@ -28,6 +28,14 @@ type Product struct {
 	Description string          `json:"description"`
 	CategoryID  uuid.UUID       `json:"category_id"`
 	Price       decimal.Decimal `json:"price"`
+
+	// availability only lives on the catalog, order snapshots don't carry it
+	Available        bool          `json:"-"`
+	UnavailableUntil sql.NullTime  `json:"-"`
+	OutOfStock       bool          `json:"-"`
+	DeletedAt        sql.NullTime  `json:"-"`
+	AvailableFrom    sql.NullInt16 `json:"-"`
+	AvailableTo      sql.NullInt16 `json:"-"`
 }
 
 func (p *Product) toModel() models.Product {
@@ -38,14 +46,39 @@ func (p *Product) toModel() models.Product {
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
+		Available:   p.Available,
+		OutOfStock:  p.OutOfStock,
 	}
 
 	if p.UpdatedAt.Valid {
 		out.UpdatedAt = p.UpdatedAt.Time
 	}
+	if p.UnavailableUntil.Valid {
+		out.UnavailableUntil = p.UnavailableUntil.Time
+	}
+	if p.DeletedAt.Valid {
+		out.DeletedAt = p.DeletedAt.Time
+	}
+	out.Window = windowFromColumns(p.AvailableFrom, p.AvailableTo)
 	return out
 }
 
+// windowFromColumns reads an optional time window kept as minutes after midnight
+func windowFromColumns(from, to sql.NullInt16) models.TimeWindow {
+	if !from.Valid || !to.Valid {
+		return models.TimeWindow{}
+	}
+	return models.TimeWindow{From: models.ClockTime(from.Int16), To: models.ClockTime(to.Int16)}
+}
+
+// windowColumns is the update of the available_from and available_to columns, the zero window clears them
+func windowColumns(w models.TimeWindow) map[string]any {
+	return map[string]any{
+		"available_from": sql.NullInt16{Int16: int16(w.From), Valid: !w.IsZero()},
+		"available_to":   sql.NullInt16{Int16: int16(w.To), Valid: !w.IsZero()},
+	}
+}
+
 func productsToModel(in json.RawMessage) []models.Product {
 	var products []Product
 	err := json.Unmarshal(in, &products)
@@ -84,6 +117,47 @@ func productFromModel(in []models.Product) json.RawMessage {
 	return productsJSON
 }
 
+type OrderAdjustment struct {
+	Kind       string          `json:"kind"`
+	Percentage decimal.Decimal `json:"percentage"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+func adjustmentsToModel(in json.RawMessage) []models.Adjustment {
+	if len(in) == 0 {
+		return nil
+	}
+
+	var adjustments []OrderAdjustment
+	if err := json.Unmarshal(in, &adjustments); err != nil {
+		// Price is stored on its own, losing the breakdown must not make the order unreadable
+		return nil
+	}
+
+	var out []models.Adjustment
+	for _, a := range adjustments {
+		out = append(out, models.Adjustment{
+			Kind:       models.AdjustmentKind(a.Kind),
+			Percentage: a.Percentage,
+			Amount:     a.Amount,
+		})
+	}
+	return out
+}
+
+func adjustmentsFromModel(in []models.Adjustment) (json.RawMessage, error) {
+	adjustments := make([]OrderAdjustment, 0, len(in))
+	for _, a := range in {
+		adjustments = append(adjustments, OrderAdjustment{
+			Kind:       string(a.Kind),
+			Percentage: a.Percentage,
+			Amount:     a.Amount,
+		})
+	}
+
+	return json.Marshal(adjustments)
+}
+
 type OrderProduct struct {
 	ID          uuid.UUID       `gorm:"id,primaryKey" json:"id"`
 	Name        string          `json:"name"`
@@ -99,10 +173,45 @@ type ProductList struct {
 }
 
 type Category struct {
-	ID        uuid.UUID `gorm:"id,primaryKey"`
-	CreatedAt time.Time
-	UpdatedAt sql.NullTime
-	Name      string
+	ID           uuid.UUID `gorm:"id,primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    sql.NullTime
+	DeletedAt    sql.NullTime
+	Name         string
+	ParentID     uuid.NullUUID
+	DisplayOrder int
+
+	// the window is only written by SetCategoryWindow
+	AvailableFrom sql.NullInt16 `gorm:"->"`
+	AvailableTo   sql.NullInt16 `gorm:"->"`
+}
+
+func categoryFromModels(in *models.Category) *Category {
+	return &Category{
+		ID:           in.ID,
+		CreatedAt:    in.CreatedAt,
+		UpdatedAt:    sql.NullTime{Time: in.UpdatedAt, Valid: !in.UpdatedAt.IsZero()},
+		Name:         in.Name,
+		ParentID:     uuid.NullUUID{UUID: in.ParentID, Valid: in.ParentID != uuid.Nil},
+		DisplayOrder: in.DisplayOrder,
+	}
+}
+
+func (c *Category) toModels() *models.Category {
+	out := &models.Category{
+		ID:           c.ID,
+		CreatedAt:    c.CreatedAt,
+		Name:         c.Name,
+		DisplayOrder: c.DisplayOrder,
+	}
+	if c.UpdatedAt.Valid {
+		out.UpdatedAt = c.UpdatedAt.Time
+	}
+	if c.ParentID.Valid {
+		out.ParentID = c.ParentID.UUID
+	}
+	out.Window = windowFromColumns(c.AvailableFrom, c.AvailableTo)
+	return out
 }
 
 type Order struct {
@@ -115,6 +224,16 @@ type Order struct {
 	Price     decimal.Decimal
 	Status    OrderStatus
 	Products  json.RawMessage `json:"products" gorm:"type:jsonb"`
+
+	Adjustments json.RawMessage `gorm:"type:jsonb"`
+
+	StoreID      string
+	PickupNumber sql.NullInt32
+	PickupDate   sql.NullTime
+	CustomerName string
+	CancelReason string
+	CanceledAt   sql.NullTime
+	CanceledBy   string
 }
 
 func (o *Order) toModels() *models.Order {
@@ -124,6 +243,11 @@ func (o *Order) toModels() *models.Order {
 		PaymentID: o.PaymentID,
 		CreatedAt: o.CreatedAt,
 		Price:     o.Price,
+		StoreID:   o.StoreID,
+
+		CustomerName: o.CustomerName,
+		CancelReason: models.CancelReason(o.CancelReason),
+		CanceledBy:   o.CanceledBy,
 	}
 	if o.UpdatedAt.Valid {
 		out.UpdatedAt = o.UpdatedAt.Time
@@ -131,15 +255,30 @@ func (o *Order) toModels() *models.Order {
 	if o.DeletedAt.Valid {
 		out.DeletedAt = o.DeletedAt.Time
 	}
+	if o.PickupNumber.Valid {
+		out.PickupNumber = int(o.PickupNumber.Int32)
+	}
+	if o.PickupDate.Valid {
+		out.PickupDate = o.PickupDate.Time
+	}
+	if o.CanceledAt.Valid {
+		out.CanceledAt = o.CanceledAt.Time
+	}
 
 	out.Status = orderStatusToModelStatus(o.Status)
 	out.Products = productsToModel(o.Products)
+	out.Adjustments = adjustmentsToModel(o.Adjustments)
 
 	return out
 }
 
-func orderFromModels(in *models.Order) *Order {
-	return &Order{
+func orderFromModels(in *models.Order) (*Order, error) {
+	adjustments, err := adjustmentsFromModel(in.Adjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Order{
 		ID:        in.ID,
 		UserID:    in.UserID,
 		PaymentID: in.PaymentID,
@@ -147,7 +286,27 @@ func orderFromModels(in *models.Order) *Order {
 		Price:     in.Price,
 		Status:    orderStatusFromModel(in.Status),
 		Products:  productFromModel(in.Products),
+		StoreID:   in.StoreID,
+
+		Adjustments: adjustments,
+
+		CustomerName: in.CustomerName,
+		CancelReason: string(in.CancelReason),
+		CanceledBy:   in.CanceledBy,
+	}
+	if in.PickupNumber > 0 {
+		out.PickupNumber = sql.NullInt32{Int32: int32(in.PickupNumber), Valid: true}
 	}
+	if !in.PickupDate.IsZero() {
+		// pickup_date is a date column, keep the store calendar day regardless of the session timezone
+		y, m, d := in.PickupDate.Date()
+		out.PickupDate = sql.NullTime{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	if !in.CanceledAt.IsZero() {
+		out.CanceledAt = sql.NullTime{Time: in.CanceledAt, Valid: true}
+	}
+
+	return out, nil
 }
 
 type OrderStatus int
@@ -162,6 +321,7 @@ const (
 	ORDER_STATUS_FINISHED
 	ORDER_STATUS_CANCELED
 	ORDER_STATUS_FAILED_PAYMENT
+	ORDER_STATUS_PAYMENT_REFUSED
 )
 
 func orderStatusToModelStatus(in OrderStatus) models.OrderStatus {
@@ -182,6 +342,8 @@ func orderStatusToModelStatus(in OrderStatus) models.OrderStatus {
 		return models.ORDER_STATUS_CANCELED
 	case ORDER_STATUS_FAILED_PAYMENT:
 		return models.ORDER_STATUS_FAILED_PAYMENT
+	case ORDER_STATUS_PAYMENT_REFUSED:
+		return models.ORDER_STATUS_PAYMENT_REFUSED
 	default:
 		return models.ORDER_STATUS_UNSET
 	}
@@ -205,6 +367,8 @@ func orderStatusFromModel(in models.OrderStatus) OrderStatus {
 		return ORDER_STATUS_CANCELED
 	case models.ORDER_STATUS_FAILED_PAYMENT:
 		return ORDER_STATUS_FAILED_PAYMENT
+	case models.ORDER_STATUS_PAYMENT_REFUSED:
+		return ORDER_STATUS_PAYMENT_REFUSED
 	default:
 		return ORDER_STATUS_UNSET
 	}
@@ -217,6 +381,27 @@ type Payment struct {
 	Value     decimal.Decimal `json:"value"`
 	OrderID   uuid.UUID
 	Status    PaymentStatus
+	Attempt   int
+	SplitID   uuid.NullUUID
+	Payer     string
+}
+
+type PaymentEvent struct {
+	ID         int64 `gorm:"primaryKey"`
+	PaymentID  uuid.UUID
+	OrderID    uuid.UUID
+	Kind       string
+	Status     PaymentStatus
+	OccurredAt time.Time
+}
+
+func (e *PaymentEvent) toModels() models.PaymentEvent {
+	return models.PaymentEvent{
+		PaymentID:  e.PaymentID,
+		Kind:       models.PaymentEventKind(e.Kind),
+		Status:     paymentStatusToModel(e.Status),
+		OccurredAt: e.OccurredAt,
+	}
 }
 
 func paymentFromModels(in *models.Payment) *Payment {
@@ -226,11 +411,16 @@ func paymentFromModels(in *models.Payment) *Payment {
 		Value:     in.Price,
 		OrderID:   in.OrderID,
 		Status:    paymentStatusFromModel(in.Status),
+		Attempt:   in.Attempt,
+		Payer:     in.Payer,
 	}
 	if !in.UpdatedAt.IsZero() {
 		out.UpdatedAt.Valid = true
 		out.UpdatedAt.Time = in.UpdatedAt
 	}
+	if in.SplitID != uuid.Nil {
+		out.SplitID = uuid.NullUUID{UUID: in.SplitID, Valid: true}
+	}
 
 	return out
 }
@@ -242,10 +432,15 @@ func (p *Payment) toModels() *models.Payment {
 		Price:     p.Value,
 		OrderID:   p.OrderID,
 		Status:    paymentStatusToModel(p.Status),
+		Attempt:   p.Attempt,
+		Payer:     p.Payer,
 	}
 	if p.UpdatedAt.Valid {
 		out.UpdatedAt = p.UpdatedAt.Time
 	}
+	if p.SplitID.Valid {
+		out.SplitID = p.SplitID.UUID
+	}
 
 	return out
 }
@@ -253,9 +448,16 @@ func (p *Payment) toModels() *models.Payment {
 type PaymentStatus string
 
 const (
-	PAYMENT_SATUS_OPEN      PaymentStatus = "Aberto"
-	PAYMENT_STATUS_APPROVED               = "Aprovado"
-	PAYMENT_STATUS_REFUSED                = "Recusado"
+	PAYMENT_SATUS_OPEN                PaymentStatus = "Aberto"
+	PAYMENT_STATUS_APPROVED                         = "Aprovado"
+	PAYMENT_STATUS_REFUSED                          = "Recusado"
+	PAYMENT_STATUS_CANCELED                         = "Cancelado"
+	PAYMENT_STATUS_REFUND_REQUESTED                 = "Estorno Solicitado"
+	PAYMENT_STATUS_REFUNDED                         = "Estornado"
+	PAYMENT_STATUS_PARTIALLY_REFUNDED               = "Parcialmente Estornado"
+	PAYMENT_STATUS_PROCESSING                       = "Processando"
+	PAYMENT_STATUS_EXPIRED                          = "Expirado"
+	PAYMENT_STATUS_FAILED                           = "Falha"
 )
 
 func paymentStatusFromModel(in models.PaymentStatus) PaymentStatus {
@@ -264,6 +466,20 @@ func paymentStatusFromModel(in models.PaymentStatus) PaymentStatus {
 		return PAYMENT_SATUS_OPEN
 	case models.PAYMENT_STATUS_APPROVED:
 		return PAYMENT_STATUS_APPROVED
+	case models.PAYMENT_STATUS_CANCELED:
+		return PAYMENT_STATUS_CANCELED
+	case models.PAYMENT_STATUS_REFUND_REQUESTED:
+		return PAYMENT_STATUS_REFUND_REQUESTED
+	case models.PAYMENT_STATUS_REFUNDED:
+		return PAYMENT_STATUS_REFUNDED
+	case models.PAYMENT_STATUS_PARTIALLY_REFUNDED:
+		return PAYMENT_STATUS_PARTIALLY_REFUNDED
+	case models.PAYMENT_STATUS_PROCESSING:
+		return PAYMENT_STATUS_PROCESSING
+	case models.PAYMENT_STATUS_EXPIRED:
+		return PAYMENT_STATUS_EXPIRED
+	case models.PAYMENT_STATUS_FAILED:
+		return PAYMENT_STATUS_FAILED
 	default:
 		return PAYMENT_STATUS_REFUSED
 	}
@@ -275,7 +491,335 @@ func paymentStatusToModel(in PaymentStatus) models.PaymentStatus {
 		return models.PAYMENT_STATUS_OPEN
 	case PAYMENT_STATUS_APPROVED:
 		return models.PAYMENT_STATUS_APPROVED
+	case PAYMENT_STATUS_CANCELED:
+		return models.PAYMENT_STATUS_CANCELED
+	case PAYMENT_STATUS_REFUND_REQUESTED:
+		return models.PAYMENT_STATUS_REFUND_REQUESTED
+	case PAYMENT_STATUS_REFUNDED:
+		return models.PAYMENT_STATUS_REFUNDED
+	case PAYMENT_STATUS_PARTIALLY_REFUNDED:
+		return models.PAYMENT_STATUS_PARTIALLY_REFUNDED
+	case PAYMENT_STATUS_PROCESSING:
+		return models.PAYMENT_STATUS_PROCESSING
+	case PAYMENT_STATUS_EXPIRED:
+		return models.PAYMENT_STATUS_EXPIRED
+	case PAYMENT_STATUS_FAILED:
+		return models.PAYMENT_STATUS_FAILED
 	default:
 		return models.PAYMENT_SATUS_REFUSED
 	}
 }
+
+type Refund struct {
+	ID        uuid.UUID `gorm:"id,primaryKey"`
+	PaymentID uuid.UUID
+	OrderID   uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Amount    decimal.Decimal
+	Items     json.RawMessage `gorm:"type:jsonb"`
+	Reason    string
+	Status    string
+}
+
+type RefundItem struct {
+	ProductID uuid.UUID       `json:"product_id"`
+	Quantity  int             `json:"quantity"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+func refundFromModels(in *models.Refund) (*Refund, error) {
+	items := make([]RefundItem, 0, len(in.Items))
+	for _, i := range in.Items {
+		items = append(items, RefundItem{ProductID: i.ProductID, Quantity: i.Quantity, Amount: i.Amount})
+	}
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Refund{
+		ID:        in.ID,
+		PaymentID: in.PaymentID,
+		OrderID:   in.OrderID,
+		CreatedAt: in.CreatedAt,
+		Amount:    in.Amount,
+		Items:     itemsJSON,
+		Reason:    in.Reason,
+		Status:    string(in.Status),
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt.Valid = true
+		out.UpdatedAt.Time = in.UpdatedAt
+	}
+
+	return out, nil
+}
+
+func (r *Refund) toModels() (*models.Refund, error) {
+	var items []RefundItem
+	if len(r.Items) > 0 {
+		if err := json.Unmarshal(r.Items, &items); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &models.Refund{
+		ID:        r.ID,
+		PaymentID: r.PaymentID,
+		OrderID:   r.OrderID,
+		CreatedAt: r.CreatedAt,
+		Amount:    r.Amount,
+		Reason:    r.Reason,
+		Status:    models.RefundStatus(r.Status),
+	}
+	for _, i := range items {
+		out.Items = append(out.Items, models.RefundItem{ProductID: i.ProductID, Quantity: i.Quantity, Amount: i.Amount})
+	}
+	if r.UpdatedAt.Valid {
+		out.UpdatedAt = r.UpdatedAt.Time
+	}
+
+	return out, nil
+}
+
+type WebhookSubscription struct {
+	ID        uuid.UUID `gorm:"id,primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	DeletedAt sql.NullTime
+	URL       string
+	Secret    string
+	Statuses  json.RawMessage `gorm:"type:jsonb"`
+}
+
+func webhookSubscriptionFromModels(in *models.WebhookSubscription) (*WebhookSubscription, error) {
+	statuses := in.Statuses
+	if statuses == nil {
+		statuses = []models.OrderStatus{}
+	}
+	statusesJSON, err := json.Marshal(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &WebhookSubscription{
+		ID:        in.ID,
+		CreatedAt: in.CreatedAt,
+		URL:       in.URL,
+		Secret:    in.Secret,
+		Statuses:  statusesJSON,
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt.Valid = true
+		out.UpdatedAt.Time = in.UpdatedAt
+	}
+
+	return out, nil
+}
+
+func (w *WebhookSubscription) toModels() (*models.WebhookSubscription, error) {
+	out := &models.WebhookSubscription{
+		ID:        w.ID,
+		CreatedAt: w.CreatedAt,
+		URL:       w.URL,
+		Secret:    w.Secret,
+	}
+	if len(w.Statuses) > 0 {
+		if err := json.Unmarshal(w.Statuses, &out.Statuses); err != nil {
+			return nil, err
+		}
+	}
+	if w.UpdatedAt.Valid {
+		out.UpdatedAt = w.UpdatedAt.Time
+	}
+
+	return out, nil
+}
+
+type WebhookDelivery struct {
+	ID             int64 `gorm:"primaryKey"`
+	SubscriptionID uuid.UUID
+	EventID        int64
+	OrderID        uuid.UUID
+	OrderStatus    string
+	Attempt        int
+	StatusCode     sql.NullInt32
+	Error          sql.NullString
+	Succeeded      bool
+	AttemptedAt    time.Time
+}
+
+func webhookDeliveryFromModels(in *models.WebhookDelivery) *WebhookDelivery {
+	out := &WebhookDelivery{
+		ID:             in.ID,
+		SubscriptionID: in.SubscriptionID,
+		EventID:        int64(in.EventID),
+		OrderID:        in.OrderID,
+		OrderStatus:    string(in.OrderStatus),
+		Attempt:        in.Attempt,
+		Succeeded:      in.Succeeded,
+		AttemptedAt:    in.AttemptedAt,
+	}
+	if in.StatusCode != 0 {
+		out.StatusCode = sql.NullInt32{Int32: int32(in.StatusCode), Valid: true}
+	}
+	if in.Error != "" {
+		out.Error = sql.NullString{String: in.Error, Valid: true}
+	}
+	return out
+}
+
+func (w *WebhookDelivery) toModels() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             w.ID,
+		SubscriptionID: w.SubscriptionID,
+		EventID:        uint64(w.EventID),
+		OrderID:        w.OrderID,
+		OrderStatus:    models.OrderStatus(w.OrderStatus),
+		Attempt:        w.Attempt,
+		StatusCode:     int(w.StatusCode.Int32),
+		Error:          w.Error.String,
+		Succeeded:      w.Succeeded,
+		AttemptedAt:    w.AttemptedAt,
+	}
+}
+
+type Ingredient struct {
+	ID        uuid.UUID `gorm:"id,primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Name      string
+	Unit      string
+	Stock     decimal.Decimal
+	Reserved  decimal.Decimal
+}
+
+func ingredientFromModels(in *models.Ingredient) *Ingredient {
+	out := &Ingredient{
+		ID:        in.ID,
+		CreatedAt: in.CreatedAt,
+		Name:      in.Name,
+		Unit:      in.Unit,
+		Stock:     in.Stock,
+		Reserved:  in.Reserved,
+	}
+	if !in.UpdatedAt.IsZero() {
+		out.UpdatedAt = sql.NullTime{Time: in.UpdatedAt, Valid: true}
+	}
+	return out
+}
+
+func (i *Ingredient) toModels() *models.Ingredient {
+	out := &models.Ingredient{
+		ID:        i.ID,
+		CreatedAt: i.CreatedAt,
+		Name:      i.Name,
+		Unit:      i.Unit,
+		Stock:     i.Stock,
+		Reserved:  i.Reserved,
+	}
+	if i.UpdatedAt.Valid {
+		out.UpdatedAt = i.UpdatedAt.Time
+	}
+	return out
+}
+
+type RecipeItem struct {
+	ProductID    uuid.UUID
+	IngredientID uuid.UUID
+	Quantity     decimal.Decimal
+}
+
+type StockReservation struct {
+	OrderID      uuid.UUID
+	IngredientID uuid.UUID
+	Quantity     decimal.Decimal
+	Status       string
+	CreatedAt    time.Time
+	UpdatedAt    sql.NullTime
+}
+
+type StockMovement struct {
+	ID            int64 `gorm:"primaryKey"`
+	IngredientID  uuid.UUID
+	OrderID       uuid.NullUUID
+	Kind          string
+	Quantity      decimal.Decimal
+	StockAfter    decimal.Decimal
+	ReservedAfter decimal.Decimal
+	Reason        sql.NullString
+	Actor         sql.NullString
+	OccurredAt    time.Time
+}
+
+func (m *StockMovement) toModels() *models.StockMovement {
+	out := &models.StockMovement{
+		ID:            m.ID,
+		IngredientID:  m.IngredientID,
+		Kind:          models.StockMovementKind(m.Kind),
+		Quantity:      m.Quantity,
+		StockAfter:    m.StockAfter,
+		ReservedAfter: m.ReservedAfter,
+		Reason:        m.Reason.String,
+		Actor:         m.Actor.String,
+		OccurredAt:    m.OccurredAt,
+	}
+	if m.OrderID.Valid {
+		out.OrderID = m.OrderID.UUID
+	}
+	return out
+}
+
+type ProductPrice struct {
+	ID          uuid.UUID `gorm:"id,primaryKey"`
+	CreatedAt   time.Time
+	ProductID   uuid.UUID
+	Price       decimal.Decimal
+	EffectiveAt time.Time
+	Status      string
+	AppliedAt   sql.NullTime
+}
+
+func (p *ProductPrice) toModels() *models.ProductPrice {
+	out := &models.ProductPrice{
+		ID:          p.ID,
+		CreatedAt:   p.CreatedAt,
+		ProductID:   p.ProductID,
+		Price:       p.Price,
+		EffectiveAt: p.EffectiveAt,
+		Status:      models.PriceStatus(p.Status),
+	}
+	if p.AppliedAt.Valid {
+		out.AppliedAt = p.AppliedAt.Time
+	}
+	return out
+}
+
+type BusinessHours struct {
+	StoreID  string
+	Weekday  int16
+	OpensAt  int16
+	ClosesAt int16
+}
+
+type HoursException struct {
+	StoreID  string
+	Day      time.Time
+	Closed   bool
+	OpensAt  sql.NullInt16
+	ClosesAt sql.NullInt16
+	Reason   string
+}
+
+func (e *HoursException) toModels() models.HoursException {
+	out := models.HoursException{
+		Day:    e.Day,
+		Closed: e.Closed,
+		Reason: e.Reason,
+	}
+	if !e.Closed {
+		out.Window = windowFromColumns(e.OpensAt, e.ClosesAt)
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	businessHoursTable   = "lanchonete_business_hours"
+	hoursExceptionsTable = "lanchonete_hours_exceptions"
+)
+
+type hoursPersistence struct {
+	db  *gorm.DB
+	log kitlog.Logger
+}
+
+// GetSchedule reads the weekly hours of the store and its exceptions from exceptionsSince on
+func (h *hoursPersistence) GetSchedule(ctx context.Context, storeID string, exceptionsSince time.Time) (*models.StoreSchedule, error) {
+	var (
+		hours      []BusinessHours
+		exceptions []HoursException
+	)
+
+	if err := h.db.WithContext(ctx).Table(businessHoursTable).
+		Where("store_id = ?", storeID).
+		Order("weekday ASC, opens_at ASC").
+		Find(&hours).Error; err != nil {
+		h.log.Log(
+			"db failed getting business hours",
+			zap.String("store_id", storeID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err := h.db.WithContext(ctx).Table(hoursExceptionsTable).
+		Where("store_id = ? AND day >= ?", storeID, exceptionsSince.Format(time.DateOnly)).
+		Order("day ASC").
+		Find(&exceptions).Error; err != nil {
+		h.log.Log(
+			"db failed getting hours exceptions",
+			zap.String("store_id", storeID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := &models.StoreSchedule{
+		Hours:      make([]models.BusinessHours, 0, len(hours)),
+		Exceptions: make([]models.HoursException, 0, len(exceptions)),
+	}
+	for _, v := range hours {
+		out.Hours = append(out.Hours, models.BusinessHours{
+			Weekday:    time.Weekday(v.Weekday),
+			TimeWindow: models.TimeWindow{From: models.ClockTime(v.OpensAt), To: models.ClockTime(v.ClosesAt)},
+		})
+	}
+	for _, v := range exceptions {
+		out.Exceptions = append(out.Exceptions, v.toModels())
+	}
+	return out, nil
+}
+
+// SetBusinessHours replaces the whole weekly hours of the store, no hours keeps it always open
+func (h *hoursPersistence) SetBusinessHours(ctx context.Context, storeID string, hours []models.BusinessHours) error {
+	rows := make([]BusinessHours, 0, len(hours))
+	for _, v := range hours {
+		rows = append(rows, BusinessHours{
+			StoreID:  storeID,
+			Weekday:  int16(v.Weekday),
+			OpensAt:  int16(v.From),
+			ClosesAt: int16(v.To),
+		})
+	}
+
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(businessHoursTable).Where("store_id = ?", storeID).Delete(&BusinessHours{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Table(businessHoursTable).Create(&rows).Error
+	})
+	if err != nil {
+		h.log.Log(
+			"db failed setting business hours",
+			zap.String("store_id", storeID),
+			zap.Error(err),
+		)
+	}
+	return err
+}
+
+// SetHoursException creates or replaces the exception of a day
+func (h *hoursPersistence) SetHoursException(ctx context.Context, storeID string, exception models.HoursException) error {
+	row := map[string]any{
+		"store_id":  storeID,
+		"day":       exception.Day.Format(time.DateOnly),
+		"closed":    exception.Closed,
+		"opens_at":  sql.NullInt16{Int16: int16(exception.Window.From), Valid: !exception.Closed},
+		"closes_at": sql.NullInt16{Int16: int16(exception.Window.To), Valid: !exception.Closed},
+		"reason":    exception.Reason,
+	}
+
+	if err := h.db.WithContext(ctx).Table(hoursExceptionsTable).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "store_id"}, {Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"closed", "opens_at", "closes_at", "reason"}),
+		}).
+		Create(row).Error; err != nil {
+		h.log.Log(
+			"db failed setting hours exception",
+			zap.String("store_id", storeID),
+			zap.Time("day", exception.Day),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (h *hoursPersistence) DeleteHoursException(ctx context.Context, storeID string, day time.Time) error {
+	res := h.db.WithContext(ctx).Table(hoursExceptionsTable).
+		Where("store_id = ? AND day = ?", storeID, day.Format(time.DateOnly)).
+		Delete(&HoursException{})
+	if res.Error != nil {
+		h.log.Log(
+			"db failed deleting hours exception",
+			zap.String("store_id", storeID),
+			zap.Time("day", day),
+			zap.Error(res.Error),
+		)
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return helpers.ErrNotFound
+	}
+	return nil
+}
+
+func NewHoursPersistence(db *gorm.DB, log kitlog.Logger) StoreHoursRepository {
+	return &hoursPersistence{
+		db:  db,
+		log: log,
+	}
+}
@@ -0,0 +1,435 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ingredientsTable       = "lanchonete_ingredients"
+	recipesTable           = "lanchonete_recipes"
+	stockReservationsTable = "lanchonete_stock_reservations"
+	stockMovementsTable    = "lanchonete_stock_movements"
+)
+
+const (
+	reservationReserved  = "reserved"
+	reservationCommitted = "committed"
+	reservationReleased  = "released"
+)
+
+type inventoryPersistence struct {
+	db  *gorm.DB
+	log kitlog.Logger
+}
+
+func (p *inventoryPersistence) CreateIngredient(ctx context.Context, in *models.Ingredient) (*models.Ingredient, error) {
+	ingredient := ingredientFromModels(in)
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(ingredientsTable).Create(ingredient).Error; err != nil {
+			return err
+		}
+		if ingredient.Stock.IsZero() {
+			return nil
+		}
+		return recordStockMovement(tx, ingredient, uuid.Nil, models.STOCK_MOVEMENT_ADJUST, ingredient.Stock, "initial stock", "", in.CreatedAt)
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed at CreateIngredient",
+			zap.String("name", in.Name),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return ingredient.toModels(), nil
+}
+
+func (p *inventoryPersistence) GetIngredient(ctx context.Context, id uuid.UUID) (*models.Ingredient, error) {
+	ingredient := new(Ingredient)
+
+	if err := p.db.WithContext(ctx).Table(ingredientsTable).
+		Select("*").
+		Where("id = ?", id).
+		First(ingredient).Error; err != nil {
+		p.log.Log(
+			"db failed getting ingredient",
+			zap.String("ingredient_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return ingredient.toModels(), nil
+}
+
+func (p *inventoryPersistence) ListIngredients(ctx context.Context) ([]*models.Ingredient, error) {
+	var ingredients []Ingredient
+
+	if err := p.db.WithContext(ctx).Table(ingredientsTable).
+		Select("*").
+		Order("name").
+		Find(&ingredients).Error; err != nil {
+		p.log.Log(
+			"db failed listing ingredients",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Ingredient, 0, len(ingredients))
+	for _, i := range ingredients {
+		out = append(out, i.toModels())
+	}
+	return out, nil
+}
+
+// AdjustStock adds delta, negative for losses, to the stock on hand. Stock never goes below zero.
+func (p *inventoryPersistence) AdjustStock(ctx context.Context, id uuid.UUID, delta decimal.Decimal, reason, actor string) (*models.Ingredient, error) {
+	var ingredient *Ingredient
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if ingredient, err = lockIngredient(tx, id); err != nil {
+			return err
+		}
+
+		ingredient.Stock = ingredient.Stock.Add(delta)
+		if ingredient.Stock.IsNegative() {
+			return helpers.ErrInvalidInput
+		}
+		if err = saveIngredientStock(tx, ingredient); err != nil {
+			return err
+		}
+		if err = recordStockMovement(tx, ingredient, uuid.Nil, models.STOCK_MOVEMENT_ADJUST, delta, reason, actor, time.Now()); err != nil {
+			return err
+		}
+		_, err = refreshOutOfStock(tx, []uuid.UUID{id})
+		return err
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed adjusting stock",
+			zap.String("ingredient_id", id.String()),
+			zap.String("delta", delta.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return ingredient.toModels(), nil
+}
+
+// SetRecipe replaces what one unit of the product takes, an empty recipe stops tracking its stock
+func (p *inventoryPersistence) SetRecipe(ctx context.Context, productID uuid.UUID, items []models.RecipeItem) error {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(recipesTable).Where("product_id = ?", productID).Delete(&RecipeItem{}).Error; err != nil {
+			return err
+		}
+
+		ingredientIDs := make([]uuid.UUID, 0, len(items))
+		for _, i := range items {
+			if err := tx.Table(recipesTable).Create(&RecipeItem{
+				ProductID:    productID,
+				IngredientID: i.IngredientID,
+				Quantity:     i.Quantity,
+			}).Error; err != nil {
+				return err
+			}
+			ingredientIDs = append(ingredientIDs, i.IngredientID)
+		}
+
+		// products without a recipe are never out of stock, the refresh below only sees products with one
+		if err := tx.Table(productsTable).Where("id = ?", productID).Update("out_of_stock", false).Error; err != nil {
+			return err
+		}
+		_, err := refreshOutOfStock(tx, ingredientIDs)
+		return err
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed setting recipe",
+			zap.String("product_id", productID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListRecipes returns the recipe of each given product that has one
+func (p *inventoryPersistence) ListRecipes(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]models.RecipeItem, error) {
+	var items []RecipeItem
+
+	if err := p.db.WithContext(ctx).Table(recipesTable).
+		Select("*").
+		Where("product_id IN ?", productIDs).
+		Find(&items).Error; err != nil {
+		p.log.Log(
+			"db failed listing recipes",
+			zap.Any("product_ids", productIDs),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make(map[uuid.UUID][]models.RecipeItem)
+	for _, i := range items {
+		out[i.ProductID] = append(out[i.ProductID], models.RecipeItem{
+			IngredientID: i.IngredientID,
+			Quantity:     i.Quantity,
+		})
+	}
+	return out, nil
+}
+
+// ReserveStock holds what the order needs, all or nothing, failing with ErrOutOfStock when an ingredient lacks it.
+// Orders already holding stock are left as they are. It reports whether any product ran out.
+func (p *inventoryPersistence) ReserveStock(ctx context.Context, orderID uuid.UUID, needs map[uuid.UUID]decimal.Decimal) (bool, error) {
+	var changed int64
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var held int64
+		if err := tx.Table(stockReservationsTable).
+			Where("order_id = ? AND status <> ?", orderID, reservationReleased).
+			Count(&held).Error; err != nil {
+			return err
+		}
+		if held > 0 {
+			return nil
+		}
+		// a new checkout after a refused payment holds the stock again
+		if err := tx.Table(stockReservationsTable).Where("order_id = ?", orderID).Delete(&StockReservation{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ids := sortedIngredientIDs(needs)
+		for _, id := range ids {
+			ingredient, err := lockIngredient(tx, id)
+			if err != nil {
+				return err
+			}
+			if ingredient.Stock.Sub(ingredient.Reserved).LessThan(needs[id]) {
+				return helpers.ErrOutOfStock
+			}
+
+			ingredient.Reserved = ingredient.Reserved.Add(needs[id])
+			if err = saveIngredientStock(tx, ingredient); err != nil {
+				return err
+			}
+			if err = tx.Table(stockReservationsTable).Create(&StockReservation{
+				OrderID:      orderID,
+				IngredientID: id,
+				Quantity:     needs[id],
+				Status:       reservationReserved,
+				CreatedAt:    now,
+			}).Error; err != nil {
+				return err
+			}
+			if err = recordStockMovement(tx, ingredient, orderID, models.STOCK_MOVEMENT_RESERVE, needs[id], "", "", now); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		changed, err = refreshOutOfStock(tx, ids)
+		return err
+	})
+	if err != nil {
+		if err != helpers.ErrOutOfStock {
+			p.log.Log(
+				"db failed reserving stock",
+				zap.String("order_id", orderID.String()),
+				zap.Error(err),
+			)
+		}
+		return false, err
+	}
+
+	return changed > 0, nil
+}
+
+// CommitStock takes the stock held by the order out for good, once its payment is approved
+func (p *inventoryPersistence) CommitStock(ctx context.Context, orderID uuid.UUID) (bool, error) {
+	return p.settleReservation(ctx, orderID, models.STOCK_MOVEMENT_COMMIT)
+}
+
+// ReleaseStock gives the stock held by the order back, stock already committed stays consumed
+func (p *inventoryPersistence) ReleaseStock(ctx context.Context, orderID uuid.UUID) (bool, error) {
+	return p.settleReservation(ctx, orderID, models.STOCK_MOVEMENT_RELEASE)
+}
+
+func (p *inventoryPersistence) settleReservation(ctx context.Context, orderID uuid.UUID, kind models.StockMovementKind) (bool, error) {
+	var changed int64
+
+	status := reservationCommitted
+	if kind == models.STOCK_MOVEMENT_RELEASE {
+		status = reservationReleased
+	}
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservations []StockReservation
+		if err := tx.Table(stockReservationsTable).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status = ?", orderID, reservationReserved).
+			Order("ingredient_id").
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		ids := make([]uuid.UUID, 0, len(reservations))
+		for _, r := range reservations {
+			ingredient, err := lockIngredient(tx, r.IngredientID)
+			if err != nil {
+				return err
+			}
+
+			ingredient.Reserved = ingredient.Reserved.Sub(r.Quantity)
+			if kind == models.STOCK_MOVEMENT_COMMIT {
+				ingredient.Stock = ingredient.Stock.Sub(r.Quantity)
+			}
+			if err = saveIngredientStock(tx, ingredient); err != nil {
+				return err
+			}
+			if err = tx.Table(stockReservationsTable).
+				Where("order_id = ? AND ingredient_id = ?", orderID, r.IngredientID).
+				Updates(map[string]any{"status": status, "updated_at": now}).Error; err != nil {
+				return err
+			}
+			if err = recordStockMovement(tx, ingredient, orderID, kind, r.Quantity, "", "", now); err != nil {
+				return err
+			}
+			ids = append(ids, r.IngredientID)
+		}
+
+		var err error
+		changed, err = refreshOutOfStock(tx, ids)
+		return err
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed settling stock reservation",
+			zap.String("order_id", orderID.String()),
+			zap.String("kind", string(kind)),
+			zap.Error(err),
+		)
+		return false, err
+	}
+
+	return changed > 0, nil
+}
+
+func (p *inventoryPersistence) ListStockMovements(ctx context.Context, ingredientID uuid.UUID, limit int) ([]*models.StockMovement, error) {
+	var movements []StockMovement
+
+	if err := p.db.WithContext(ctx).Table(stockMovementsTable).
+		Select("*").
+		Where("ingredient_id = ?", ingredientID).
+		Order("occurred_at DESC, id DESC").
+		Limit(limit).
+		Find(&movements).Error; err != nil {
+		p.log.Log(
+			"db failed listing stock movements",
+			zap.String("ingredient_id", ingredientID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.StockMovement, 0, len(movements))
+	for _, m := range movements {
+		out = append(out, m.toModels())
+	}
+	return out, nil
+}
+
+func lockIngredient(tx *gorm.DB, id uuid.UUID) (*Ingredient, error) {
+	ingredient := new(Ingredient)
+	if err := tx.Table(ingredientsTable).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(ingredient).Error; err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+func saveIngredientStock(tx *gorm.DB, ingredient *Ingredient) error {
+	ingredient.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return tx.Table(ingredientsTable).
+		Where("id = ?", ingredient.ID).
+		Updates(map[string]any{
+			"stock":      ingredient.Stock,
+			"reserved":   ingredient.Reserved,
+			"updated_at": ingredient.UpdatedAt,
+		}).Error
+}
+
+func recordStockMovement(tx *gorm.DB, ingredient *Ingredient, orderID uuid.UUID, kind models.StockMovementKind, quantity decimal.Decimal, reason, actor string, at time.Time) error {
+	return tx.Table(stockMovementsTable).Create(&StockMovement{
+		IngredientID:  ingredient.ID,
+		OrderID:       uuid.NullUUID{UUID: orderID, Valid: orderID != uuid.Nil},
+		Kind:          string(kind),
+		Quantity:      quantity,
+		StockAfter:    ingredient.Stock,
+		ReservedAfter: ingredient.Reserved,
+		Reason:        sql.NullString{String: reason, Valid: reason != ""},
+		Actor:         sql.NullString{String: actor, Valid: actor != ""},
+		OccurredAt:    at,
+	}).Error
+}
+
+// refreshOutOfStock flags the products using the ingredients that can't be made once more, and clears the
+// ones that can again. It returns how many products changed.
+func refreshOutOfStock(tx *gorm.DB, ingredientIDs []uuid.UUID) (int64, error) {
+	if len(ingredientIDs) == 0 {
+		return 0, nil
+	}
+
+	res := tx.Exec(`UPDATE `+productsTable+` p SET out_of_stock = s.short
+FROM (SELECT r.product_id, bool_or(i.stock - i.reserved < r.quantity) AS short
+      FROM `+recipesTable+` r JOIN `+ingredientsTable+` i ON i.id = r.ingredient_id
+      WHERE r.product_id IN (SELECT product_id FROM `+recipesTable+` WHERE ingredient_id IN ?)
+      GROUP BY r.product_id) s
+WHERE p.id = s.product_id AND p.out_of_stock <> s.short`, ingredientIDs)
+
+	return res.RowsAffected, res.Error
+}
+
+// sortedIngredientIDs locks ingredients always in the same order so concurrent checkouts can't deadlock
+func sortedIngredientIDs(needs map[uuid.UUID]decimal.Decimal) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(needs))
+	for id := range needs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+func NewInventoryPersistence(db *gorm.DB, log kitlog.Logger) InventoryRepository {
+	return &inventoryPersistence{
+		db:  db,
+		log: log,
+	}
+}
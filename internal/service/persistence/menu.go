@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+type menuPersistence struct {
+	db  *gorm.DB
+	log kitlog.Logger
+}
+
+// LoadMenu reads every category still in the catalog with its products sorted by name. Subcategories follow
+// their parent, siblings keep their display order. UpdatedAt is the latest change among them.
+func (m *menuPersistence) LoadMenu(ctx context.Context) (*models.Menu, error) {
+	var (
+		categories []Category
+		products   []Product
+	)
+
+	if err := m.db.WithContext(ctx).Table(categoriesTable).
+		Where(notArchived).
+		Order(categoryOrder).
+		Find(&categories).Error; err != nil {
+		m.log.Log(
+			"db failed loading menu categories",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err := m.db.WithContext(ctx).Table(productsTable).
+		Where(notArchived).
+		Order("name ASC").
+		Find(&products).Error; err != nil {
+		m.log.Log(
+			"db failed loading menu products",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := &models.Menu{}
+	byCategory := make(map[uuid.UUID][]models.Product, len(categories))
+	for _, p := range products {
+		product := p.toModel()
+		byCategory[product.CategoryID] = append(byCategory[product.CategoryID], product)
+		out.UpdatedAt = latest(out.UpdatedAt, product.CreatedAt, product.UpdatedAt)
+	}
+
+	flat := make([]*models.Category, 0, len(categories))
+	for _, c := range categories {
+		category := c.toModels()
+		out.UpdatedAt = latest(out.UpdatedAt, category.CreatedAt, category.UpdatedAt)
+		flat = append(flat, category)
+	}
+
+	// inherited is the window of the nearest category above with one
+	var walk func(nodes []*models.CategoryNode, inherited models.TimeWindow)
+	walk = func(nodes []*models.CategoryNode, inherited models.TimeWindow) {
+		for _, n := range nodes {
+			window := inherited
+			if !n.Window.IsZero() {
+				window = n.Window
+			}
+			if len(byCategory[n.ID]) > 0 {
+				products := byCategory[n.ID]
+				for i := range products {
+					products[i].CategoryWindow = window
+				}
+				out.Categories = append(out.Categories, models.MenuCategory{
+					Category: n.Category,
+					Products: products,
+				})
+			}
+			walk(n.Children, window)
+		}
+	}
+	walk(models.CategoryTree(flat), models.TimeWindow{})
+
+	return out, nil
+}
+
+func latest(current time.Time, candidates ...time.Time) time.Time {
+	for _, t := range candidates {
+		if t.After(current) {
+			current = t
+		}
+	}
+	return current
+}
+
+func NewMenuPersistence(db *gorm.DB, log kitlog.Logger) MenuRepository {
+	return &menuPersistence{
+		db:  db,
+		log: log,
+	}
+}
@@ -5,14 +5,19 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
-const ordersTable = "lanchonete_orders"
+const (
+	ordersTable         = "lanchonete_orders"
+	pickupCountersTable = "lanchonete_pickup_counters"
+)
 
 type ordersPersistence struct {
 	db  *gorm.DB
@@ -57,7 +62,9 @@ func (o *ordersPersistence) GetOrderByPaymentID(ctx context.Context, paymentID u
 	var err error
 	if err = o.db.WithContext(ctx).Table(ordersTable).
 		Select("*").
-		Where("payment_id = ?", paymentID).
+		// any part of a split bill finds its order, not only the one recorded on it
+		Where("payment_id = ? OR id = (?)", paymentID,
+			o.db.Table(paymentTable).Select("order_id").Where("id = ?", paymentID)).
 		First(order).Error; err != nil {
 		o.log.Log(
 			"db failed getting order",
@@ -71,7 +78,10 @@ func (o *ordersPersistence) GetOrderByPaymentID(ctx context.Context, paymentID u
 }
 
 func (o *ordersPersistence) CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
-	in := orderFromModels(order)
+	in, err := orderFromModels(order)
+	if err != nil {
+		return nil, err
+	}
 	in.Status = ORDER_STATUS_OPEN
 
 	columns := []string{"updated_at"}
@@ -79,7 +89,7 @@ func (o *ordersPersistence) CreateOrder(ctx context.Context, order *models.Order
 		columns = append(columns, "user_id")
 	}
 
-	if err := o.db.WithContext(ctx).Table(ordersTable).Omit(columns...).Create(&in).Error; err != nil {
+	if err = o.db.WithContext(ctx).Table(ordersTable).Omit(columns...).Create(&in).Error; err != nil {
 		o.log.Log(
 			"db failed at CreateOrder",
 			zap.Any("order_input", order),
@@ -92,7 +102,10 @@ func (o *ordersPersistence) CreateOrder(ctx context.Context, order *models.Order
 }
 
 func (o *ordersPersistence) UpdateOrder(ctx context.Context, in *models.Order) (*models.Order, error) {
-	order := orderFromModels(in)
+	order, err := orderFromModels(in)
+	if err != nil {
+		return nil, err
+	}
 
 	order.UpdatedAt = sql.NullTime{
 		Time:  in.UpdatedAt,
@@ -123,14 +136,15 @@ func (o *ordersPersistence) DeleteOrder(ctx context.Context, orderID uuid.UUID)
 		Valid: true,
 	}
 	if err := o.db.WithContext(ctx).Table(ordersTable).
+		Where("id = ?", orderID).
 		UpdateColumn("deleted_at", deletedAt).
-		Where("order_id", orderID).
 		Error; err != nil {
 		o.log.Log(
 			"db failed deleting order",
 			zap.String("order_id", orderID.String()),
 			zap.Error(err),
 		)
+		return err
 	}
 	return nil
 }
@@ -183,16 +197,16 @@ func (o *ordersPersistence) ListOrdersByUser(ctx context.Context, limit, offset
 	return oList, err
 }
 
-func (o *ordersPersistence) ListOrders(ctx context.Context, limit, offset int) (*models.OrderList, error) {
+// ListOrders pages through the orders not deleted, oldest first
+func (o *ordersPersistence) ListOrders(ctx context.Context, page models.PageRequest) (*models.OrderList, error) {
 	var total int64
-
 	var saveOrders []Order
 
-	var err error
-	if err = o.db.WithContext(ctx).Table(ordersTable).
-		Limit(limit).
-		Offset(offset).
-		Order("status DESC").
+	filter := func(tx *gorm.DB) *gorm.DB {
+		return tx.Table(ordersTable).Where("deleted_at IS NULL")
+	}
+
+	if err := keysetPage(filter(o.db.WithContext(ctx)), page).
 		Find(&saveOrders).Error; err != nil {
 		o.log.Log(
 			"failed listing orders",
@@ -201,28 +215,232 @@ func (o *ordersPersistence) ListOrders(ctx context.Context, limit, offset int) (
 		return nil, err
 	}
 
-	if err = o.db.WithContext(ctx).Table(ordersTable).
-		Where("status > ? AND status < ? ", ORDER_STATUS_WAITING_PAYMENT, ORDER_STATUS_FINISHED).
-		Count(&total).Error; err != nil {
+	if err := filter(o.db.WithContext(ctx)).Count(&total).Error; err != nil {
 		o.log.Log(
 			"failed counting orders",
 			zap.Error(err),
 		)
+		return nil, err
 	}
 
-	oList := &models.OrderList{}
+	saveOrders, next, prev := keysetResult(saveOrders, page, func(v Order) models.Cursor {
+		return models.Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
+	})
+
 	out := make([]*models.Order, 0, len(saveOrders))
+	for _, v := range saveOrders {
+		out = append(out, v.toModels())
+	}
 
+	return &models.OrderList{
+		Orders: out,
+		Limit:  page.Limit,
+		Total:  total,
+		Next:   next,
+		Prev:   prev,
+	}, nil
+}
+
+// likeEscaper keeps the wildcards typed by staff literal in LIKE patterns, backslash is the Postgres escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(in string) string {
+	return likeEscaper.Replace(in)
+}
+
+// SearchOrders finds the orders not deleted matching every filter given, paged like ListOrders
+func (o *ordersPersistence) SearchOrders(ctx context.Context, in models.OrderSearch) (*models.OrderList, error) {
+	var total int64
+	var saveOrders []Order
+
+	filter := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Table(ordersTable).Where("deleted_at IS NULL")
+		if len(in.Statuses) > 0 {
+			statuses := make([]OrderStatus, 0, len(in.Statuses))
+			for _, s := range in.Statuses {
+				statuses = append(statuses, orderStatusFromModel(s))
+			}
+			tx = tx.Where("status IN ?", statuses)
+		}
+		if !in.CreatedFrom.IsZero() {
+			tx = tx.Where("created_at >= ?", in.CreatedFrom)
+		}
+		if !in.CreatedTo.IsZero() {
+			tx = tx.Where("created_at < ?", in.CreatedTo)
+		}
+		if in.UserID != uuid.Nil {
+			tx = tx.Where("user_id = ?", in.UserID)
+		}
+		if in.CustomerName != "" {
+			tx = tx.Where("customer_name ILIKE ?", "%"+escapeLike(in.CustomerName)+"%")
+		}
+		if in.PaymentID != uuid.Nil {
+			tx = tx.Where("(payment_id = ? OR id IN (?))", in.PaymentID,
+				o.db.Table(paymentTable).Select("order_id").Where("id = ?", in.PaymentID))
+		}
+		if in.ProductID != uuid.Nil {
+			tx = tx.Where("products @> ?::jsonb", `[{"id":"`+in.ProductID.String()+`"}]`)
+		}
+		if in.MinPrice.Valid {
+			tx = tx.Where("price >= ?", in.MinPrice.Decimal)
+		}
+		if in.MaxPrice.Valid {
+			tx = tx.Where("price <= ?", in.MaxPrice.Decimal)
+		}
+		return tx
+	}
+
+	if err := keysetPage(filter(o.db.WithContext(ctx)), in.Page).
+		Find(&saveOrders).Error; err != nil {
+		o.log.Log(
+			"failed searching orders",
+			zap.Any("search", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err := filter(o.db.WithContext(ctx)).Count(&total).Error; err != nil {
+		o.log.Log(
+			"failed counting searched orders",
+			zap.Any("search", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	saveOrders, next, prev := keysetResult(saveOrders, in.Page, func(v Order) models.Cursor {
+		return models.Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
+	})
+
+	out := make([]*models.Order, 0, len(saveOrders))
 	for _, v := range saveOrders {
 		out = append(out, v.toModels())
 	}
 
-	oList.Orders = out
-	oList.Total = total
-	oList.Limit = limit
-	oList.Offset = offset
+	return &models.OrderList{
+		Orders: out,
+		Limit:  in.Page.Limit,
+		Total:  total,
+		Next:   next,
+		Prev:   prev,
+	}, nil
+}
 
-	return oList, err
+// NextPickupNumber atomically increments the store counter of the day, so concurrent checkouts never share a number
+func (o *ordersPersistence) NextPickupNumber(ctx context.Context, storeID string, day time.Time) (int, error) {
+	var number int
+
+	if err := o.db.WithContext(ctx).Raw(
+		"INSERT INTO "+pickupCountersTable+" (store_id, day, last_number) VALUES (?, ?, 1) "+
+			"ON CONFLICT (store_id, day) DO UPDATE SET last_number = "+pickupCountersTable+".last_number + 1 "+
+			"RETURNING last_number",
+		storeID, day.Format(time.DateOnly),
+	).Scan(&number).Error; err != nil {
+		o.log.Log(
+			"db failed generating pickup number",
+			zap.String("store_id", storeID),
+			zap.Time("day", day),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	return number, nil
+}
+
+func (o *ordersPersistence) GetOrderByPickupNumber(ctx context.Context, storeID string, day time.Time, number int) (*models.Order, error) {
+	order := &Order{}
+
+	if err := o.db.WithContext(ctx).Table(ordersTable).
+		Select("*").
+		Where("store_id = ? AND pickup_date = ? AND pickup_number = ?", storeID, day.Format(time.DateOnly), number).
+		First(order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.ErrNotFound
+		}
+		o.log.Log(
+			"db failed getting order by pickup number",
+			zap.String("store_id", storeID),
+			zap.Int("pickup_number", number),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return order.toModels(), nil
+}
+
+// ListStaleOrders lists orders sitting in status whose last change happened before lastChangeBefore
+func (o *ordersPersistence) ListStaleOrders(ctx context.Context, status models.OrderStatus, lastChangeBefore time.Time, limit int) ([]*models.Order, error) {
+	var saveOrders []Order
+
+	if err := o.db.WithContext(ctx).Table(ordersTable).
+		Where("status = ? AND deleted_at IS NULL AND COALESCE(updated_at, created_at) < ?", orderStatusFromModel(status), lastChangeBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&saveOrders).Error; err != nil {
+		o.log.Log(
+			"failed listing stale orders",
+			zap.String("status", string(status)),
+			zap.Time("last_change_before", lastChangeBefore),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Order, 0, len(saveOrders))
+	for _, v := range saveOrders {
+		out = append(out, v.toModels())
+	}
+
+	return out, nil
+}
+
+// ListPickupPanelEntries reads only the columns the public panel may show, never the order contents
+func (o *ordersPersistence) ListPickupPanelEntries(ctx context.Context, storeID string, day time.Time, statuses []models.OrderStatus) ([]models.PickupPanelEntry, error) {
+	type panelRow struct {
+		PickupNumber sql.NullInt32
+		CustomerName sql.NullString
+		Status       OrderStatus
+		CreatedAt    time.Time
+		UpdatedAt    sql.NullTime
+	}
+	var rows []panelRow
+
+	dbStatuses := make([]OrderStatus, 0, len(statuses))
+	for _, s := range statuses {
+		dbStatuses = append(dbStatuses, orderStatusFromModel(s))
+	}
+
+	if err := o.db.WithContext(ctx).Table(ordersTable).
+		Select("pickup_number, customer_name, status, created_at, updated_at").
+		Where("store_id = ? AND pickup_date = ? AND status IN ? AND pickup_number IS NOT NULL", storeID, day.Format(time.DateOnly), dbStatuses).
+		Order("pickup_number ASC").
+		Scan(&rows).Error; err != nil {
+		o.log.Log(
+			"db failed listing pickup panel entries",
+			zap.String("store_id", storeID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]models.PickupPanelEntry, 0, len(rows))
+	for _, r := range rows {
+		entry := models.PickupPanelEntry{
+			PickupNumber: int(r.PickupNumber.Int32),
+			CustomerName: r.CustomerName.String,
+			Status:       orderStatusToModelStatus(r.Status),
+			UpdatedAt:    r.CreatedAt,
+		}
+		if r.UpdatedAt.Valid {
+			entry.UpdatedAt = r.UpdatedAt.Time
+		}
+		out = append(out, entry)
+	}
+
+	return out, nil
 }
 
 func NewOrdersPersistence(db *gorm.DB, log kitlog.Logger) OrdersRepository {
@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"gorm.io/gorm"
+)
+
+// keysetPage sorts the query by (created_at, id) and seeks past the cursor of page. It fetches one row more
+// than asked so keysetResult can tell whether the list goes on.
+func keysetPage(tx *gorm.DB, page models.PageRequest) *gorm.DB {
+	c := page.Cursor
+	if c.Backward {
+		if !c.IsZero() {
+			tx = tx.Where("(created_at, id) < (?, ?)", c.CreatedAt, c.ID)
+		}
+		return tx.Order("created_at DESC, id DESC").Limit(page.Limit + 1)
+	}
+
+	if !c.IsZero() {
+		tx = tx.Where("(created_at, id) > (?, ?)", c.CreatedAt, c.ID)
+	}
+	return tx.Order("created_at ASC, id ASC").Limit(page.Limit + 1)
+}
+
+// keysetResult drops the extra row of keysetPage, puts backward pages back in ascending order and
+// returns the cursors of the pages before and after this one
+func keysetResult[T any](rows []T, page models.PageRequest, key func(T) models.Cursor) (out []T, next, prev models.Cursor) {
+	more := len(rows) > page.Limit
+	if more {
+		rows = rows[:page.Limit]
+	}
+	if page.Cursor.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, next, prev
+	}
+
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	first.Backward = true
+
+	if page.Cursor.Backward {
+		// coming back from a later page, there is always one after this
+		if !page.Cursor.IsZero() {
+			next = last
+		}
+		if more {
+			prev = first
+		}
+		return rows, next, prev
+	}
+
+	if more {
+		next = last
+	}
+	if !page.Cursor.IsZero() {
+		prev = first
+	}
+	return rows, next, prev
+}
@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/google/uuid"
+)
+
+type pageRow struct {
+	n         int
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func pageRowKey(r pageRow) models.Cursor {
+	return models.Cursor{CreatedAt: r.createdAt, ID: r.id}
+}
+
+func TestKeysetResult(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := make([]pageRow, 6)
+	for i := range rows {
+		rows[i] = pageRow{n: i, createdAt: base.Add(time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	forward := func(r pageRow) models.Cursor { return pageRowKey(r) }
+	backward := func(r pageRow) models.Cursor {
+		c := pageRowKey(r)
+		c.Backward = true
+		return c
+	}
+	// reversed returns rows as a backward query fetches them, newest first
+	reversed := func(in ...pageRow) []pageRow {
+		out := make([]pageRow, 0, len(in))
+		for i := len(in) - 1; i >= 0; i-- {
+			out = append(out, in[i])
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		fetched  []pageRow
+		page     models.PageRequest
+		want     []int
+		wantNext models.Cursor
+		wantPrev models.Cursor
+	}{
+		{
+			name:     "first page with more rows",
+			fetched:  rows[0:4],
+			page:     models.PageRequest{Limit: 3},
+			want:     []int{0, 1, 2},
+			wantNext: forward(rows[2]),
+		},
+		{
+			name:    "single page",
+			fetched: rows[0:3],
+			page:    models.PageRequest{Limit: 3},
+			want:    []int{0, 1, 2},
+		},
+		{
+			name:     "middle page going forward",
+			fetched:  rows[2:5],
+			page:     models.PageRequest{Limit: 2, Cursor: forward(rows[1])},
+			want:     []int{2, 3},
+			wantNext: forward(rows[3]),
+			wantPrev: backward(rows[2]),
+		},
+		{
+			name:     "last page going forward",
+			fetched:  rows[4:6],
+			page:     models.PageRequest{Limit: 2, Cursor: forward(rows[3])},
+			want:     []int{4, 5},
+			wantPrev: backward(rows[4]),
+		},
+		{
+			name:     "middle page going backward",
+			fetched:  reversed(rows[1:4]...),
+			page:     models.PageRequest{Limit: 2, Cursor: backward(rows[4])},
+			want:     []int{2, 3},
+			wantNext: forward(rows[3]),
+			wantPrev: backward(rows[2]),
+		},
+		{
+			name:     "first page going backward",
+			fetched:  reversed(rows[0:2]...),
+			page:     models.PageRequest{Limit: 2, Cursor: backward(rows[2])},
+			want:     []int{0, 1},
+			wantNext: forward(rows[1]),
+		},
+		{
+			name: "empty page",
+			page: models.PageRequest{Limit: 2, Cursor: forward(rows[5])},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, prev := keysetResult(append([]pageRow(nil), tt.fetched...), tt.page, pageRowKey)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("keysetResult() returned %d rows, want %d", len(got), len(tt.want))
+			}
+			for i, r := range got {
+				if r.n != tt.want[i] {
+					t.Errorf("row %d = %d, want %d", i, r.n, tt.want[i])
+				}
+			}
+			if next != tt.wantNext {
+				t.Errorf("next = %+v, want %+v", next, tt.wantNext)
+			}
+			if prev != tt.wantPrev {
+				t.Errorf("prev = %+v, want %+v", prev, tt.wantPrev)
+			}
+		})
+	}
+}
@@ -3,10 +3,12 @@ package persistence
 import (
 	"context"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 type paymentsPersistence struct {
@@ -14,12 +16,28 @@ type paymentsPersistence struct {
 	log kitlog.Logger
 }
 
-const paymentTable = "lanchonete_payments"
+const (
+	paymentTable       = "lanchonete_payments"
+	paymentEventsTable = "lanchonete_payment_events"
+)
 
+// CreatePayment stores a new attempt for the order, numbered after the previous ones, along with its first event
 func (p *paymentsPersistence) CreatePayment(ctx context.Context, in *models.Payment) (*models.Payment, error) {
 	payment := paymentFromModels(in)
 
-	if err := p.db.WithContext(ctx).Table(paymentTable).Create(&payment).Error; err != nil {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(paymentTable).
+			Select("coalesce(max(attempt), 0) + 1").
+			Where("order_id = ?", in.OrderID).
+			Scan(&payment.Attempt).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(paymentTable).Create(payment).Error; err != nil {
+			return err
+		}
+		return recordPaymentEvent(tx, payment, models.PAYMENT_EVENT_CREATED, payment.CreatedAt)
+	})
+	if err != nil {
 		p.log.Log(
 			"db failed at CreatePayment",
 			zap.Any("payment_input", in),
@@ -50,13 +68,20 @@ func (p *paymentsPersistence) GetPayment(ctx context.Context, id uuid.UUID) (*mo
 	return out, err
 }
 
+// UpdatePayment changes the current state of the payment and appends the change to its history
 func (p *paymentsPersistence) UpdatePayment(ctx context.Context, in *models.Payment) (*models.Payment, error) {
 	payment := paymentFromModels(in)
 
-	if err := p.db.WithContext(ctx).Table(paymentTable).
-		Updates(&payment).
-		Where("id = ?", in.ID).
-		Error; err != nil {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(paymentTable).
+			Where("id = ?", in.ID).
+			Updates(payment).
+			Error; err != nil {
+			return err
+		}
+		return recordPaymentEvent(tx, payment, models.PAYMENT_EVENT_STATUS_CHANGED, in.UpdatedAt)
+	})
+	if err != nil {
 		p.log.Log(
 			"db failed updating payment",
 			zap.Any("in_payment", in),
@@ -69,6 +94,169 @@ func (p *paymentsPersistence) UpdatePayment(ctx context.Context, in *models.Paym
 	return payment.toModels(), nil
 }
 
+// ChangePaymentStatus moves the payment to its new status only while it is still in from, so concurrent copies
+// of the same notification apply once. The others get helpers.ErrPaymentStatusChanged and record nothing.
+func (p *paymentsPersistence) ChangePaymentStatus(ctx context.Context, in *models.Payment, from models.PaymentStatus) (*models.Payment, error) {
+	payment := paymentFromModels(in)
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table(paymentTable).
+			Where("id = ? AND status = ?", in.ID, paymentStatusFromModel(from)).
+			Updates(payment)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return helpers.ErrPaymentStatusChanged
+		}
+		return recordPaymentEvent(tx, payment, models.PAYMENT_EVENT_STATUS_CHANGED, in.UpdatedAt)
+	})
+	if err == helpers.ErrPaymentStatusChanged {
+		return nil, err
+	}
+	if err != nil {
+		p.log.Log(
+			"db failed changing payment status",
+			zap.String("payment_id", in.ID.String()),
+			zap.String("from", string(from)),
+			zap.String("to", string(in.Status)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return payment.toModels(), nil
+}
+
+func (p *paymentsPersistence) RecordPaymentEvent(ctx context.Context, in *models.Payment, kind models.PaymentEventKind) error {
+	if err := recordPaymentEvent(p.db.WithContext(ctx), paymentFromModels(in), kind, time.Now()); err != nil {
+		p.log.Log(
+			"db failed recording payment event",
+			zap.String("payment_id", in.ID.String()),
+			zap.String("kind", string(kind)),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// ListPaymentsByOrder returns every attempt made to pay the order, oldest first, with their timelines
+func (p *paymentsPersistence) ListPaymentsByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	var (
+		payments []Payment
+		events   []PaymentEvent
+	)
+
+	if err := p.db.WithContext(ctx).Table(paymentTable).
+		Select("*").
+		Where("order_id = ?", orderID).
+		Order("attempt").
+		Find(&payments).Error; err != nil {
+		p.log.Log(
+			"db failed listing payments",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err := p.db.WithContext(ctx).Table(paymentEventsTable).
+		Select("*").
+		Where("order_id = ?", orderID).
+		Order("occurred_at, id").
+		Find(&events).Error; err != nil {
+		p.log.Log(
+			"db failed listing payment events",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	byPayment := make(map[uuid.UUID]*models.Payment, len(payments))
+	out := make([]*models.Payment, 0, len(payments))
+	for _, payment := range payments {
+		m := payment.toModels()
+		byPayment[m.ID] = m
+		out = append(out, m)
+	}
+	for _, e := range events {
+		if m, ok := byPayment[e.PaymentID]; ok {
+			m.Timeline = append(m.Timeline, e.toModels())
+		}
+	}
+
+	return out, nil
+}
+
+// ListPaymentsBySplit returns the parts of a split bill in the order they were created
+func (p *paymentsPersistence) ListPaymentsBySplit(ctx context.Context, splitID uuid.UUID) ([]*models.Payment, error) {
+	var payments []Payment
+
+	if err := p.db.WithContext(ctx).Table(paymentTable).
+		Select("*").
+		Where("split_id = ?", splitID).
+		Order("attempt").
+		Find(&payments).Error; err != nil {
+		p.log.Log(
+			"db failed listing split payments",
+			zap.String("split_id", splitID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Payment, 0, len(payments))
+	for _, v := range payments {
+		out = append(out, v.toModels())
+	}
+
+	return out, nil
+}
+
+func (p *paymentsPersistence) ListStalePayments(ctx context.Context, statuses []models.PaymentStatus, lastChangeBefore time.Time, limit int) ([]*models.Payment, error) {
+	var payments []Payment
+
+	dbStatuses := make([]PaymentStatus, 0, len(statuses))
+	for _, s := range statuses {
+		dbStatuses = append(dbStatuses, paymentStatusFromModel(s))
+	}
+
+	if err := p.db.WithContext(ctx).Table(paymentTable).
+		Where("status IN ? AND COALESCE(updated_at, created_at) < ?", dbStatuses, lastChangeBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&payments).Error; err != nil {
+		p.log.Log(
+			"failed listing stale payments",
+			zap.Time("last_change_before", lastChangeBefore),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Payment, 0, len(payments))
+	for _, v := range payments {
+		out = append(out, v.toModels())
+	}
+
+	return out, nil
+}
+
+func recordPaymentEvent(tx *gorm.DB, payment *Payment, kind models.PaymentEventKind, at time.Time) error {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return tx.Table(paymentEventsTable).Create(&PaymentEvent{
+		PaymentID:  payment.ID,
+		OrderID:    payment.OrderID,
+		Kind:       string(kind),
+		Status:     payment.Status,
+		OccurredAt: at,
+	}).Error
+}
+
 func NewPaymentsPersistence(db *gorm.DB, log kitlog.Logger) PaymentRepository {
 	return &paymentsPersistence{
 		db:  db,
@@ -0,0 +1,182 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const productPricesTable = "lanchonete_product_prices"
+
+// SchedulePrice records a price the product takes at effectiveAt, the product price is left alone until then
+func (p *productsPersistence) SchedulePrice(ctx context.Context, productID uuid.UUID, price decimal.Decimal, effectiveAt time.Time) (*models.ProductPrice, error) {
+	entry := &ProductPrice{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		ProductID:   productID,
+		Price:       price,
+		EffectiveAt: effectiveAt,
+		Status:      string(models.PRICE_STATUS_SCHEDULED),
+	}
+
+	var count int64
+	if err := p.db.WithContext(ctx).Table(productsTable).
+		Where("id = ?", productID).Where(notArchived).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, helpers.ErrNotFound
+	}
+
+	if err := p.db.WithContext(ctx).Table(productPricesTable).Create(entry).Error; err != nil {
+		p.log.Log(
+			"db failed scheduling price",
+			zap.String("product_id", productID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return entry.toModels(), nil
+}
+
+// CancelScheduledPrice drops a price that didn't come due yet
+func (p *productsPersistence) CancelScheduledPrice(ctx context.Context, productID, priceID uuid.UUID) (*models.ProductPrice, error) {
+	entry := new(ProductPrice)
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(productPricesTable).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND product_id = ?", priceID, productID).
+			First(entry).Error; err != nil {
+			return err
+		}
+		if entry.Status != string(models.PRICE_STATUS_SCHEDULED) {
+			return helpers.ErrPriceNotScheduled
+		}
+
+		entry.Status = string(models.PRICE_STATUS_CANCELED)
+		return tx.Table(productPricesTable).
+			Where("id = ?", priceID).
+			Update("status", entry.Status).Error
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed canceling scheduled price",
+			zap.String("product_id", productID.String()),
+			zap.String("price_id", priceID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return entry.toModels(), nil
+}
+
+// ListPriceHistory returns every price of the product, scheduled ones included, latest effective first
+func (p *productsPersistence) ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductPrice, error) {
+	var entries []ProductPrice
+
+	if err := p.db.WithContext(ctx).Table(productPricesTable).
+		Where("product_id = ?", productID).
+		Order("effective_at DESC, created_at DESC").
+		Find(&entries).Error; err != nil {
+		p.log.Log(
+			"db failed listing price history",
+			zap.String("product_id", productID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.ProductPrice, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.toModels())
+	}
+	return out, nil
+}
+
+// ApplyDuePrices moves the products to their scheduled prices due at now. When several came due for a product
+// only the latest is applied, the others are marked superseded. Returns the products changed and the
+// scheduled prices fetched, a batch full of superseded entries changes few products but still leaves more due.
+func (p *productsPersistence) ApplyDuePrices(ctx context.Context, now time.Time, limit int) (applied, fetched int, err error) {
+
+	err = p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var due []ProductPrice
+		// skip locked lets more than one instance run the job without waiting on each other
+		if err := tx.Table(productPricesTable).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND effective_at <= ?", models.PRICE_STATUS_SCHEDULED, now).
+			// archived products keep their schedule, it comes due once they are restored
+			Where("product_id IN (?)", tx.Table(productsTable).Select("id").Where(notArchived)).
+			Order("effective_at ASC, created_at ASC").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		fetched = len(due)
+
+		latest := make(map[uuid.UUID]ProductPrice)
+		for _, e := range due {
+			latest[e.ProductID] = e
+		}
+
+		for _, e := range due {
+			if latest[e.ProductID].ID != e.ID {
+				if err := tx.Table(productPricesTable).Where("id = ?", e.ID).
+					Update("status", models.PRICE_STATUS_SUPERSEDED).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			res := tx.Table(productsTable).
+				Where("id = ?", e.ProductID).Where(notArchived).
+				Updates(map[string]any{"price": e.Price, "updated_at": now})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				// archived since the select, try again after a restore
+				continue
+			}
+			if err := tx.Table(productPricesTable).Where("id = ?", e.ID).
+				Updates(map[string]any{
+					"status":     models.PRICE_STATUS_APPLIED,
+					"applied_at": now,
+				}).Error; err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	if err != nil {
+		p.log.Log(
+			"db failed applying scheduled prices",
+			zap.Error(err),
+		)
+		return 0, 0, err
+	}
+	return applied, fetched, nil
+}
+
+// recordPrice adds an applied entry to the history, for prices set right away by a product insert or update
+func recordPrice(tx *gorm.DB, productID uuid.UUID, price decimal.Decimal, at time.Time) error {
+	return tx.Table(productPricesTable).Create(&ProductPrice{
+		ID:          uuid.New(),
+		CreatedAt:   at,
+		ProductID:   productID,
+		Price:       price,
+		EffectiveAt: at,
+		Status:      string(models.PRICE_STATUS_APPLIED),
+		AppliedAt:   sql.NullTime{Time: at, Valid: true},
+	}).Error
+}
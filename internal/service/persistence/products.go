@@ -4,23 +4,31 @@ import (
 	"context"
 	"database/sql"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 const productsTable = "lanchonete_products"
 
+// notArchived keeps the products and categories still in the catalog
+const notArchived = "deleted_at IS NULL"
+
+// availableProducts keeps the products that can be ordered at the given time
+const availableProducts = "NOT out_of_stock AND (available OR unavailable_until <= ?)"
+
 type productsPersistence struct {
 	db  *gorm.DB
 	log kitlog.Logger
 }
 
+// GetProduct also resolves archived products, orders placed before the archival still point at them
 func (p *productsPersistence) GetProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
-	out := &models.Product{}
 	product := Product{}
 
 	if err := p.db.WithContext(ctx).Table(productsTable).
@@ -33,18 +41,18 @@ func (p *productsPersistence) GetProduct(ctx context.Context, id uuid.UUID) (*mo
 		return nil, err
 	}
 
-	out.ID = product.ID
-	out.Name = product.Name
-	out.Description = product.Description
-	out.CategoryID = product.CategoryID
-	out.Price = product.Price
-	out.CreatedAt = product.CreatedAt
-
-	if product.UpdatedAt.Valid {
-		out.UpdatedAt = product.UpdatedAt.Time
+	out := product.toModel()
+	window, err := categoryWindow(p.db.WithContext(ctx), product.CategoryID)
+	if err != nil {
+		p.log.Log(
+			"db failed getting category window of product",
+			zap.String("id", id.String()),
+			zap.Error(err),
+		)
+		return nil, err
 	}
-
-	return out, nil
+	out.CategoryWindow = window
+	return &out, nil
 }
 
 func (p *productsPersistence) InsertProduct(ctx context.Context, in *models.Product) (*models.Product, error) {
@@ -55,9 +63,18 @@ func (p *productsPersistence) InsertProduct(ctx context.Context, in *models.Prod
 		Description: in.Description,
 		CategoryID:  in.CategoryID,
 		Price:       in.Price,
+		Available:   in.Available,
 	}
 
-	if err := p.db.WithContext(ctx).Table(productsTable).Create(&product).Error; err != nil {
+	if err := p.activeCategory(ctx, in.CategoryID); err != nil {
+		return nil, err
+	}
+	if err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(productsTable).Create(&product).Error; err != nil {
+			return err
+		}
+		return recordPrice(tx, product.ID, product.Price, product.CreatedAt)
+	}); err != nil {
 		p.log.Log(
 			"db failed inserting product",
 			zap.Any("in_product", in),
@@ -73,6 +90,7 @@ func (p *productsPersistence) InsertProduct(ctx context.Context, in *models.Prod
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       product.Price,
+		Available:   product.Available,
 	}
 
 	return out, nil
@@ -96,7 +114,26 @@ func (p *productsPersistence) UpdateProduct(ctx context.Context, in *models.Prod
 		Valid: true,
 	}
 
-	if err := p.db.WithContext(ctx).Table(productsTable).Updates(&product).Where("id = ?", in.ID).Error; err != nil {
+	if err := p.activeCategory(ctx, in.CategoryID); err != nil {
+		return nil, err
+	}
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current := new(Product)
+		if err := tx.Table(productsTable).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", in.ID).Where(notArchived).
+			First(current).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(productsTable).Where("id = ?", in.ID).Updates(&product).Error; err != nil {
+			return err
+		}
+		if current.Price.Equal(product.Price) {
+			return nil
+		}
+		return recordPrice(tx, product.ID, product.Price, product.UpdatedAt.Time)
+	})
+	if err != nil {
 		p.log.Log(
 			"db failed updating product",
 			zap.Any("in_product", in),
@@ -105,40 +142,119 @@ func (p *productsPersistence) UpdateProduct(ctx context.Context, in *models.Prod
 		return nil, err
 	}
 
-	out := &models.Product{
-		ID:          product.ID,
-		CategoryID:  product.CategoryID,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt.Time,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
+	// read it back, availability is not part of the update
+	return p.GetProduct(ctx, in.ID)
+}
+
+// SetProductAvailability switches the product on or off, until is only kept while it is off
+func (p *productsPersistence) SetProductAvailability(ctx context.Context, id uuid.UUID, available bool, until time.Time) (*models.Product, error) {
+	changes := map[string]any{
+		"available":         available,
+		"unavailable_until": sql.NullTime{Time: until, Valid: !available && !until.IsZero()},
+		"updated_at":        time.Now(),
 	}
 
-	return out, nil
+	res := p.db.WithContext(ctx).Table(productsTable).Where("id = ?", id).Where(notArchived).Updates(changes)
+	if res.Error != nil {
+		p.log.Log(
+			"db failed setting product availability",
+			zap.String("product_id", id.String()),
+			zap.Bool("available", available),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, helpers.ErrNotFound
+	}
+
+	return p.GetProduct(ctx, id)
 }
 
+// DeleteProduct archives the product, the row stays so orders and reports still resolve it
 func (p *productsPersistence) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	product := Product{ID: id}
-	if err := p.db.WithContext(ctx).Table(productsTable).Delete(&product).Error; err != nil {
+	res := p.db.WithContext(ctx).Table(productsTable).
+		Where("id = ?", id).Where(notArchived).
+		UpdateColumn("deleted_at", time.Now())
+	if res.Error != nil {
 		p.log.Log(
 			"failed deleting product",
 			zap.String("product_id", id.String()),
+			zap.Error(res.Error),
+		)
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return helpers.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreProduct brings an archived product back to the catalog, as long as its category is still there
+func (p *productsPersistence) RestoreProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	product, err := p.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsArchived() {
+		return product, nil
+	}
+	if err = p.activeCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if err = p.db.WithContext(ctx).Table(productsTable).
+		Where("id = ?", id).
+		Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now()}).Error; err != nil {
+		p.log.Log(
+			"failed restoring product",
+			zap.String("product_id", id.String()),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 
+	return p.GetProduct(ctx, id)
+}
+
+// activeCategory refuses categories that don't exist or were archived, instead of failing on the foreign key
+func (p *productsPersistence) activeCategory(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := p.db.WithContext(ctx).Table(categoriesTable).
+		Where("id = ?", id).Where(notArchived).
+		Count(&count).Error; err != nil {
+		p.log.Log(
+			"db failed checking product category",
+			zap.String("category_id", id.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+	if count == 0 {
+		return helpers.ErrInvalidInput
+	}
 	return nil
 }
 
-func (p *productsPersistence) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) (*models.ProductList, error) {
+// ListProductsByCategory pages through the products that can be ordered now in creation order,
+// withDescendants includes the ones in subcategories
+func (p *productsPersistence) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, withDescendants bool, page models.PageRequest) (*models.ProductList, error) {
 	var products []Product
 	var total int64
 
-	err := p.db.WithContext(ctx).Table(productsTable).
-		Where("category_id = ?", categoryID).Limit(limit).Offset(offset).Order("name ASC").Find(&products).Error
-	if err != nil {
+	inCategory := "category_id = ?"
+	if withDescendants {
+		inCategory = "category_id IN (" + categorySubtree + ")"
+	}
+	now := time.Now()
+	filter := func(tx *gorm.DB) *gorm.DB {
+		return tx.Table(productsTable).
+			Where(inCategory, categoryID).Where(notArchived).Where(availableProducts, now)
+	}
+
+	if err := keysetPage(filter(p.db.WithContext(ctx)), page).
+		Find(&products).Error; err != nil {
 		p.log.Log(
 			"failed listing products",
 			zap.String("category", categoryID.String()),
@@ -147,39 +263,116 @@ func (p *productsPersistence) ListProductsByCategory(ctx context.Context, catego
 		return nil, err
 	}
 
-	if err = p.db.WithContext(ctx).Table(productsTable).
-		Where("category_id = ?", categoryID).
-		Count(&total).Error; err != nil {
+	if err := filter(p.db.WithContext(ctx)).Count(&total).Error; err != nil {
 		p.log.Log(
 			"failed counting products by category id",
 			zap.String("category", categoryID.String()),
 			zap.Error(err),
 		)
+		return nil, err
 	}
 
-	pList := &models.ProductList{}
+	products, next, prev := keysetResult(products, page, func(v Product) models.Cursor {
+		return models.Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
+	})
+
 	out := make([]*models.Product, 0, len(products))
 	for _, v := range products {
-		product := &models.Product{
-			ID:          v.ID,
-			CategoryID:  v.CategoryID,
-			CreatedAt:   v.CreatedAt,
-			Name:        v.Name,
-			Description: v.Description,
-			Price:       v.Price,
+		product := v.toModel()
+		out = append(out, &product)
+	}
+
+	return &models.ProductList{
+		Products: out,
+		Limit:    page.Limit,
+		Total:    total,
+		Next:     next,
+		Prev:     prev,
+	}, nil
+}
+
+// productSearchQuery turns the customer text into a tsquery, quotes and "or" are understood, accents are not needed
+const productSearchQuery = "websearch_to_tsquery('portuguese', lanchonete_unaccent(?))"
+
+// productSorts orders the search results, ties fall back to the name and id so pages are stable
+var productSorts = map[models.ProductSort]string{
+	models.PRODUCT_SORT_NAME:       "name ASC, id ASC",
+	models.PRODUCT_SORT_PRICE_ASC:  "price ASC, name ASC, id ASC",
+	models.PRODUCT_SORT_PRICE_DESC: "price DESC, name ASC, id ASC",
+	models.PRODUCT_SORT_NEWEST:     "created_at DESC, id ASC",
+}
+
+// SearchProducts finds active products by text over name and description, category with its subcategories,
+// price range and availability
+func (p *productsPersistence) SearchProducts(ctx context.Context, in models.ProductSearch) (*models.ProductList, error) {
+	var products []Product
+	var total int64
+
+	filter := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Table(productsTable).Where(notArchived)
+		if in.Query != "" {
+			tx = tx.Where("search @@ "+productSearchQuery, in.Query)
+		}
+		if in.CategoryID != uuid.Nil {
+			tx = tx.Where("category_id IN ("+categorySubtree+")", in.CategoryID)
+		}
+		if in.MinPrice.Valid {
+			tx = tx.Where("price >= ?", in.MinPrice.Decimal)
 		}
-		if v.UpdatedAt.Valid {
-			product.UpdatedAt = v.UpdatedAt.Time
+		if in.MaxPrice.Valid {
+			tx = tx.Where("price <= ?", in.MaxPrice.Decimal)
 		}
-		out = append(out, product)
+		switch in.Availability {
+		case models.PRODUCT_AVAILABILITY_AVAILABLE:
+			tx = tx.Where(availableProducts, time.Now())
+		case models.PRODUCT_AVAILABILITY_UNAVAILABLE:
+			tx = tx.Where("NOT ("+availableProducts+")", time.Now())
+		}
+		return tx
+	}
+
+	query := filter(p.db.WithContext(ctx))
+	if order, ok := productSorts[in.Sort]; ok {
+		query = query.Order(order)
+	} else if in.Query != "" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(search, " + productSearchQuery + ") DESC, name ASC, id ASC",
+			Vars: []any{in.Query},
+		}})
+	} else {
+		query = query.Order(productSorts[models.PRODUCT_SORT_NAME])
 	}
 
-	pList.Products = out
-	pList.Total = total
-	pList.Limit = limit
-	pList.Offset = offset
+	if err := query.Limit(in.Limit).Offset(in.Offset).Find(&products).Error; err != nil {
+		p.log.Log(
+			"failed searching products",
+			zap.Any("search", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err := filter(p.db.WithContext(ctx)).Count(&total).Error; err != nil {
+		p.log.Log(
+			"failed counting searched products",
+			zap.Any("search", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Product, 0, len(products))
+	for _, v := range products {
+		product := v.toModel()
+		out = append(out, &product)
+	}
 
-	return pList, err
+	return &models.ProductList{
+		Products: out,
+		Limit:    in.Limit,
+		Offset:   in.Offset,
+		Total:    total,
+	}, nil
 }
 
 func (p *productsPersistence) GetProductsPriceSumByID(ctx context.Context, ids []uuid.UUID) (*models.ProductsSum, error) {
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type refundsPersistence struct {
+	db  *gorm.DB
+	log kitlog.Logger
+}
+
+const refundTable = "lanchonete_refunds"
+
+func (r *refundsPersistence) CreateRefund(ctx context.Context, in *models.Refund) (*models.Refund, error) {
+	refund, err := refundFromModels(in)
+	if err != nil {
+		r.log.Log(
+			"failed marshalling refund items",
+			zap.Any("refund_input", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if err = r.db.WithContext(ctx).Table(refundTable).Create(refund).Error; err != nil {
+		r.log.Log(
+			"db failed at CreateRefund",
+			zap.Any("refund_input", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return refund.toModels()
+}
+
+func (r *refundsPersistence) GetRefund(ctx context.Context, id uuid.UUID) (*models.Refund, error) {
+	refund := new(Refund)
+
+	if err := r.db.WithContext(ctx).Table(refundTable).
+		Select("*").
+		Where("id = ?", id).
+		First(refund).Error; err != nil {
+		r.log.Log(
+			"db failed getting refund",
+			zap.String("refund_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return refund.toModels()
+}
+
+func (r *refundsPersistence) UpdateRefund(ctx context.Context, in *models.Refund) (*models.Refund, error) {
+	refund, err := refundFromModels(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.db.WithContext(ctx).Table(refundTable).
+		Where("id = ?", in.ID).
+		Updates(map[string]any{
+			"status":     refund.Status,
+			"updated_at": refund.UpdatedAt,
+		}).Error; err != nil {
+		r.log.Log(
+			"db failed updating refund",
+			zap.Any("in_refund", in),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return refund.toModels()
+}
+
+func (r *refundsPersistence) ListRefundsByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Refund, error) {
+	var refunds []Refund
+
+	if err := r.db.WithContext(ctx).Table(refundTable).
+		Select("*").
+		Where("order_id = ?", orderID).
+		Order("created_at").
+		Find(&refunds).Error; err != nil {
+		r.log.Log(
+			"db failed listing refunds",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.Refund, 0, len(refunds))
+	for _, refund := range refunds {
+		m, err := refund.toModels()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+
+	return out, nil
+}
+
+func NewRefundsPersistence(db *gorm.DB, log kitlog.Logger) RefundRepository {
+	return &refundsPersistence{
+		db:  db,
+		log: log,
+	}
+}
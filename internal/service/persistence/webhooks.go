@@ -0,0 +1,161 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	webhookSubscriptionsTable = "lanchonete_webhook_subscriptions"
+	webhookDeliveriesTable    = "lanchonete_webhook_deliveries"
+)
+
+type webhooksPersistence struct {
+	db  *gorm.DB
+	log kitlog.Logger
+}
+
+func (p *webhooksPersistence) CreateSubscription(ctx context.Context, in *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	sub, err := webhookSubscriptionFromModels(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.db.WithContext(ctx).Table(webhookSubscriptionsTable).Create(sub).Error; err != nil {
+		p.log.Log(
+			"db failed at CreateSubscription",
+			zap.String("url", in.URL),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return sub.toModels()
+}
+
+func (p *webhooksPersistence) GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	sub := new(WebhookSubscription)
+
+	if err := p.db.WithContext(ctx).Table(webhookSubscriptionsTable).
+		Select("*").
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.ErrNotFound
+		}
+		p.log.Log(
+			"db failed getting webhook subscription",
+			zap.String("subscription_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return sub.toModels()
+}
+
+func (p *webhooksPersistence) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	var subs []WebhookSubscription
+
+	if err := p.db.WithContext(ctx).Table(webhookSubscriptionsTable).
+		Select("*").
+		Where("deleted_at IS NULL").
+		Order("created_at").
+		Find(&subs).Error; err != nil {
+		p.log.Log(
+			"db failed listing webhook subscriptions",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.WebhookSubscription, 0, len(subs))
+	for _, s := range subs {
+		m, err := s.toModels()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+
+	return out, nil
+}
+
+func (p *webhooksPersistence) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	deletedAt := sql.NullTime{
+		Time:  time.Now(),
+		Valid: true,
+	}
+	res := p.db.WithContext(ctx).Table(webhookSubscriptionsTable).
+		Where("id = ? AND deleted_at IS NULL", id).
+		UpdateColumn("deleted_at", deletedAt)
+	if res.Error != nil {
+		p.log.Log(
+			"db failed deleting webhook subscription",
+			zap.String("subscription_id", id.String()),
+			zap.Error(res.Error),
+		)
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return helpers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *webhooksPersistence) RecordDelivery(ctx context.Context, in *models.WebhookDelivery) error {
+	if err := p.db.WithContext(ctx).Table(webhookDeliveriesTable).
+		Create(webhookDeliveryFromModels(in)).Error; err != nil {
+		p.log.Log(
+			"db failed recording webhook delivery",
+			zap.String("subscription_id", in.SubscriptionID.String()),
+			zap.Uint64("event_id", in.EventID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListDeliveries returns the latest delivery attempts of a subscription, newest first
+func (p *webhooksPersistence) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	if err := p.db.WithContext(ctx).Table(webhookDeliveriesTable).
+		Select("*").
+		Where("subscription_id = ?", subscriptionID).
+		Order("attempted_at DESC, id DESC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		p.log.Log(
+			"db failed listing webhook deliveries",
+			zap.String("subscription_id", subscriptionID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	out := make([]*models.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		out = append(out, d.toModels())
+	}
+
+	return out, nil
+}
+
+func NewWebhooksPersistence(db *gorm.DB, log kitlog.Logger) WebhooksRepository {
+	return &webhooksPersistence{
+		db:  db,
+		log: log,
+	}
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// categoryWindowQuery finds the window of the category given as argument or of its nearest ancestor with one
+const categoryWindowQuery = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id, available_from, available_to, 0 AS depth FROM ` + categoriesTable + ` WHERE id = ?
+	UNION ALL
+	SELECT c.id, c.parent_id, c.available_from, c.available_to, a.depth + 1 FROM ` + categoriesTable + ` c
+	JOIN ancestors a ON c.id = a.parent_id
+) SELECT available_from, available_to FROM ancestors WHERE available_from IS NOT NULL ORDER BY depth LIMIT 1`
+
+// categoryWindow returns the window products of the category inherit, zero when no category up the tree has one
+func categoryWindow(tx *gorm.DB, categoryID uuid.UUID) (models.TimeWindow, error) {
+	var row struct {
+		AvailableFrom sql.NullInt16
+		AvailableTo   sql.NullInt16
+	}
+	res := tx.Raw(categoryWindowQuery, categoryID).Scan(&row)
+	if res.Error != nil {
+		return models.TimeWindow{}, res.Error
+	}
+	return windowFromColumns(row.AvailableFrom, row.AvailableTo), nil
+}
+
+// SetProductWindow limits the hours of the day the product is sold, the zero window sells it all day
+func (p *productsPersistence) SetProductWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Product, error) {
+	changes := windowColumns(window)
+	changes["updated_at"] = time.Now()
+
+	res := p.db.WithContext(ctx).Table(productsTable).Where("id = ?", id).Where(notArchived).Updates(changes)
+	if res.Error != nil {
+		p.log.Log(
+			"db failed setting product window",
+			zap.String("product_id", id.String()),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, helpers.ErrNotFound
+	}
+
+	return p.GetProduct(ctx, id)
+}
+
+// SetCategoryWindow limits the hours of the day the products of the category and its subcategories are sold
+func (p *catPersistence) SetCategoryWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Category, error) {
+	changes := windowColumns(window)
+	changes["updated_at"] = time.Now()
+
+	res := p.db.WithContext(ctx).Table(categoriesTable).Where("id = ?", id).Where(notArchived).Updates(changes)
+	if res.Error != nil {
+		p.log.Log(
+			"db failed setting category window",
+			zap.String("category_id", id.String()),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, helpers.ErrNotFound
+	}
+
+	return p.GetCategoryByID(ctx, id)
+}
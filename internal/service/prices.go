@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const scheduledPricesBatchSize = 100
+
+// SchedulePrice sets the price a product takes at effectiveAt, prices taking effect now go through UpdateProduct
+func (p *productsSvc) SchedulePrice(ctx context.Context, productID uuid.UUID, price decimal.Decimal, effectiveAt time.Time) (*models.ProductPrice, error) {
+	if !price.IsPositive() || !effectiveAt.After(time.Now()) {
+		return nil, helpers.ErrInvalidInput
+	}
+	return p.productRepo.SchedulePrice(ctx, productID, price.Round(2), effectiveAt)
+}
+
+func (p *productsSvc) CancelScheduledPrice(ctx context.Context, productID, priceID uuid.UUID) (*models.ProductPrice, error) {
+	return p.productRepo.CancelScheduledPrice(ctx, productID, priceID)
+}
+
+func (p *productsSvc) ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductPrice, error) {
+	if _, err := p.productRepo.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return p.productRepo.ListPriceHistory(ctx, productID)
+}
+
+// ApplyScheduledPrices applies every price already due, batch by batch, and refreshes the menu when one changed
+func (p *productsSvc) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	total := 0
+	now := time.Now()
+	for {
+		applied, fetched, err := p.productRepo.ApplyDuePrices(ctx, now, scheduledPricesBatchSize)
+		total += applied
+		if err != nil || fetched < scheduledPricesBatchSize {
+			if total > 0 {
+				p.catalog.CatalogChanged()
+			}
+			return total, err
+		}
+	}
+}
+
+// ScheduledPricesConfig drives the job applying scheduled prices, a zero interval disables it
+type ScheduledPricesConfig struct {
+	Interval time.Duration
+}
+
+// RunScheduledPrices periodically applies the scheduled prices that came due until ctx is done
+func RunScheduledPrices(ctx context.Context, svc ProductsService, cfg ScheduledPricesConfig, log kitlog.Logger) {
+	if cfg.Interval <= 0 {
+		log.Log("message", "scheduled prices job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			applied, err := svc.ApplyScheduledPrices(ctx)
+			if err != nil {
+				log.Log(
+					"scheduled prices run failed",
+					zap.Error(err),
+				)
+				continue
+			}
+			if applied > 0 {
+				log.Log(
+					"applied scheduled prices",
+					zap.Int("count", applied),
+				)
+			}
+		}
+	}
+}
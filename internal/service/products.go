@@ -8,10 +8,13 @@ import (
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"strings"
+	"time"
 )
 
 type productsSvc struct {
 	productRepo persistence.ProductsRepository
+	catalog     CatalogObserver
 	log         kitlog.Logger
 }
 
@@ -24,7 +27,11 @@ func (p *productsSvc) InsertProduct(ctx context.Context, in *models.Product) (*m
 		return nil, helpers.ErrBadRequest
 	}
 	in.ID = uuid.New()
+	in.Available = true
 	out, err := p.productRepo.InsertProduct(ctx, in)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
 	return out, err
 }
 
@@ -32,24 +39,91 @@ func (p *productsSvc) UpdateProduct(ctx context.Context, in *models.Product) (*m
 	if in.Price == decimal.Zero {
 		return nil, helpers.ErrBadRequest
 	}
-	return p.productRepo.UpdateProduct(ctx, in)
+	out, err := p.productRepo.UpdateProduct(ctx, in)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
+	return out, err
 }
 
 func (p *productsSvc) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	return p.productRepo.DeleteProduct(ctx, id)
+	err := p.productRepo.DeleteProduct(ctx, id)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
+	return err
+}
+
+// RestoreProduct brings an archived product back to the catalog
+func (p *productsSvc) RestoreProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	out, err := p.productRepo.RestoreProduct(ctx, id)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
+	return out, err
 }
 
-func (p *productsSvc) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) (*models.ProductList, error) {
-	return p.productRepo.ListProductsByCategory(ctx, categoryID, limit, offset)
+// SetProductWindow limits the hours of the day the product is sold, the zero window follows its category again
+func (p *productsSvc) SetProductWindow(ctx context.Context, id uuid.UUID, window models.TimeWindow) (*models.Product, error) {
+	if !window.IsZero() && !window.IsValid() {
+		return nil, helpers.ErrInvalidInput
+	}
+	out, err := p.productRepo.SetProductWindow(ctx, id, window)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
+	return out, err
+}
+
+// SetProductAvailability takes a product off the menu, until a given time when until is set, or puts it back
+func (p *productsSvc) SetProductAvailability(ctx context.Context, id uuid.UUID, available bool, until time.Time) (*models.Product, error) {
+	if available {
+		until = time.Time{}
+	} else if !until.IsZero() && !until.After(time.Now()) {
+		return nil, helpers.ErrInvalidInput
+	}
+
+	out, err := p.productRepo.SetProductAvailability(ctx, id, available, until)
+	if err == nil {
+		p.catalog.CatalogChanged()
+	}
+	return out, err
+}
+
+func (p *productsSvc) ListProductsByCategory(ctx context.Context, categoryID uuid.UUID, withDescendants bool, page models.PageRequest) (*models.ProductList, error) {
+	return p.productRepo.ListProductsByCategory(ctx, categoryID, withDescendants, page.Normalized())
+}
+
+// productSearchMaxLimit keeps a single search page cheap
+const productSearchMaxLimit = 100
+
+// SearchProducts defaults to the products that can be ordered now, by relevance when there is a text
+func (p *productsSvc) SearchProducts(ctx context.Context, in models.ProductSearch) (*models.ProductList, error) {
+	in.Query = strings.TrimSpace(in.Query)
+	if in.Availability == "" {
+		in.Availability = models.PRODUCT_AVAILABILITY_AVAILABLE
+	}
+	if in.Sort == "" {
+		in.Sort = models.PRODUCT_SORT_RELEVANCE
+	}
+
+	if !in.Availability.IsValid() || !in.Sort.IsValid() ||
+		in.Limit <= 0 || in.Limit > productSearchMaxLimit || in.Offset < 0 ||
+		(in.MinPrice.Valid && in.MaxPrice.Valid && in.MinPrice.Decimal.GreaterThan(in.MaxPrice.Decimal)) {
+		return nil, helpers.ErrInvalidInput
+	}
+
+	return p.productRepo.SearchProducts(ctx, in)
 }
 
 func (p *productsSvc) GetProductsPriceSumByID(ctx context.Context, products []uuid.UUID) (*models.ProductsSum, error) {
 	return p.productRepo.GetProductsPriceSumByID(ctx, products)
 }
 
-func NewProductsService(repo persistence.ProductsRepository, log kitlog.Logger) ProductsService {
+func NewProductsService(repo persistence.ProductsRepository, catalog CatalogObserver, log kitlog.Logger) ProductsService {
 	return &productsSvc{
 		productRepo: repo,
+		catalog:     catalog,
 		log:         log,
 	}
 }
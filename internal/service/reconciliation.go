@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/api"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
+	kitlog "github.com/go-kit/log"
+	"go.uber.org/zap"
+)
+
+const reconciliationBatchSize = 100
+
+// PaymentReconciliationConfig drives the reconciliation job, a zero Interval disables it.
+// Timeout bounds each call to msvc-payments.
+type PaymentReconciliationConfig struct {
+	Interval  time.Duration
+	OlderThan time.Duration
+	Timeout   time.Duration
+}
+
+// PaymentDiscrepancy is a payment whose local status disagrees with msvc-payments
+type PaymentDiscrepancy struct {
+	Payment      *models.Payment
+	RemoteStatus models.PaymentStatus
+}
+
+type PaymentReconciliationReport struct {
+	Checked       int
+	Discrepancies []PaymentDiscrepancy
+	Applied       int
+	Failed        int
+}
+
+type PaymentReconciler struct {
+	payments PaymentsService
+	orders   OrdersService
+	client   api.PaymentAPI
+	log      kitlog.Logger
+}
+
+// NewPaymentReconciler creates the job that recovers payment notifications lost on the way,
+// asking msvc-payments directly about charges pending for too long
+func NewPaymentReconciler(payments PaymentsService, orders OrdersService, client api.PaymentAPI, log kitlog.Logger) *PaymentReconciler {
+	return &PaymentReconciler{
+		payments: payments,
+		orders:   orders,
+		client:   client,
+		log:      log,
+	}
+}
+
+// Reconcile compares the payments pending for longer than olderThan with msvc-payments and applies
+// the missed changes through the same path as the notifications
+func (r *PaymentReconciler) Reconcile(ctx context.Context, olderThan time.Duration) (*PaymentReconciliationReport, error) {
+	stale, err := r.payments.ListStalePayments(ctx, olderThan, reconciliationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PaymentReconciliationReport{}
+	for _, payment := range stale {
+		report.Checked++
+
+		remote, err := r.client.GetPayment(api.GetPaymentRequest{PaymentID: payment.ID})
+		if err != nil {
+			report.Failed++
+			r.log.Log(
+				"failed querying payment for reconciliation",
+				zap.String("payment_id", payment.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		remoteStatus := remote.Status
+		if remoteStatus == "" {
+			remoteStatus = remote.Payment.Status
+		}
+		if !models.IsClearingStatusKnown(string(remoteStatus)) {
+			// a notification may map unknown statuses to failures, a guess here could fail a paid order
+			r.log.Log(
+				"skipping payment with unknown remote status",
+				zap.String("payment_id", payment.ID.String()),
+				zap.String("remote_status", string(remoteStatus)),
+			)
+			continue
+		}
+		status := models.PaymentStatusFromClearingService(string(remoteStatus))
+		if status == payment.Status {
+			continue
+		}
+
+		report.Discrepancies = append(report.Discrepancies, PaymentDiscrepancy{Payment: payment, RemoteStatus: status})
+		r.log.Log(
+			"payment status discrepancy",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("order_id", payment.OrderID.String()),
+			zap.String("local_status", string(payment.Status)),
+			zap.String("remote_status", string(remoteStatus)),
+			zap.String("payment_error", remote.PaymentError),
+		)
+
+		if err = r.orders.ProcessPaymentStatusChange(ctx, messages.PaymentStatusChangedMessage{
+			ID:        payment.ID.String(),
+			OrderID:   payment.OrderID.String(),
+			Status:    string(remoteStatus),
+			UpdatedAt: time.Now().Format(time.RFC3339),
+		}); err != nil {
+			report.Failed++
+			r.log.Log(
+				"failed applying reconciled payment status",
+				zap.String("payment_id", payment.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		report.Applied++
+	}
+
+	return report, nil
+}
+
+// RunPaymentReconciliation periodically reconciles stale payments until ctx is done
+func RunPaymentReconciliation(ctx context.Context, r *PaymentReconciler, cfg PaymentReconciliationConfig, log kitlog.Logger) {
+	if r == nil || cfg.Interval <= 0 {
+		log.Log("message", "payment reconciliation job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := r.Reconcile(ctx, cfg.OlderThan)
+			if err != nil {
+				log.Log(
+					"payment reconciliation run failed",
+					zap.Error(err),
+				)
+				continue
+			}
+			if len(report.Discrepancies) > 0 || report.Failed > 0 {
+				log.Log(
+					"payment reconciliation report",
+					zap.Int("checked", report.Checked),
+					zap.Int("discrepancies", len(report.Discrepancies)),
+					zap.Int("applied", report.Applied),
+					zap.Int("failed", report.Failed),
+				)
+			}
+		}
+	}
+}
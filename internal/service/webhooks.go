@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/persistence"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	ordermsgs "github.com/SOAT1StackGoLang/msvc-orders/pkg/messages"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookMaxAttempts      = 6
+	webhookBaseBackoff      = 2 * time.Second
+	webhookRequestTimeout   = 10 * time.Second
+	webhookDeliveriesLimit  = 100
+	webhookSecretBytes      = 32
+	webhookUserAgent        = "msvc-orders-webhooks/1.0"
+	webhookResubscribeDelay = time.Second
+	// other instances may change the subscriptions, the cache is only reloaded on our own changes or after this
+	webhookSubscriptionsTTL = time.Minute
+)
+
+type webhooksSvc struct {
+	repo    persistence.WebhooksRepository
+	events  OrderEventsHub
+	client  *http.Client
+	backoff time.Duration
+	log     kitlog.Logger
+
+	mu       sync.RWMutex
+	subs     []*models.WebhookSubscription
+	loadedAt time.Time
+}
+
+// NewWebhooksService manages the outbound webhook subscriptions. Call Run to start delivering events.
+func NewWebhooksService(repo persistence.WebhooksRepository, events OrderEventsHub, log kitlog.Logger) WebhooksService {
+	return &webhooksSvc{
+		repo:    repo,
+		events:  events,
+		client:  &http.Client{Timeout: webhookRequestTimeout},
+		backoff: webhookBaseBackoff,
+		log:     log,
+	}
+}
+
+// CreateSubscription registers url for the given statuses, every status when empty.
+// A secret is generated when none is given, it is only returned here.
+func (w *webhooksSvc) CreateSubscription(ctx context.Context, rawURL, secret string, statuses []models.OrderStatus) (*models.WebhookSubscription, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, helpers.ErrInvalidInput
+	}
+	for _, s := range statuses {
+		if !s.IsKnown() {
+			return nil, helpers.ErrInvalidInput
+		}
+	}
+
+	if secret == "" {
+		raw := make([]byte, webhookSecretBytes)
+		if _, err = rand.Read(raw); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(raw)
+	}
+
+	sub, err := w.repo.CreateSubscription(ctx, &models.WebhookSubscription{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		URL:       target.String(),
+		Secret:    secret,
+		Statuses:  statuses,
+	})
+	if err == nil {
+		w.invalidateSubscriptions()
+	}
+	return sub, err
+}
+
+func (w *webhooksSvc) GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	return w.repo.GetSubscription(ctx, id)
+}
+
+func (w *webhooksSvc) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return w.repo.ListSubscriptions(ctx)
+}
+
+func (w *webhooksSvc) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.DeleteSubscription(ctx, id)
+	if err == nil {
+		w.invalidateSubscriptions()
+	}
+	return err
+}
+
+// activeSubscriptions keeps the subscriptions in memory for webhookSubscriptionsTTL, every event needs them
+func (w *webhooksSvc) activeSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	w.mu.RLock()
+	subs, fresh := w.subs, w.subs != nil && time.Since(w.loadedAt) < webhookSubscriptionsTTL
+	w.mu.RUnlock()
+	if fresh {
+		return subs, nil
+	}
+
+	loadedAt := time.Now()
+	subs, err := w.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	// a change made while loading already invalidated what we read
+	if !w.loadedAt.After(loadedAt) {
+		w.subs = subs
+		w.loadedAt = loadedAt
+	}
+	w.mu.Unlock()
+
+	return subs, nil
+}
+
+func (w *webhooksSvc) invalidateSubscriptions() {
+	w.mu.Lock()
+	w.subs = nil
+	w.loadedAt = time.Now()
+	w.mu.Unlock()
+}
+
+func (w *webhooksSvc) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]*models.WebhookDelivery, error) {
+	if _, err := w.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return w.repo.ListDeliveries(ctx, subscriptionID, webhookDeliveriesLimit)
+}
+
+// Run delivers every order status change to the matching subscriptions until ctx is done
+func (w *webhooksSvc) Run(ctx context.Context) {
+	var lastEventID uint64
+
+	for {
+		events, cancel, missed := w.events.Subscribe(models.OrderEventFilter{}, lastEventID)
+		if missed {
+			// the hub no longer keeps what happened while we were away, those deliveries are lost
+			w.log.Log(
+				"order events missed by webhooks",
+				zap.Uint64("last_event_id", lastEventID),
+			)
+		}
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				cancel()
+				return
+			case e, ok := <-events:
+				if !ok {
+					// dropped by the hub for falling behind, resume from the last event seen
+					open = false
+					break
+				}
+				lastEventID = e.ID
+				w.dispatch(ctx, e)
+			}
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookResubscribeDelay):
+		}
+	}
+}
+
+func (w *webhooksSvc) dispatch(ctx context.Context, e models.OrderEvent) {
+	subs, err := w.activeSubscriptions(ctx)
+	if err != nil {
+		w.log.Log(
+			"failed loading webhook subscriptions",
+			zap.Uint64("event_id", e.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	msg := ordermsgs.OrderWebhookMessage{
+		ID:             uuid.NewString(),
+		Event:          ordermsgs.OrderStatusChangedEvent,
+		OrderID:        e.OrderID.String(),
+		Status:         string(e.Status),
+		PreviousStatus: string(e.PreviousStatus),
+		OccurredAt:     e.OccurredAt.Format(time.RFC3339),
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		w.log.Log(
+			"failed marshalling webhook message",
+			zap.Uint64("event_id", e.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, sub := range subs {
+		if sub.Filter().Matches(e) {
+			go w.deliver(ctx, sub, e, msg.ID, body)
+		}
+	}
+}
+
+// deliver retries with exponential backoff, logging every attempt, until the subscriber answers 2xx
+// or gives a final answer
+func (w *webhooksSvc) deliver(ctx context.Context, sub *models.WebhookSubscription, e models.OrderEvent, deliveryID string, body []byte) {
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		code, err := w.send(ctx, sub, deliveryID, body)
+
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        e.ID,
+			OrderID:        e.OrderID,
+			OrderStatus:    e.Status,
+			Attempt:        attempt,
+			StatusCode:     code,
+			Succeeded:      err == nil && code >= 200 && code < 300,
+			AttemptedAt:    time.Now(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		w.repo.RecordDelivery(ctx, delivery)
+
+		if delivery.Succeeded || !retryableWebhookStatus(code, err) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.backoff * time.Duration(1<<(attempt-1))):
+		}
+	}
+
+	w.log.Log(
+		"giving up webhook delivery",
+		zap.String("subscription_id", sub.ID.String()),
+		zap.Uint64("event_id", e.ID),
+		zap.Int("attempts", webhookMaxAttempts),
+	)
+}
+
+func (w *webhooksSvc) send(ctx context.Context, sub *models.WebhookSubscription, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(helpers.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(helpers.SignatureHeader, helpers.SignPayload([]byte(sub.Secret), now, body))
+	req.Header.Set(ordermsgs.WebhookEventHeader, ordermsgs.OrderStatusChangedEvent)
+	req.Header.Set(ordermsgs.WebhookDeliveryIDHeader, deliveryID)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	return resp.StatusCode, nil
+}
+
+// retryableWebhookStatus treats client errors as final, except timeouts and rate limiting
+func retryableWebhookStatus(code int, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch {
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return true
+	case code >= 400 && code < 500:
+		return false
+	default:
+		return true
+	}
+}
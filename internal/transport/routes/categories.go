@@ -30,13 +30,20 @@ func NewCategoriesRouter(svc service.CategoriesService, r *mux.Router, logger ki
 		httptransport.ServerErrorEncoder(encodeError),
 	}
 
-	r.Methods(http.MethodGet).Path("/category/all").Queries("limit", "{limit:[0-9]+}", "offset", "{offset:[0-9]+}").Handler(httptransport.NewServer(
+	r.Methods(http.MethodGet).Path("/category/all").Handler(httptransport.NewServer(
 		catEndpoints.ListCategoriesEndpoint,
 		decodeListCategoriesRequest,
 		encodeResponse,
 		options...,
 	))
 
+	r.Methods(http.MethodGet).Path("/category/tree").Handler(httptransport.NewServer(
+		catEndpoints.CategoryTreeEndpoint,
+		decodeCategoryTreeRequest,
+		encodeResponse,
+		options...,
+	))
+
 	r.Methods(http.MethodGet).Path("/category/{id}").Handler(httptransport.NewServer(
 		catEndpoints.GetCategoryEndpoint,
 		decodeGetCategoriesRequest,
@@ -51,6 +58,20 @@ func NewCategoriesRouter(svc service.CategoriesService, r *mux.Router, logger ki
 		options...,
 	))
 
+	r.Methods(http.MethodPut).Path("/category/{id}").Handler(httptransport.NewServer(
+		catEndpoints.UpdateCategoryEndpoint,
+		decodeUpdateCategoryRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPut).Path("/category/{id}/window").Handler(httptransport.NewServer(
+		catEndpoints.SetWindowEndpoint,
+		decodeSetWindowRequest,
+		encodeResponse,
+		options...,
+	))
+
 	r.Methods(http.MethodDelete).Path("/category/{id}").Handler(httptransport.NewServer(
 		catEndpoints.DeleteCategoryEndpoint,
 		decodeDeleteCategoriesRequest,
@@ -69,35 +90,22 @@ func NewCategoriesRouter(svc service.CategoriesService, r *mux.Router, logger ki
 //	@Description	List all categories
 //	@ID				list-categories
 //	@Produce		json
-//	@Param			limit	query		int		true	"Limit"		default(10)
-//	@Param			offset	query		int		true	"Offset"	default(0)
+//	@Param			limit	query		int		false	"Limit, at most 100"				default(20)
+//	@Param			cursor	query		string	false	"next_cursor or prev_cursor of a previous page"
 //	@Success		200		{string}	string	"ok"
 //	@Failure		400		{string}	string	"error"
 //	@Failure		404		{string}	string	"Not Found"
 //	@Failure		500		{string}	string	"Inernal Server Error"
 //	@Router			/category/all [get]
 func decodeListCategoriesRequest(_ context.Context, r *http.Request) (request any, err error) {
-	query := r.URL.Query()
-	limit := query.Get("limit")
-
-	limitInt, err := strconv.ParseInt(limit, 10, 64)
-	if err != nil {
-		return nil, err
-	}
-	if limitInt == 0 {
-		return nil, ErrBadRequest
-	}
-
-	offset := query.Get("offset")
-
-	offsetInt, err := strconv.ParseInt(offset, 10, 64)
+	limit, cursor, err := decodePageQuery(r.URL.Query())
 	if err != nil {
 		return nil, err
 	}
 
 	return endpoint.ListCategoriesRequest{
-		Limit:  limitInt,
-		Offset: offsetInt,
+		Limit:  limit,
+		Cursor: cursor,
 	}, nil
 
 }
@@ -107,14 +115,16 @@ func decodeListCategoriesRequest(_ context.Context, r *http.Request) (request an
 //	@Summary		Delete a category
 //	@Tags			Categories
 //	@Security		ApiKeyAuth
-//	@Description	Delete a category
+//	@Description	Archives a category. It is refused while the category has active products, unless cascade archives them too.
 //	@ID				delete-category
 //	@Accept			json
-//	@Param			id	path		string	true	"Category ID"
-//	@Success		200	{string}	string	"ok"
-//	@Failure		400	{string}	string	"error"
-//	@Failure		404	{string}	string	"Not Found"
-//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Param			id		path		string	true	"Category ID"
+//	@Param			cascade	query		bool	false	"Archive the active products of the category too"	default(false)
+//	@Success		200		{string}	string	"ok"
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
 //	@Router			/category/{id} [delete]
 func decodeDeleteCategoriesRequest(_ context.Context, r *http.Request) (request any, err error) {
 	vars := mux.Vars(r)
@@ -124,7 +134,14 @@ func decodeDeleteCategoriesRequest(_ context.Context, r *http.Request) (request
 		return nil, ErrBadRouting
 	}
 
-	return endpoint.DeleteCategoryRequest{ID: id}, nil
+	cascade := false
+	if c := r.URL.Query().Get("cascade"); c != "" {
+		if cascade, err = strconv.ParseBool(c); err != nil {
+			return nil, ErrBadRequest
+		}
+	}
+
+	return endpoint.DeleteCategoryRequest{ID: id, Cascade: cascade}, nil
 }
 
 // InsertCategories
@@ -136,7 +153,7 @@ func decodeDeleteCategoriesRequest(_ context.Context, r *http.Request) (request
 //	@ID				insert-category
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		string	true	"Category data"	SchemaExample({\r\n  "name": "Bebidas Importadas"\r\n})
+//	@Param			request	body		string	true	"Category data"	SchemaExample({\r\n  "name": "Bebidas Importadas",\r\n  "parent_id": "a557b0c0-3bcf-11ee-be56-0242ac120002",\r\n  "display_order": 2\r\n})
 //	@Success		200		{string}	string	"ok"
 //	@Failure		400		{string}	string	"error"
 //	@Failure		404		{string}	string	"Not Found"
@@ -148,9 +165,7 @@ func decodeInsertCategoriesRequest(_ context.Context, r *http.Request) (request
 		return nil, ErrBadRequest
 	}
 
-	return endpoint.InsertCategoryRequest{
-		Name: req.Name,
-	}, nil
+	return req, nil
 }
 
 // GetCategory
@@ -177,3 +192,84 @@ func decodeGetCategoriesRequest(_ context.Context, r *http.Request) (request any
 
 	return endpoint.GetCategoryRequest{ID: id}, nil
 }
+
+// UpdateCategory
+//
+//	@Summary		Update a category
+//	@Tags			Categories
+//	@Security		ApiKeyAuth
+//	@Description	Renames, reorders or moves a category. An empty parent moves it to the top level, nesting it under itself or one of its subcategories is refused.
+//	@ID				update-category
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Category ID"
+//	@Param			request	body		string	true	"Category data"	SchemaExample({\r\n  "name": "Refrigerantes",\r\n  "parent_id": "a557b0c0-3bcf-11ee-be56-0242ac120002",\r\n  "display_order": 1\r\n})
+//	@Success		200		{object}	endpoint.GetCategoryResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/category/{id} [put]
+func decodeUpdateCategoryRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.UpdateCategoryRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// SetCategoryWindow
+//
+//	@Summary		Set the hours a category is sold
+//	@Tags			Categories
+//	@Security		ApiKeyAuth
+//	@Description	Limits the hours of the day the products of the category and of its subcategories can be ordered, in the store timezone. Products and subcategories with their own window keep it. Empty times lift the limit.
+//	@ID				set-category-window
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Category ID"
+//	@Param			request	body		string	true	"Window data"	SchemaExample({\r\n  "available_from": "06:00",\r\n  "available_to": "10:30"\r\n})
+//	@Success		200		{object}	endpoint.GetCategoryResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/category/{id}/window [put]
+func decodeSetWindowRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.SetWindowRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// CategoryTree
+//
+//	@Summary		Category tree
+//	@Tags			Categories
+//	@Security		ApiKeyAuth
+//	@Description	Every category nested under its parent, siblings in display order
+//	@ID				category-tree
+//	@Produce		json
+//	@Success		200	{object}	endpoint.CategoryTreeResponse
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/category/tree [get]
+func decodeCategoryTreeRequest(_ context.Context, _ *http.Request) (request any, err error) {
+	return nil, nil
+}
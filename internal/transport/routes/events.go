@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetryMillis       = 3000
+)
+
+// kitchenStatuses are the transitions the kitchen displays care about
+var kitchenStatuses = []models.OrderStatus{
+	models.ORDER_STATUS_RECEIVED,
+	models.ORDER_STATUS_PREPARING,
+	models.ORDER_STATUS_DONE,
+	models.ORDER_STATUS_FINISHED,
+	models.ORDER_STATUS_CANCELED,
+}
+
+type sseHandler struct {
+	svc    service.OrdersService
+	hub    service.OrderEventsHub
+	logger kitlog.Logger
+}
+
+func NewOrderEventsRouter(svc service.OrdersService, hub service.OrderEventsHub, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	h := &sseHandler{svc: svc, hub: hub, logger: logger}
+
+	r.Methods(http.MethodGet).Path("/order/{id}/events").HandlerFunc(h.orderEvents)
+	r.Methods(http.MethodGet).Path("/kitchen/events").HandlerFunc(h.kitchenEvents)
+
+	return r
+}
+
+// OrderEvents godoc
+//
+//	@Summary		Stream status changes of an order
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Server-Sent Events stream with every status change of the order. Send Last-Event-ID to resume after a reconnection, a "snapshot" event with the current status is sent first and again when the events since then are no longer kept.
+//	@Produce		text/event-stream
+//	@Param			id				path		string	true	"Order ID"
+//	@Param			Last-Event-ID	header		string	false	"Last received event ID"
+//	@Success		200				{string}	string	"event stream"
+//	@Failure		404				{string}	string	"Not Found"
+//	@Failure		500				{string}	string	"Inernal Server Error"
+//	@Router			/order/{id}/events [get]
+func (h *sseHandler) orderEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	orderID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		encodeError(r.Context(), ErrBadRequest, w)
+		return
+	}
+
+	snapshot := func(ctx context.Context) (*models.Order, error) {
+		return h.svc.GetOrder(ctx, orderID)
+	}
+
+	h.stream(w, r, models.OrderEventFilter{OrderID: orderID}, parseLastEventID(r), snapshot)
+}
+
+// KitchenEvents godoc
+//
+//	@Summary		Stream kitchen status changes
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Server-Sent Events stream with status changes of every order relevant to the kitchen. Send Last-Event-ID to resume after a reconnection, a "reset" event tells the display to reload when the events since then are no longer kept.
+//	@Produce		text/event-stream
+//	@Param			Last-Event-ID	header		string	false	"Last received event ID"
+//	@Success		200				{string}	string	"event stream"
+//	@Failure		500				{string}	string	"Inernal Server Error"
+//	@Router			/kitchen/events [get]
+func (h *sseHandler) kitchenEvents(w http.ResponseWriter, r *http.Request) {
+	h.stream(w, r, models.OrderEventFilter{Statuses: kitchenStatuses}, parseLastEventID(r), nil)
+}
+
+func (h *sseHandler) stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	filter models.OrderEventFilter,
+	lastEventID uint64,
+	snapshot func(ctx context.Context) (*models.Order, error),
+) {
+	// snapshot loads the current state, sent on the first connection and when resuming from lastEventID is no
+	// longer possible. Streams without one are told to reset instead.
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		encodeError(r.Context(), ErrStreamingUnsupported, w)
+		return
+	}
+
+	// subscribing before loading the snapshot keeps a change made in between from being lost
+	events, cancel, missed := h.hub.Subscribe(filter, lastEventID)
+	defer cancel()
+
+	var current *models.Order
+	if snapshot != nil {
+		var err error
+		if current, err = snapshot(r.Context()); err != nil {
+			encodeError(r.Context(), err, w)
+			return
+		}
+	}
+	sendSnapshot := current != nil && (lastEventID == 0 || missed)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	switch {
+	case sendSnapshot:
+		if err := writeSSE(w, "", "snapshot", &endpoint.OrderEventResponse{
+			OrderID:    current.ID.String(),
+			Status:     string(current.Status),
+			OccurredAt: current.UpdatedAt.String(),
+		}); err != nil {
+			return
+		}
+	case missed:
+		if err := writeSSE(w, "", "reset", struct{}{}); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-events:
+			if !ok {
+				// the hub dropped us for falling behind, the client resumes with Last-Event-ID
+				h.logger.Log("message", "order events stream closed by hub", "order_id", filter.OrderID.String())
+				return
+			}
+			// buffered before the snapshot was read, the client already has that state. Stored timestamps
+			// keep microseconds only.
+			if sendSnapshot && !e.OccurredAt.Round(time.Microsecond).After(current.UpdatedAt) {
+				continue
+			}
+			if err := writeSSE(w, strconv.FormatUint(e.ID, 10), "status", endpoint.OrderEventResponseFromModel(e)); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, id, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err = fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+func parseLastEventID(r *http.Request) uint64 {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
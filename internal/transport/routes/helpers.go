@@ -25,6 +25,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
 )
 
 type errorer interface {
@@ -32,7 +36,8 @@ type errorer interface {
 }
 
 var (
-	ErrBadRequest = errors.New("parametros incorretos")
+	ErrBadRequest           = errors.New("parametros incorretos")
+	ErrStreamingUnsupported = errors.New("streaming nao suportado")
 )
 
 func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
@@ -63,7 +68,25 @@ func codeFrom(err error) int {
 	switch err {
 	case ErrBadRequest:
 		return http.StatusNotFound
+	case helpers.ErrNotFound:
+		return http.StatusNotFound
+	case helpers.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case helpers.ErrInvalidInput, helpers.ErrProductUnavailable, helpers.ErrCategoryCycle:
+		return http.StatusBadRequest
+	case helpers.ErrInvalidStatusTransition, helpers.ErrOrderNotCancelable, helpers.ErrOrderNotEditable, helpers.ErrRefundNotAllowed, helpers.ErrPaymentNotRetryable, helpers.ErrPixUnavailable, helpers.ErrWebhookReplayed, helpers.ErrOutOfStock, helpers.ErrCategoryNotEmpty, helpers.ErrPriceNotScheduled, helpers.ErrStoreClosed:
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
 }
+
+// decodePageQuery reads the optional limit and cursor of the paginated lists
+func decodePageQuery(query url.Values) (limit int, cursor string, err error) {
+	if l := query.Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit < 0 {
+			return 0, "", ErrBadRequest
+		}
+	}
+	return limit, query.Get("cursor"), nil
+}
@@ -0,0 +1,167 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	kittransport "github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+func NewStoreHoursRouter(svc service.StoreHoursService, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	hoursEndpoints := endpoint.MakeStoreHoursEndpoints(svc)
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(kittransport.NewLogErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	r.Methods(http.MethodGet).Path("/store/status").Handler(httptransport.NewServer(
+		hoursEndpoints.GetStoreStatusEndpoint,
+		decodeGetStoreStatusRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/store/hours").Handler(httptransport.NewServer(
+		hoursEndpoints.GetStoreHoursEndpoint,
+		decodeGetStoreHoursRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPut).Path("/store/hours").Handler(httptransport.NewServer(
+		hoursEndpoints.SetBusinessHoursEndpoint,
+		decodeSetBusinessHoursRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPut).Path("/store/hours/exceptions/{day}").Handler(httptransport.NewServer(
+		hoursEndpoints.SetHoursExceptionEndpoint,
+		decodeSetHoursExceptionRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodDelete).Path("/store/hours/exceptions/{day}").Handler(httptransport.NewServer(
+		hoursEndpoints.DeleteHoursExceptionEndpoint,
+		decodeDeleteHoursExceptionRequest,
+		encodeResponse,
+		options...,
+	))
+
+	return r
+}
+
+// GetStoreStatus godoc
+//
+//	@Summary		Store status
+//	@Tags			Store
+//	@Description	Tells whether the store takes orders now. Orders are refused with 409 while it is closed.
+//	@ID				get-store-status
+//	@Produce		json
+//	@Success		200	{object}	endpoint.StoreStatusResponse
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/store/status [get]
+func decodeGetStoreStatusRequest(_ context.Context, _ *http.Request) (request any, err error) {
+	return endpoint.GetStoreHoursRequest{}, nil
+}
+
+// GetStoreHours godoc
+//
+//	@Summary		Business hours
+//	@Tags			Store
+//	@Security		ApiKeyAuth
+//	@Description	Weekly hours and the exceptions from yesterday on, in the store timezone
+//	@ID				get-store-hours
+//	@Produce		json
+//	@Success		200	{object}	endpoint.StoreScheduleResponse
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/store/hours [get]
+func decodeGetStoreHoursRequest(_ context.Context, _ *http.Request) (request any, err error) {
+	return endpoint.GetStoreHoursRequest{}, nil
+}
+
+// SetBusinessHours godoc
+//
+//	@Summary		Set business hours
+//	@Tags			Store
+//	@Security		ApiKeyAuth
+//	@Description	Replaces the weekly hours. A weekday may have several periods that don't overlap, a period closing before it opens goes past midnight. Without hours the store is always open.
+//	@ID				set-store-hours
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		endpoint.SetBusinessHoursRequest	true	"Weekly hours"
+//	@Success		200		{object}	endpoint.StoreScheduleResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/store/hours [put]
+func decodeSetBusinessHoursRequest(_ context.Context, r *http.Request) (request any, err error) {
+	var req endpoint.SetBusinessHoursRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	return req, nil
+}
+
+// SetHoursException godoc
+//
+//	@Summary		Set a business hours exception
+//	@Tags			Store
+//	@Security		ApiKeyAuth
+//	@Description	Closes the store on a day, like a holiday, or gives it special hours. It replaces the weekly hours of that day.
+//	@ID				set-store-hours-exception
+//	@Accept			json
+//	@Produce		json
+//	@Param			day		path		string						true	"Day, YYYY-MM-DD"
+//	@Param			request	body		endpoint.HoursExceptionEntry	true	"Exception data"
+//	@Success		200		{object}	endpoint.StoreScheduleResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/store/hours/exceptions/{day} [put]
+func decodeSetHoursExceptionRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	day, ok := vars["day"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.HoursExceptionEntry
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.Day = day
+
+	return req, nil
+}
+
+// DeleteHoursException godoc
+//
+//	@Summary		Delete a business hours exception
+//	@Tags			Store
+//	@Security		ApiKeyAuth
+//	@Description	The day goes back to the weekly hours
+//	@ID				delete-store-hours-exception
+//	@Produce		json
+//	@Param			day	path		string	true	"Day, YYYY-MM-DD"
+//	@Success		200	{object}	endpoint.StoreScheduleResponse
+//	@Failure		400	{string}	string	"error"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/store/hours/exceptions/{day} [delete]
+func decodeDeleteHoursExceptionRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	day, ok := vars["day"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.DeleteHoursExceptionRequest{Day: day}, nil
+}
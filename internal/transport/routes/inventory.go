@@ -0,0 +1,239 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	kittransport "github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+// stockMovementsDefaultLimit is how much history is shown when the client doesn't ask for a limit
+const stockMovementsDefaultLimit = 50
+
+func NewInventoryRouter(svc service.InventoryService, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	inventoryEndpoints := endpoint.MakeInventoryEndpoints(svc)
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(kittransport.NewLogErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	r.Methods(http.MethodPost).Path("/inventory/ingredients").Handler(httptransport.NewServer(
+		inventoryEndpoints.CreateIngredientEndpoint,
+		decodeCreateIngredientRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/inventory/ingredients").Handler(httptransport.NewServer(
+		inventoryEndpoints.ListIngredientsEndpoint,
+		decodeListIngredientsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/inventory/ingredients/{id}").Handler(httptransport.NewServer(
+		inventoryEndpoints.GetIngredientEndpoint,
+		decodeGetIngredientRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPost).Path("/inventory/ingredients/{id}/adjustments").Handler(httptransport.NewServer(
+		inventoryEndpoints.AdjustStockEndpoint,
+		decodeAdjustStockRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/inventory/ingredients/{id}/movements").Handler(httptransport.NewServer(
+		inventoryEndpoints.ListStockMovementsEndpoint,
+		decodeListStockMovementsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPut).Path("/product/{id}/recipe").Handler(httptransport.NewServer(
+		inventoryEndpoints.SetRecipeEndpoint,
+		decodeSetRecipeRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/product/{id}/recipe").Handler(httptransport.NewServer(
+		inventoryEndpoints.GetRecipeEndpoint,
+		decodeGetRecipeRequest,
+		encodeResponse,
+		options...,
+	))
+
+	return r
+}
+
+// CreateIngredient godoc
+//
+//	@Summary	Create an ingredient
+//	@Tags		Inventory
+//	@Security	ApiKeyAuth
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		string	true	"Ingredient data"	SchemaExample({\r\n "name": "Pão brioche",\r\n "unit": "un",\r\n "stock": "120"\r\n})
+//	@Success	200		{object}	endpoint.IngredientResponse
+//	@Failure	400		{string}	string	"Bad Request"
+//	@Failure	500		{string}	string	"Inernal Server Error"
+//	@Router		/inventory/ingredients [post]
+func decodeCreateIngredientRequest(_ context.Context, r *http.Request) (request any, err error) {
+	var req endpoint.CreateIngredientRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+
+	return req, nil
+}
+
+// ListIngredients godoc
+//
+//	@Summary	List ingredients
+//	@Tags		Inventory
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	endpoint.IngredientList
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/inventory/ingredients [get]
+func decodeListIngredientsRequest(_ context.Context, _ *http.Request) (request any, err error) {
+	return nil, nil
+}
+
+// GetIngredient godoc
+//
+//	@Summary	Get an ingredient stock
+//	@Tags		Inventory
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Ingredient ID"
+//	@Success	200	{object}	endpoint.IngredientResponse
+//	@Failure	404	{string}	string	"Not Found"
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/inventory/ingredients/{id} [get]
+func decodeGetIngredientRequest(_ context.Context, r *http.Request) (request any, err error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.GetIngredientRequest{ID: id}, nil
+}
+
+// AdjustStock godoc
+//
+//	@Summary		Adjust an ingredient stock
+//	@Tags			Inventory
+//	@Security		ApiKeyAuth
+//	@Description	Records a delivery, loss or count. Delta is added to the stock on hand, which never goes below zero.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Ingredient ID"
+//	@Param			request	body		string	true	"Adjustment data"	SchemaExample({\r\n "delta": "-4",\r\n "reason": "pães vencidos",\r\n "actor": "gerente-01"\r\n})
+//	@Success		200		{object}	endpoint.IngredientResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/inventory/ingredients/{id}/adjustments [post]
+func decodeAdjustStockRequest(_ context.Context, r *http.Request) (request any, err error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.AdjustStockRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// ListStockMovements godoc
+//
+//	@Summary		List stock movements
+//	@Tags			Inventory
+//	@Security		ApiKeyAuth
+//	@Description	Audit trail of an ingredient: reservations, commits, releases and adjustments, newest first
+//	@Produce		json
+//	@Param			id		path		string	true	"Ingredient ID"
+//	@Param			limit	query		int		false	"Limit"	default(50)
+//	@Success		200		{object}	endpoint.StockMovementList
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/inventory/ingredients/{id}/movements [get]
+func decodeListStockMovementsRequest(_ context.Context, r *http.Request) (request any, err error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	limit := stockMovementsDefaultLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			return nil, ErrBadRequest
+		}
+	}
+
+	return endpoint.ListStockMovementsRequest{ID: id, Limit: limit}, nil
+}
+
+// SetRecipe godoc
+//
+//	@Summary		Set a product recipe
+//	@Tags			Inventory
+//	@Security		ApiKeyAuth
+//	@Description	Replaces the ingredients one unit of the product takes. The product leaves the menu when any of them runs out, an empty recipe stops tracking it.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Product ID"
+//	@Param			request	body		string	true	"Recipe"	SchemaExample({\r\n "items": [\r\n {"ingredient_id": "b7b7a3c2-9d4e-4f4b-8e1a-3f1f2d6c9a10", "quantity": "1"}\r\n ]\r\n})
+//	@Success		200		{object}	endpoint.RecipeResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/recipe [put]
+func decodeSetRecipeRequest(_ context.Context, r *http.Request) (request any, err error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.SetRecipeRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ProductID = id
+
+	return req, nil
+}
+
+// GetRecipe godoc
+//
+//	@Summary	Get a product recipe
+//	@Tags		Inventory
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Product ID"
+//	@Success	200	{object}	endpoint.RecipeResponse
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/product/{id}/recipe [get]
+func decodeGetRecipeRequest(_ context.Context, r *http.Request) (request any, err error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.GetProductRequest{ID: id}, nil
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+// menuMaxAge lets kiosks reuse the menu for a while and revalidate it cheaply with the ETag afterwards
+const menuMaxAge = "public, max-age=30"
+
+type menuHandler struct {
+	svc    service.MenuService
+	logger kitlog.Logger
+}
+
+func NewMenuRouter(svc service.MenuService, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	h := &menuHandler{svc: svc, logger: logger}
+
+	r.Methods(http.MethodGet).Path("/menu").HandlerFunc(h.get)
+
+	return r
+}
+
+// GetMenu godoc
+//
+//	@Summary		Full menu
+//	@Tags			Menu
+//	@Description	Every category with its products in a single call, answered with 304 when the ETag or Last-Modified sent by the kiosk still match
+//	@Produce		json
+//	@Success		200	{object}	endpoint.MenuResponse
+//	@Success		304	{string}	string	"Not Modified"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/menu [get]
+func (h *menuHandler) get(w http.ResponseWriter, r *http.Request) {
+	menu, err := h.svc.GetMenu(r.Context())
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	body, err := json.Marshal(endpoint.MenuResponseFromModel(menu))
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	// HTTP dates have no fractions of a second
+	lastModified := menu.UpdatedAt.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	if notModified(w, r, body, menuMaxAge) {
+		return
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && r.Header.Get("If-None-Match") == "" &&
+		!lastModified.IsZero() && !lastModified.After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(body)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
@@ -25,13 +26,62 @@ func NewOrdersRouter(svc service.OrdersService, r *mux.Router, logger kitlog.Log
 		httptransport.ServerErrorEncoder(encodeError),
 	}
 
-	r.Methods(http.MethodGet).Path("/order/all").Queries("limit", "{limit:[0-9]+}", "offset", "{offset:[0-9]+}").Handler(httptransport.NewServer(
+	r.Methods(http.MethodGet).Path("/order/all").Handler(httptransport.NewServer(
 		ordersEnpoints.ListOrdersEndpoint,
 		decodeListOrdersRequest,
 		encodeResponse,
 		options...,
 	))
 
+	r.Methods(http.MethodGet).Path("/order/number/{number:[0-9]+}").Handler(httptransport.NewServer(
+		ordersEnpoints.GetOrderByPickupNumber,
+		decodeGetOrderByPickupNumberRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPost).Path("/order/{id}/cancel").Handler(httptransport.NewServer(
+		ordersEnpoints.CancelOrderEndpoint,
+		decodeCancelOrderRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPut).Path("/order/{id}/adjustments").Handler(httptransport.NewServer(
+		ordersEnpoints.UpdateAdjustments,
+		decodeUpdateOrderAdjustmentsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/order/{id}/payments").Handler(httptransport.NewServer(
+		ordersEnpoints.ListOrderPayments,
+		decodeListOrderPaymentsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPost).Path("/order/{id}/payments").Handler(httptransport.NewServer(
+		ordersEnpoints.RetryPaymentEndpoint,
+		decodeRetryPaymentRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodPost).Path("/order/{id}/refunds").Handler(httptransport.NewServer(
+		ordersEnpoints.RefundOrderEndpoint,
+		decodeRefundOrderRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/order/{id}/refunds").Handler(httptransport.NewServer(
+		ordersEnpoints.ListOrderRefunds,
+		decodeListOrderRefundsRequest,
+		encodeResponse,
+		options...,
+	))
+
 	r.Methods(http.MethodGet).Path("/order/{id}").Handler(httptransport.NewServer(
 		ordersEnpoints.GetOrderEndpoint,
 		decodeGetOrderRequest,
@@ -39,6 +89,13 @@ func NewOrdersRouter(svc service.OrdersService, r *mux.Router, logger kitlog.Log
 		options...,
 	))
 
+	r.Methods(http.MethodGet).Path("/order").Handler(httptransport.NewServer(
+		ordersEnpoints.SearchOrdersEndpoint,
+		decodeSearchOrdersRequest,
+		encodeResponse,
+		options...,
+	))
+
 	r.Methods(http.MethodPost).Path("/order").Handler(httptransport.NewServer(
 		ordersEnpoints.CreateOrderEndpoint,
 		decodeCreateOrderRequest,
@@ -63,6 +120,12 @@ func NewOrdersRouter(svc service.OrdersService, r *mux.Router, logger kitlog.Log
 		encodeResponse,
 		options...,
 	))
+	r.Methods(http.MethodPost).Path("/order/checkout/{id}/split").Handler(httptransport.NewServer(
+		ordersEnpoints.SplitCheckoutEndpoint,
+		decodeSplitCheckoutRequest,
+		encodeResponse,
+		options...,
+	))
 	r.Methods(http.MethodGet).PathPrefix("/swagger").Handler(httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"), // URL pointing to the API definition
 		httpSwagger.DeepLinking(true),
@@ -86,6 +149,7 @@ func NewOrdersRouter(svc service.OrdersService, r *mux.Router, logger kitlog.Log
 //	@Success	200	{string}	string	"ok"
 //	@Failure	400	{string}	string	"error"
 //	@Failure	404	{string}	string	"error"
+//	@Failure	409	{string}	string	"error"
 //	@Failure	500	{string}	string	"error"
 //	@Router		/order/checkout/{id} [get]
 func decodeOrderCheckout(_ context.Context, r *http.Request) (request any, err error) {
@@ -99,6 +163,210 @@ func decodeOrderCheckout(_ context.Context, r *http.Request) (request any, err e
 	return endpoint.CheckoutOrderRequest{ID: id}, nil
 }
 
+// SplitCheckout godoc
+//
+//	@Summary		Checkout an order splitting the bill
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Charges each part separately, by a fixed amount or by items plus their share of tip and service charge. The parts must add up to the order total and the order is received once every part is approved. When a part is refused or fails the other parts are canceled or refunded.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Order ID"
+//	@Param			request	body		string	true	"Split data"	SchemaExample({\r\n "parts": [\r\n  {"payer": "Ana", "amount": "20.00"},\r\n  {"payer": "Bruno", "items": [{"product_id": "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", "quantity": 1}]}\r\n ]\r\n})
+//	@Success		200		{object}	endpoint.OrderResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/order/checkout/{id}/split [post]
+func decodeSplitCheckoutRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.SplitCheckoutRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// CancelOrder godoc
+//
+//	@Summary		Cancel an order
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Cancels the order compensating its current stage: open orders are closed, pending charges are canceled and orders already sent to production are withdrawn and refunded. Ready and finished orders can't be canceled.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Order ID"
+//	@Param			request	body		string	true	"Cancellation data"	SchemaExample({\r\n "reason": "customer_request",\r\n "actor": "atendente-01"\r\n})
+//	@Success		200		{object}	endpoint.OrderResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/order/{id}/cancel [post]
+func decodeCancelOrderRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.CancelOrderRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// UpdateOrderAdjustments godoc
+//
+//	@Summary		Set tip and service charge
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Sets the tip, fixed or as a percentage of the subtotal, and whether the store service charge applies, a field left out keeps its current value. Only open orders can be changed, the payment is created for the total at checkout.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Order ID"
+//	@Param			request	body		string	true	"Adjustments data"	SchemaExample({\r\n "tip": {"percentage": "10"},\r\n "service_charge": false\r\n})
+//	@Success		200		{object}	endpoint.OrderResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/order/{id}/adjustments [put]
+func decodeUpdateOrderAdjustmentsRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.UpdateOrderAdjustmentsRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// ListOrderPayments godoc
+//
+//	@Summary		List the payment attempts of an order
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Every payment attempt of the order, oldest first, with the timeline of each one
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	endpoint.PaymentAttemptList
+//	@Failure		400	{string}	string	"error"
+//	@Failure		404	{string}	string	"error"
+//	@Failure		500	{string}	string	"error"
+//	@Router			/order/{id}/payments [get]
+func decodeListOrderPaymentsRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.ListOrderPaymentsRequest{ID: id}, nil
+}
+
+// RetryPayment godoc
+//
+//	@Summary		Retry a refused payment
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Creates a new payment attempt for an order whose last payment was refused or failed
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	endpoint.OrderResponse
+//	@Failure		400	{string}	string	"Bad Request"
+//	@Failure		404	{string}	string	"Not Found"
+//	@Failure		409	{string}	string	"Conflict"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/order/{id}/payments [post]
+func decodeRetryPaymentRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.RetryPaymentRequest{ID: id}, nil
+}
+
+// RefundOrder godoc
+//
+//	@Summary		Refund a paid order
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Requests the refund of the given items of a paid order, or of everything not refunded yet when no items are sent. The refund completes when msvc-payments confirms it.
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Order ID"
+//	@Param			request	body		string	true	"Refund data"	SchemaExample({\r\n "items": [{"product_id": "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", "quantity": 1}],\r\n "reason": "produto em falta"\r\n})
+//	@Success		200		{object}	endpoint.RefundResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/order/{id}/refunds [post]
+func decodeRefundOrderRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.RefundOrderRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// ListOrderRefunds godoc
+//
+//	@Summary	List the refunds of an order
+//	@Tags		Orders
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Order ID"
+//	@Success	200	{object}	endpoint.RefundList
+//	@Failure	400	{string}	string	"error"
+//	@Failure	404	{string}	string	"error"
+//	@Failure	500	{string}	string	"error"
+//	@Router		/order/{id}/refunds [get]
+func decodeListOrderRefundsRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.ListOrderRefundsRequest{ID: id}, nil
+}
+
 // DeleteOrder godoc
 //
 //	@Summary	Delete an order
@@ -133,6 +401,7 @@ func decodeDeleteOrder(_ context.Context, r *http.Request) (request any, err err
 //	@Success	200	{string}	string	"ok"
 //	@Failure	400	{string}	string	"error"
 //	@Failure	404	{string}	string	"error"
+//	@Failure	409	{string}	string	"error"
 //	@Failure	500	{string}	string	"error"
 //	@Router		/order/items [put]
 func decodeAlterOrderItems(_ context.Context, r *http.Request) (request any, err error) {
@@ -155,7 +424,7 @@ func decodeAlterOrderItems(_ context.Context, r *http.Request) (request any, err
 //	@Accept		json
 //	@Produce	json
 //	@Param		user_id	header		string	false	"User ID"				default(123e4567-e89b-12d3-a456-426614174000)
-//	@Param		request	body		string	true	"Order request data"	SchemaExample({\r\n "products_ids": ["b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"]\r\n})
+//	@Param		request	body		string	true	"Order request data"	SchemaExample({\r\n "products_ids": ["b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"],\r\n "customer_name": "Maria"\r\n})
 //	@Success	200		{string}	string	"ok"
 //	@Failure	400		{string}	string	"error"
 //	@Failure	500		{string}	string	"error"
@@ -174,8 +443,9 @@ func decodeCreateOrderRequest(_ context.Context, r *http.Request) (request any,
 	uID := r.Header.Get("user_id")
 
 	return endpoint.CreateOrderRequest{
-		UserID:      uID,
-		ProductsIDs: req.ProductsIDs,
+		UserID:       uID,
+		ProductsIDs:  req.ProductsIDs,
+		CustomerName: req.CustomerName,
 	}, nil
 }
 
@@ -203,6 +473,93 @@ func decodeGetOrderRequest(_ context.Context, r *http.Request) (request any, err
 	return endpoint.GetOrderRequest{ID: id}, nil
 }
 
+// GetOrderByPickupNumber godoc
+//
+//	@Summary		Get an order by its pickup number
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Pickup numbers restart every day, the store day is used unless a date is informed
+//	@Accept			json
+//	@Produce		json
+//	@Param			number	path		int		true	"Pickup number"
+//	@Param			date	query		string	false	"Store day (YYYY-MM-DD)"
+//	@Success		200		{string}	string	"ok"
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"error"
+//	@Failure		500		{string}	string	"error"
+//	@Router			/order/number/{number} [get]
+func decodeGetOrderByPickupNumberRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	number, ok := vars["number"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	numberInt, err := strconv.Atoi(number)
+	if err != nil || numberInt == 0 {
+		return nil, ErrBadRequest
+	}
+
+	return endpoint.GetOrderByPickupNumberRequest{
+		Number: numberInt,
+		Date:   r.URL.Query().Get("date"),
+	}, nil
+}
+
+// SearchOrders godoc
+//
+//	@Summary		Search orders
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Back-office search, every filter given must match. Dates take a timestamp or a store day, a day in "to" includes all of it. Deleted orders are never found.
+//	@Produce		json
+//	@Param			status		query		[]string	false	"Order status, repeat or separate by commas for any of them"	collectionFormat(multi)
+//	@Param			from		query		string		false	"Created at or after, RFC3339 or YYYY-MM-DD"
+//	@Param			to			query		string		false	"Created before, RFC3339 or YYYY-MM-DD"
+//	@Param			user_id		query		string		false	"Customer user ID"
+//	@Param			customer	query		string		false	"Part of the customer name"
+//	@Param			payment_id	query		string		false	"Payment ID, any part of a split bill"
+//	@Param			product_id	query		string		false	"Product in the order"
+//	@Param			min_price	query		string		false	"Minimum price"
+//	@Param			max_price	query		string		false	"Maximum price"
+//	@Param			limit		query		int			false	"Limit, at most 100"	default(20)
+//	@Param			cursor		query		string		false	"next_cursor or prev_cursor of a previous page"
+//	@Success		200			{object}	endpoint.OrderList
+//	@Failure		400			{string}	string	"error"
+//	@Failure		500			{string}	string	"error"
+//	@Router			/order [get]
+func decodeSearchOrdersRequest(_ context.Context, r *http.Request) (request any, err error) {
+	query := r.URL.Query()
+
+	limit, cursor, err := decodePageQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	req := endpoint.SearchOrdersRequest{
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+		UserID:    query.Get("user_id"),
+		Customer:  query.Get("customer"),
+		PaymentID: query.Get("payment_id"),
+		ProductID: query.Get("product_id"),
+		MinPrice:  query.Get("min_price"),
+		MaxPrice:  query.Get("max_price"),
+		Limit:     limit,
+		Cursor:    cursor,
+	}
+	for _, v := range query["status"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				req.Statuses = append(req.Statuses, s)
+			}
+		}
+	}
+
+	return req, nil
+}
+
 // ListOrders godoc
 //
 //	@Summary	List all orders
@@ -210,33 +567,20 @@ func decodeGetOrderRequest(_ context.Context, r *http.Request) (request any, err
 //	@Security	ApiKeyAuth
 //	@Accept		json
 //	@Produce	json
-//	@Param		limit	query		int		true	"Limit"		default(10)
-//	@Param		offset	query		int		true	"Offset"	default(0)
+//	@Param		limit	query		int		false	"Limit, at most 100"	default(20)
+//	@Param		cursor	query		string	false	"next_cursor or prev_cursor of a previous page"
 //	@Success	200		{string}	string	"ok"
 //	@Failure	400		{string}	string	"error"
 //	@Failure	500		{string}	string	"error"
 //	@Router		/order/all [get]
 func decodeListOrdersRequest(_ context.Context, r *http.Request) (request any, err error) {
-	query := r.URL.Query()
-	limit := query.Get("limit")
-
-	limitInt, err := strconv.ParseInt(limit, 10, 64)
-	if err != nil {
-		return nil, err
-	}
-	if limitInt == 0 {
-		return nil, ErrBadRequest
-	}
-
-	offset := query.Get("offset")
-
-	offsetInt, err := strconv.ParseInt(offset, 10, 64)
+	limit, cursor, err := decodePageQuery(r.URL.Query())
 	if err != nil {
 		return nil, err
 	}
 
 	return endpoint.ListOrderRequest{
-		Limit:  int(limitInt),
-		Offset: int(offsetInt),
+		Limit:  limit,
+		Cursor: cursor,
 	}, nil
 }
@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+// panelMaxAge is short enough for a TV panel and long enough to absorb polling from every screen in the store
+const panelMaxAge = "public, max-age=5"
+
+var panelTemplate = template.Must(template.New("panel").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>Painel de Retirada</title>
+<style>
+body { font-family: sans-serif; margin: 0; display: flex; background: #111; color: #fff; }
+section { flex: 1; padding: 2rem; }
+h1 { font-size: 3rem; margin-top: 0; }
+ul { list-style: none; padding: 0; }
+li { font-size: 2.5rem; margin: .5rem 0; }
+.ready { background: #1b5e20; }
+span { font-size: 1.5rem; opacity: .8; margin-left: 1rem; }
+</style>
+</head>
+<body>
+<section>
+<h1>Em Preparação</h1>
+<ul>{{range .Preparing}}<li>{{.PickupNumber}}{{if .CustomerName}}<span>{{.CustomerName}}</span>{{end}}</li>{{end}}</ul>
+</section>
+<section class="ready">
+<h1>Pronto</h1>
+<ul>{{range .Ready}}<li>{{.PickupNumber}}{{if .CustomerName}}<span>{{.CustomerName}}</span>{{end}}</li>{{end}}</ul>
+</section>
+</body>
+</html>
+`))
+
+type panelHandler struct {
+	svc    service.OrdersService
+	logger kitlog.Logger
+}
+
+func NewPickupPanelRouter(svc service.OrdersService, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	h := &panelHandler{svc: svc, logger: logger}
+
+	r.Methods(http.MethodGet).Path("/panel").HandlerFunc(h.json)
+	r.Methods(http.MethodGet).Path("/panel/html").HandlerFunc(h.html)
+
+	return r
+}
+
+// PickupPanel godoc
+//
+//	@Summary		Pickup panel
+//	@Tags			Panel
+//	@Description	Public list of the pickup numbers in preparation and ready, without the order contents
+//	@Produce		json
+//	@Success		200	{object}	endpoint.PickupPanelResponse
+//	@Success		304	{string}	string	"Not Modified"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/panel [get]
+func (h *panelHandler) json(w http.ResponseWriter, r *http.Request) {
+	panel, err := h.svc.GetPickupPanel(r.Context())
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	out := endpoint.PickupPanelResponseFromModel(panel)
+	// generated_at changes on every call, keep it out of the ETag so unchanged panels still hit the cache
+	body, err := json.Marshal(struct {
+		Preparing []endpoint.PickupPanelEntryResponse `json:"preparing"`
+		Ready     []endpoint.PickupPanelEntryResponse `json:"ready"`
+	}{out.Preparing, out.Ready})
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+	if notModified(w, r, body, panelMaxAge) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	json.NewEncoder(w).Encode(out)
+}
+
+// PickupPanelHTML godoc
+//
+//	@Summary		Pickup panel page
+//	@Tags			Panel
+//	@Description	Minimal page for the store TV showing the pickup numbers in preparation and ready
+//	@Produce		html
+//	@Success		200	{string}	string	"ok"
+//	@Success		304	{string}	string	"Not Modified"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/panel/html [get]
+func (h *panelHandler) html(w http.ResponseWriter, r *http.Request) {
+	panel, err := h.svc.GetPickupPanel(r.Context())
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	var page bytes.Buffer
+	if err = panelTemplate.Execute(&page, endpoint.PickupPanelResponseFromModel(panel)); err != nil {
+		h.logger.Log("message", "failed rendering pickup panel", "error", err)
+		encodeError(r.Context(), err, w)
+		return
+	}
+	if notModified(w, r, page.Bytes(), panelMaxAge) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(page.Bytes())
+}
+
+// notModified sets the caching headers and answers 304 when the client already has this content
+func notModified(w http.ResponseWriter, r *http.Request, content []byte, cacheControl string) bool {
+	sum := sha1.Sum(content)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
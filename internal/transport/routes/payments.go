@@ -3,13 +3,22 @@ package routes
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
 	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
 	kittransport "github.com/go-kit/kit/transport"
 	httptransport "github.com/go-kit/kit/transport/http"
 	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRCodeSize = 256
+	minQRCodeSize     = 128
+	maxQRCodeSize     = 1024
 )
 
 func NewPaymentsRouter(svc service.PaymentsService, r *mux.Router, logger kitlog.Logger) *mux.Router {
@@ -26,9 +35,65 @@ func NewPaymentsRouter(svc service.PaymentsService, r *mux.Router, logger kitlog
 		options...,
 	))
 
+	r.Methods(http.MethodGet).Path("/payment/{id}/pix.png").HandlerFunc(pixQRCodeHandler(svc, logger))
+
 	return r
 }
 
+// PixQRCode godoc
+//
+//	@Summary		PIX QR code of a payment
+//	@Tags			Payments
+//	@Security		ApiKeyAuth
+//	@Description	PNG QR code with the PIX "copia e cola" of a payment still waiting to be paid
+//	@Produce		png
+//	@Param			id		path		string	true	"Payment ID"
+//	@Param			size	query		int		false	"Image side in pixels, from 128 to 1024"	default(256)
+//	@Success		200		{file}		binary
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		409		{string}	string	"Conflict"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/payment/{id}/pix.png [get]
+func pixQRCodeHandler(svc service.PaymentsService, logger kitlog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		paymentID, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			encodeError(r.Context(), ErrBadRequest, w)
+			return
+		}
+
+		size := defaultQRCodeSize
+		if raw := r.URL.Query().Get("size"); raw != "" {
+			if size, err = strconv.Atoi(raw); err != nil || size < minQRCodeSize || size > maxQRCodeSize {
+				encodeError(r.Context(), ErrBadRequest, w)
+				return
+			}
+		}
+
+		payment, err := svc.GetPayment(r.Context(), paymentID)
+		if err != nil {
+			encodeError(r.Context(), err, w)
+			return
+		}
+		payload, err := svc.PixPayload(payment)
+		if err != nil {
+			encodeError(r.Context(), err, w)
+			return
+		}
+
+		png, err := qrcode.Encode(payload, qrcode.Medium, size)
+		if err != nil {
+			logger.Log("message", "failed rendering pix qr code", "payment_id", paymentID.String(), "error", err)
+			encodeError(r.Context(), err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(png)
+	}
+}
+
 // GetPayment
 //
 //	@Summary		Get a payment by ID
@@ -22,6 +22,11 @@ func NewProductsRouter(svc service.ProductsService, r *mux.Router, logger kitlog
 		httptransport.ServerErrorEncoder(encodeError),
 	}
 
+	r.Methods(http.MethodGet).Path("/product/search").Handler(httptransport.NewServer(prodEndpoints.SearchProducts,
+		decodeSearchProductsRequest,
+		encodeResponse,
+		options...,
+	))
 	r.Methods(http.MethodGet).Path("/product/{id}").Handler(httptransport.NewServer(prodEndpoints.GetProductEndpoint,
 		decodeGetProductsRequest,
 		encodeResponse,
@@ -36,6 +41,36 @@ func NewProductsRouter(svc service.ProductsService, r *mux.Router, logger kitlog
 		decodeUpdateProductsRequest,
 		encodeResponse,
 		options...))
+	r.Methods(http.MethodPost).Path("/product/{id}/restore").Handler(httptransport.NewServer(prodEndpoints.RestoreProductEndpoint,
+		decodeRestoreProductRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods(http.MethodPut).Path("/product/{id}/availability").Handler(httptransport.NewServer(prodEndpoints.SetAvailability,
+		decodeSetProductAvailabilityRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods(http.MethodPut).Path("/product/{id}/window").Handler(httptransport.NewServer(prodEndpoints.SetWindow,
+		decodeSetProductWindowRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods(http.MethodPost).Path("/product/{id}/prices").Handler(httptransport.NewServer(prodEndpoints.SchedulePrice,
+		decodeSchedulePriceRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods(http.MethodGet).Path("/product/{id}/prices").Handler(httptransport.NewServer(prodEndpoints.ListPriceHistory,
+		decodeListPriceHistoryRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods(http.MethodDelete).Path("/product/{id}/prices/{price_id}").Handler(httptransport.NewServer(prodEndpoints.CancelScheduledPrice,
+		decodeCancelScheduledPriceRequest,
+		encodeResponse,
+		options...,
+	))
 	r.Methods(http.MethodDelete).Path("/product/{id}").Handler(httptransport.NewServer(prodEndpoints.DeleteProductEndpoint,
 		decodeDeleteProductsRequest,
 		encodeResponse,
@@ -45,7 +80,6 @@ func NewProductsRouter(svc service.ProductsService, r *mux.Router, logger kitlog
 	r.Methods(
 		http.MethodGet).
 		Path("/product/category/{id}").
-		Queries("limit", "{limit:[0-9]+}", "offset", "{offset:[0-9]+}").
 		Handler(
 			httptransport.NewServer(
 				prodEndpoints.ListProductsByCategory,
@@ -66,9 +100,10 @@ func NewProductsRouter(svc service.ProductsService, r *mux.Router, logger kitlog
 //	@Description	List products
 //	@ID				list-products
 //	@Produce		json
-//	@Param			id		path		string	false	"Category ID"
-//	@Param			limit	query		int		true	"Limit"		default(10)
-//	@Param			offset	query		int		true	"Offset"	default(0)
+//	@Param			id			path		string	false	"Category ID"
+//	@Param			limit		query		int		false	"Limit, at most 100"	default(20)
+//	@Param			cursor		query		string	false	"next_cursor or prev_cursor of a previous page"
+//	@Param			descendants	query		bool	false	"Include the products of subcategories"	default(false)
 //	@Success		200		{string}	string	"ok"
 //	@Failure		400		{string}	string	"error"
 //	@Failure		404		{string}	string	"Not Found"
@@ -83,30 +118,72 @@ func decodeListProductsRequest(_ context.Context, r *http.Request) (request any,
 	}
 
 	query := r.URL.Query()
-	limit := query.Get("limit")
-
-	limitInt, err := strconv.ParseInt(limit, 10, 64)
+	limit, cursor, err := decodePageQuery(query)
 	if err != nil {
 		return nil, err
 	}
-	if limitInt == 0 {
-		return nil, ErrBadRequest
-	}
-
-	offset := query.Get("offset")
 
-	offsetInt, err := strconv.ParseInt(offset, 10, 64)
-	if err != nil {
-		return nil, err
+	descendants := false
+	if d := query.Get("descendants"); d != "" {
+		if descendants, err = strconv.ParseBool(d); err != nil {
+			return nil, ErrBadRequest
+		}
 	}
 
 	return endpoint.ListProductsByCategoryRequest{
-		ID:     id,
-		Limit:  limitInt,
-		Offset: offsetInt,
+		ID:          id,
+		Descendants: descendants,
+		Limit:       limit,
+		Cursor:      cursor,
 	}, nil
 }
 
+// SearchProducts
+//
+//	@Summary		Search products
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Full-text search over name and description in Portuguese, accents are ignored. Subcategories are searched with their category, archived products are never found.
+//	@ID				search-products
+//	@Produce		json
+//	@Param			q				query		string	false	"Text to search"
+//	@Param			category_id		query		string	false	"Category ID"
+//	@Param			min_price		query		string	false	"Minimum price"
+//	@Param			max_price		query		string	false	"Maximum price"
+//	@Param			availability	query		string	false	"available, unavailable or all"							default(available)
+//	@Param			sort			query		string	false	"relevance, name, price_asc, price_desc or newest"	default(relevance)
+//	@Param			limit			query		int		false	"Limit"													default(10)
+//	@Param			offset			query		int		false	"Offset"												default(0)
+//	@Success		200				{object}	endpoint.ProductList
+//	@Failure		400				{string}	string	"error"
+//	@Failure		500				{string}	string	"Inernal Server Error"
+//	@Router			/product/search [get]
+func decodeSearchProductsRequest(_ context.Context, r *http.Request) (request any, err error) {
+	query := r.URL.Query()
+
+	req := endpoint.SearchProductsRequest{
+		Query:        query.Get("q"),
+		CategoryID:   query.Get("category_id"),
+		MinPrice:     query.Get("min_price"),
+		MaxPrice:     query.Get("max_price"),
+		Availability: query.Get("availability"),
+		Sort:         query.Get("sort"),
+		Limit:        10,
+	}
+	if l := query.Get("limit"); l != "" {
+		if req.Limit, err = strconv.Atoi(l); err != nil {
+			return nil, ErrBadRequest
+		}
+	}
+	if o := query.Get("offset"); o != "" {
+		if req.Offset, err = strconv.Atoi(o); err != nil {
+			return nil, ErrBadRequest
+		}
+	}
+
+	return req, nil
+}
+
 // GetProduct
 //
 //	@Summary		Get a product by ID
@@ -196,7 +273,7 @@ func decodeUpdateProductsRequest(_ context.Context, r *http.Request) (request an
 //	@Summary		Delete a product
 //	@Tags			Products
 //	@Security		ApiKeyAuth
-//	@Description	Delete a product
+//	@Description	Archives a product. It leaves listings, the menu and new orders, but is still found by ID for past orders and can be restored.
 //	@ID				delete-product
 //	@Accept			json
 //	@Produce		json
@@ -216,3 +293,170 @@ func decodeDeleteProductsRequest(_ context.Context, r *http.Request) (request an
 
 	return endpoint.DeleteProductRequest{ID: id}, nil
 }
+
+// RestoreProduct
+//
+//	@Summary		Restore an archived product
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Brings an archived product back to the catalog, its category must not be archived
+//	@ID				restore-product
+//	@Produce		json
+//	@Param			id	path		string	true	"Product ID"
+//	@Success		200	{object}	endpoint.ProductResponse
+//	@Failure		400	{string}	string	"error"
+//	@Failure		404	{string}	string	"Not Found"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/restore [post]
+func decodeRestoreProductRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.GetProductRequest{ID: id}, nil
+}
+
+// SetProductAvailability
+//
+//	@Summary		Switch product availability
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Takes a product off the menu when the kitchen runs out of it, optionally until a given time, or puts it back. Unavailable products are hidden from listings and the menu and refused in new orders.
+//	@ID				set-product-availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Product ID"
+//	@Param			request	body		string	true	"Availability data"	SchemaExample({\r\n  "available": false,\r\n  "until": "2024-03-01T18:00:00-03:00"\r\n})
+//	@Success		200		{object}	endpoint.ProductResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/availability [put]
+func decodeSetProductAvailabilityRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.SetProductAvailabilityRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// SchedulePrice
+//
+//	@Summary		Schedule a product price
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Sets the price a product takes at a future time. A background job applies it once due, when several come due together only the latest is charged.
+//	@ID				schedule-product-price
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Product ID"
+//	@Param			request	body		string	true	"Scheduled price data"	SchemaExample({\r\n  "price": "R$ 12,50",\r\n  "effective_at": "2024-03-01T00:00:00-03:00"\r\n})
+//	@Success		200		{object}	endpoint.ProductPriceResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/prices [post]
+func decodeSchedulePriceRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	var req endpoint.SchedulePriceRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// ListPriceHistory
+//
+//	@Summary		Product price history
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Lists every price of a product, latest effective first, scheduled and canceled ones included, to audit what customers were charged.
+//	@ID				list-product-prices
+//	@Produce		json
+//	@Param			id	path		string	true	"Product ID"
+//	@Success		200	{object}	endpoint.PriceHistoryResponse
+//	@Failure		400	{string}	string	"error"
+//	@Failure		404	{string}	string	"Not Found"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/prices [get]
+func decodeListPriceHistoryRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.GetProductRequest{ID: id}, nil
+}
+
+// CancelScheduledPrice
+//
+//	@Summary		Cancel a scheduled price
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Cancels a price that did not take effect yet, it stays in the history as canceled
+//	@ID				cancel-product-price
+//	@Produce		json
+//	@Param			id			path		string	true	"Product ID"
+//	@Param			price_id	path		string	true	"Scheduled price ID"
+//	@Success		200			{object}	endpoint.ProductPriceResponse
+//	@Failure		400			{string}	string	"error"
+//	@Failure		404			{string}	string	"Not Found"
+//	@Failure		409			{string}	string	"Already applied or canceled"
+//	@Failure		500			{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/prices/{price_id} [delete]
+func decodeCancelScheduledPriceRequest(_ context.Context, r *http.Request) (request any, err error) {
+	vars := mux.Vars(r)
+
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	priceID, ok := vars["price_id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.CancelScheduledPriceRequest{ID: id, PriceID: priceID}, nil
+}
+
+// SetProductWindow
+//
+//	@Summary		Set the hours a product is sold
+//	@Tags			Products
+//	@Security		ApiKeyAuth
+//	@Description	Limits the hours of the day the product can be ordered, in the store timezone, like breakfast from 06:00 to 10:30. A window ending before it starts goes past midnight. Empty times make the product follow its category again.
+//	@ID				set-product-window
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Product ID"
+//	@Param			request	body		string	true	"Window data"	SchemaExample({\r\n  "available_from": "06:00",\r\n  "available_to": "10:30"\r\n})
+//	@Success		200		{object}	endpoint.ProductResponse
+//	@Failure		400		{string}	string	"error"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/product/{id}/window [put]
+func decodeSetProductWindowRequest(ctx context.Context, r *http.Request) (request any, err error) {
+	return decodeSetWindowRequest(ctx, r)
+}
@@ -0,0 +1,150 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	kittransport "github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+func NewWebhookSubscriptionsRouter(svc service.WebhooksService, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	webhooksEndpoints := endpoint.MakeWebhooksEndpoints(svc)
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(kittransport.NewLogErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	r.Methods(http.MethodPost).Path("/webhooks").Handler(httptransport.NewServer(
+		webhooksEndpoints.CreateSubscriptionEndpoint,
+		decodeCreateWebhookSubscriptionRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/webhooks").Handler(httptransport.NewServer(
+		webhooksEndpoints.ListSubscriptionsEndpoint,
+		decodeListWebhookSubscriptionsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/webhooks/{id}/deliveries").Handler(httptransport.NewServer(
+		webhooksEndpoints.ListDeliveriesEndpoint,
+		decodeWebhookDeliveriesRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodGet).Path("/webhooks/{id}").Handler(httptransport.NewServer(
+		webhooksEndpoints.GetSubscriptionEndpoint,
+		decodeGetWebhookSubscriptionRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods(http.MethodDelete).Path("/webhooks/{id}").Handler(httptransport.NewServer(
+		webhooksEndpoints.DeleteSubscriptionEndpoint,
+		decodeDeleteWebhookSubscriptionRequest,
+		encodeResponse,
+		options...,
+	))
+
+	return r
+}
+
+// CreateWebhookSubscription godoc
+//
+//	@Summary		Subscribe to order events
+//	@Tags			Webhooks
+//	@Security		ApiKeyAuth
+//	@Description	Registers a URL notified on order status changes. Deliveries carry X-Signature, "sha256=" followed by the hex HMAC-SHA256 of "<X-Timestamp>.<body>", and are retried with exponential backoff.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		string	true	"Subscription data"	SchemaExample({\r\n "url": "https://parceiro.example.com/webhooks/pedidos",\r\n "statuses": ["Pronto", "Finalizado"]\r\n})
+//	@Success		200		{object}	endpoint.WebhookSubscriptionResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		500		{string}	string	"Inernal Server Error"
+//	@Router			/webhooks [post]
+func decodeCreateWebhookSubscriptionRequest(_ context.Context, r *http.Request) (request any, err error) {
+	var req endpoint.CreateWebhookSubscriptionRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, ErrBadRequest
+	}
+
+	return req, nil
+}
+
+// ListWebhookSubscriptions godoc
+//
+//	@Summary	List webhook subscriptions
+//	@Tags		Webhooks
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	endpoint.WebhookSubscriptionList
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/webhooks [get]
+func decodeListWebhookSubscriptionsRequest(_ context.Context, _ *http.Request) (request any, err error) {
+	return nil, nil
+}
+
+// GetWebhookSubscription godoc
+//
+//	@Summary	Get a webhook subscription
+//	@Tags		Webhooks
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Subscription ID"
+//	@Success	200	{object}	endpoint.WebhookSubscriptionResponse
+//	@Failure	404	{string}	string	"Not Found"
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/webhooks/{id} [get]
+func decodeGetWebhookSubscriptionRequest(_ context.Context, r *http.Request) (request any, err error) {
+	return decodeWebhookSubscriptionID(r)
+}
+
+// DeleteWebhookSubscription godoc
+//
+//	@Summary	Delete a webhook subscription
+//	@Tags		Webhooks
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Subscription ID"
+//	@Success	200	{string}	string	"ok"
+//	@Failure	404	{string}	string	"Not Found"
+//	@Failure	500	{string}	string	"Inernal Server Error"
+//	@Router		/webhooks/{id} [delete]
+func decodeDeleteWebhookSubscriptionRequest(_ context.Context, r *http.Request) (request any, err error) {
+	return decodeWebhookSubscriptionID(r)
+}
+
+// ListWebhookDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Tags			Webhooks
+//	@Security		ApiKeyAuth
+//	@Description	Latest delivery attempts of a subscription, newest first
+//	@Produce		json
+//	@Param			id	path		string	true	"Subscription ID"
+//	@Success		200	{object}	endpoint.WebhookDeliveryList
+//	@Failure		404	{string}	string	"Not Found"
+//	@Failure		500	{string}	string	"Inernal Server Error"
+//	@Router			/webhooks/{id}/deliveries [get]
+func decodeWebhookDeliveriesRequest(_ context.Context, r *http.Request) (request any, err error) {
+	return decodeWebhookSubscriptionID(r)
+}
+
+func decodeWebhookSubscriptionID(r *http.Request) (any, error) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+
+	return endpoint.GetWebhookSubscriptionRequest{ID: id}, nil
+}
@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/datastore"
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/messages"
+	kitlog "github.com/go-kit/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	webhookMaxBody = 64 << 10
+	// webhookTolerance bounds clock skew and how long a captured delivery could be replayed
+	webhookTolerance = 5 * time.Minute
+	webhookReplayKey = "webhook:payment:"
+	webhookApplied   = "applied"
+)
+
+type paymentWebhookHandler struct {
+	svc    service.OrdersService
+	cache  datastore.RedisStore
+	claims helpers.KeyClaimer
+	secret []byte
+	logger kitlog.Logger
+}
+
+// NewPaymentWebhookRouter accepts payment notifications over HTTP for acquirers that can't publish on Redis.
+// Nothing is registered without a secret, unsigned notifications are never accepted.
+func NewPaymentWebhookRouter(svc service.OrdersService, cache datastore.RedisStore, claims helpers.KeyClaimer, secret string, r *mux.Router, logger kitlog.Logger) *mux.Router {
+	if secret == "" {
+		logger.Log("message", "payment webhook disabled, PAYMENT_WEBHOOK_SECRET is not set")
+		return r
+	}
+
+	h := &paymentWebhookHandler{svc: svc, cache: cache, claims: claims, secret: []byte(secret), logger: logger}
+	r.Methods(http.MethodPost).Path("/webhook/payment").HandlerFunc(h.payment)
+
+	return r
+}
+
+// PaymentWebhook godoc
+//
+//	@Summary		Payment status webhook
+//	@Tags			Webhooks
+//	@Description	Receives the same payment status notification published on Redis. X-Signature is "sha256=" followed by the hex HMAC-SHA256 of "<X-Timestamp>.<body>". Redeliveries and statuses the order already went past are acknowledged with 204, a delivery still being processed gets 409.
+//	@Accept			json
+//	@Param			X-Signature	header		string	true	"HMAC signature"
+//	@Param			X-Timestamp	header		int		true	"Unix seconds of the delivery"
+//	@Param			request		body		string	true	"Payment status"	SchemaExample({\r\n "id": "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12",\r\n "order_id": "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",\r\n "status": "paid",\r\n "updated_at": "2024-03-01T12:00:00Z"\r\n})
+//	@Success		204			{string}	string	"No Content"
+//	@Failure		400			{string}	string	"Bad Request"
+//	@Failure		401			{string}	string	"Unauthorized"
+//	@Failure		409			{string}	string	"Conflict"
+//	@Failure		500			{string}	string	"Inernal Server Error"
+//	@Router			/webhook/payment [post]
+func (h *paymentWebhookHandler) payment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBody))
+	if err != nil {
+		encodeError(ctx, helpers.ErrInvalidInput, w)
+		return
+	}
+
+	timestamp, err := helpers.ParseSignatureTimestamp(r.Header.Get(helpers.TimestampHeader))
+	if err != nil || time.Since(timestamp).Abs() > webhookTolerance {
+		encodeError(ctx, helpers.ErrUnauthorized, w)
+		return
+	}
+	signature := r.Header.Get(helpers.SignatureHeader)
+	if !helpers.VerifySignature(h.secret, timestamp, body, signature) {
+		h.logger.Log("message", "payment webhook with invalid signature", "remote_addr", r.RemoteAddr)
+		encodeError(ctx, helpers.ErrUnauthorized, w)
+		return
+	}
+
+	var msg messages.PaymentStatusChangedMessage
+	if err = json.Unmarshal(body, &msg); err != nil {
+		encodeError(ctx, helpers.ErrInvalidInput, w)
+		return
+	}
+
+	// a valid signature only lives for the tolerance window, claiming it that long blocks replays.
+	// Redeliveries of a notification already applied are acknowledged, concurrent ones retried later.
+	replayKey := webhookReplayKey + signature
+	claimed, err := h.claims.Claim(ctx, replayKey, msg.ID, 2*webhookTolerance)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	if !claimed {
+		state, err := h.cache.Get(ctx, replayKey)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+		if state != webhookApplied {
+			encodeError(ctx, helpers.ErrWebhookReplayed, w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = h.svc.ProcessPaymentStatusChange(ctx, msg)
+	if err == helpers.ErrInvalidStatusTransition {
+		// the order already went past this status, retrying would never change that
+		h.logger.Log("message", "payment webhook for outdated status", "payment_id", msg.ID, "status", msg.Status)
+		err = nil
+	}
+	if err != nil {
+		// let the acquirer retry the same delivery
+		h.cache.Delete(ctx, replayKey)
+		encodeError(ctx, err, w)
+		return
+	}
+	h.cache.Set(ctx, replayKey, webhookApplied, 2*webhookTolerance)
+
+	w.WriteHeader(http.StatusNoContent)
+}
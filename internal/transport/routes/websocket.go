@@ -0,0 +1,409 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/endpoint"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service"
+	"github.com/SOAT1StackGoLang/msvc-orders/internal/service/models"
+	"github.com/SOAT1StackGoLang/msvc-orders/pkg/helpers"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = (wsPongWait * 9) / 10
+	wsMaxMessageSize = 4096
+	wsSendBuffer     = 64
+
+	wsTopicKitchen     = "kitchen"
+	wsTopicPickup      = "pickup"
+	wsTopicOrderPrefix = "order:"
+)
+
+// DeviceRole is the kind of device allowed to open a websocket session
+type DeviceRole string
+
+const (
+	DEVICE_ROLE_KIOSK   DeviceRole = "kiosk"
+	DEVICE_ROLE_KITCHEN DeviceRole = "kitchen"
+	DEVICE_ROLE_PICKUP  DeviceRole = "pickup"
+)
+
+var (
+	ErrUnknownTopic   = errors.New("topico desconhecido")
+	ErrUnknownCommand = errors.New("comando desconhecido")
+	ErrForbidden      = errors.New("dispositivo sem permissao para esta acao")
+)
+
+// pickupStatuses are the transitions shown at the pickup panel
+var pickupStatuses = []models.OrderStatus{
+	models.ORDER_STATUS_PREPARING,
+	models.ORDER_STATUS_DONE,
+	models.ORDER_STATUS_FINISHED,
+}
+
+// wsCommands maps the actions devices may send to the order status they request and the roles allowed to send them
+var wsCommands = map[string]struct {
+	status models.OrderStatus
+	roles  []DeviceRole
+}{
+	"start_preparing": {models.ORDER_STATUS_PREPARING, []DeviceRole{DEVICE_ROLE_KITCHEN}},
+	"ready":           {models.ORDER_STATUS_DONE, []DeviceRole{DEVICE_ROLE_KITCHEN}},
+	"finish":          {models.ORDER_STATUS_FINISHED, []DeviceRole{DEVICE_ROLE_KITCHEN, DEVICE_ROLE_PICKUP}},
+}
+
+type (
+	wsClientMessage struct {
+		Type      string `json:"type"`
+		Topic     string `json:"topic,omitempty"`
+		Action    string `json:"action,omitempty"`
+		OrderID   string `json:"order_id,omitempty"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+
+	wsServerMessage struct {
+		Type      string                       `json:"type"`
+		Topic     string                       `json:"topic,omitempty"`
+		RequestID string                       `json:"request_id,omitempty"`
+		Event     *endpoint.OrderEventResponse `json:"event,omitempty"`
+		Order     *endpoint.OrderResponse      `json:"order,omitempty"`
+		Error     string                       `json:"error,omitempty"`
+	}
+)
+
+// ParseDeviceTokens reads the "token:role,token:role" format used by WS_DEVICE_TOKENS
+func ParseDeviceTokens(raw string) map[string]DeviceRole {
+	out := make(map[string]DeviceRole)
+	for _, pair := range strings.Split(raw, ",") {
+		token, role, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || token == "" {
+			continue
+		}
+		switch r := DeviceRole(strings.ToLower(role)); r {
+		case DEVICE_ROLE_KIOSK, DEVICE_ROLE_KITCHEN, DEVICE_ROLE_PICKUP:
+			out[token] = r
+		}
+	}
+	return out
+}
+
+type wsGateway struct {
+	svc      service.OrdersService
+	hub      service.OrderEventsHub
+	tokens   map[string]DeviceRole
+	upgrader websocket.Upgrader
+	logger   kitlog.Logger
+}
+
+func NewWebsocketRouter(
+	svc service.OrdersService,
+	hub service.OrderEventsHub,
+	tokens map[string]DeviceRole,
+	r *mux.Router,
+	logger kitlog.Logger,
+) *mux.Router {
+	if len(tokens) == 0 {
+		logger.Log("message", "no websocket device tokens configured, every websocket session will be refused")
+	}
+
+	g := &wsGateway{
+		svc:    svc,
+		hub:    hub,
+		tokens: tokens,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// devices authenticate with their token, so cross origin kiosks are fine
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		logger: logger,
+	}
+
+	r.Methods(http.MethodGet).Path("/ws").HandlerFunc(g.serve)
+
+	return r
+}
+
+// Websocket godoc
+//
+//	@Summary		Open a websocket session for kiosks and kitchen displays
+//	@Tags			Orders
+//	@Security		ApiKeyAuth
+//	@Description	Authenticated with a device token (Authorization: Bearer <token> or ?token=). Devices subscribe to "order:<id>", "kitchen" or "pickup" and kitchen devices send "start_preparing", "ready" and "finish" commands.
+//	@Param			token	query		string	false	"Device token"
+//	@Success		101		{string}	string	"Switching Protocols"
+//	@Failure		401		{string}	string	"Unauthorized"
+//	@Router			/ws [get]
+func (g *wsGateway) serve(w http.ResponseWriter, r *http.Request) {
+	role, ok := g.authenticate(r)
+	if !ok {
+		encodeError(r.Context(), helpers.ErrUnauthorized, w)
+		return
+	}
+
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		g.logger.Log("message", "failed upgrading websocket connection", "error", err)
+		return
+	}
+
+	s := &wsSession{
+		id:            uuid.New(),
+		role:          role,
+		gateway:       g,
+		conn:          conn,
+		send:          make(chan wsServerMessage, wsSendBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[string]*wsSubscription),
+	}
+	g.logger.Log("message", "websocket session opened", "session_id", s.id.String(), "role", string(role))
+
+	go s.writeLoop()
+	s.readLoop()
+}
+
+func (g *wsGateway) authenticate(r *http.Request) (DeviceRole, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	role, ok := g.tokens[token]
+	return role, ok && token != ""
+}
+
+type wsSession struct {
+	id      uuid.UUID
+	role    DeviceRole
+	gateway *wsGateway
+	conn    *websocket.Conn
+
+	send      chan wsServerMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu            sync.Mutex
+	subscriptions map[string]*wsSubscription
+}
+
+// wsSubscription is one subscription of a session to a topic, compared by pointer so a finished
+// subscription never removes the one that replaced it
+type wsSubscription struct {
+	cancel func()
+}
+
+func (s *wsSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		for topic, sub := range s.subscriptions {
+			sub.cancel()
+			delete(s.subscriptions, topic)
+		}
+		s.mu.Unlock()
+		s.conn.Close()
+		s.gateway.logger.Log("message", "websocket session closed", "session_id", s.id.String())
+	})
+}
+
+// push queues a message for the writer, closing slow sessions instead of blocking the publishers
+func (s *wsSession) push(msg wsServerMessage) {
+	select {
+	case <-s.done:
+	case s.send <- msg:
+	default:
+		s.gateway.logger.Log("message", "closing slow websocket session", "session_id", s.id.String())
+		s.close()
+	}
+}
+
+func (s *wsSession) readLoop() {
+	defer s.close()
+
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, raw, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg wsClientMessage
+		if err = json.Unmarshal(raw, &msg); err != nil {
+			s.push(wsServerMessage{Type: "error", Error: ErrBadRequest.Error()})
+			continue
+		}
+
+		switch msg.Type {
+		case "subscribe":
+			s.subscribe(msg)
+		case "unsubscribe":
+			s.unsubscribe(msg)
+		case "command":
+			s.command(msg)
+		case "ping":
+			s.push(wsServerMessage{Type: "pong", RequestID: msg.RequestID})
+		default:
+			s.push(wsServerMessage{Type: "error", RequestID: msg.RequestID, Error: ErrUnknownCommand.Error()})
+		}
+	}
+}
+
+func (s *wsSession) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		s.close()
+	}()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) topicFilter(topic string) (models.OrderEventFilter, error) {
+	switch {
+	case topic == wsTopicKitchen && s.role == DEVICE_ROLE_KITCHEN:
+		return models.OrderEventFilter{Statuses: kitchenStatuses}, nil
+	case topic == wsTopicPickup && s.role != DEVICE_ROLE_KIOSK:
+		return models.OrderEventFilter{Statuses: pickupStatuses}, nil
+	case topic == wsTopicKitchen || topic == wsTopicPickup:
+		return models.OrderEventFilter{}, ErrForbidden
+	case strings.HasPrefix(topic, wsTopicOrderPrefix):
+		orderID, err := uuid.Parse(strings.TrimPrefix(topic, wsTopicOrderPrefix))
+		if err != nil {
+			return models.OrderEventFilter{}, ErrBadRequest
+		}
+		return models.OrderEventFilter{OrderID: orderID}, nil
+	default:
+		return models.OrderEventFilter{}, ErrUnknownTopic
+	}
+}
+
+func (s *wsSession) subscribe(msg wsClientMessage) {
+	filter, err := s.topicFilter(msg.Topic)
+	if err != nil {
+		s.push(wsServerMessage{Type: "error", Topic: msg.Topic, RequestID: msg.RequestID, Error: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.subscriptions[msg.Topic]; ok {
+		s.mu.Unlock()
+		s.push(wsServerMessage{Type: "subscribed", Topic: msg.Topic, RequestID: msg.RequestID})
+		return
+	}
+	events, cancel, _ := s.gateway.hub.Subscribe(filter, 0)
+	sub := &wsSubscription{cancel: cancel}
+	s.subscriptions[msg.Topic] = sub
+	s.mu.Unlock()
+
+	go s.forward(msg.Topic, sub, events)
+	s.push(wsServerMessage{Type: "subscribed", Topic: msg.Topic, RequestID: msg.RequestID})
+}
+
+func (s *wsSession) unsubscribe(msg wsClientMessage) {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[msg.Topic]
+	delete(s.subscriptions, msg.Topic)
+	s.mu.Unlock()
+
+	if !ok {
+		s.push(wsServerMessage{Type: "error", Topic: msg.Topic, RequestID: msg.RequestID, Error: ErrUnknownTopic.Error()})
+		return
+	}
+	// forward confirms with an "unsubscribed" message once the hub closes the channel
+	sub.cancel()
+}
+
+func (s *wsSession) forward(topic string, sub *wsSubscription, events <-chan models.OrderEvent) {
+	for e := range events {
+		event := endpoint.OrderEventResponseFromModel(e)
+
+		if topic == wsTopicKitchen && e.Status == models.ORDER_STATUS_RECEIVED {
+			order, err := s.gateway.svc.GetOrder(context.Background(), e.OrderID)
+			if err == nil {
+				out := endpoint.OrderResponseFromModel(order)
+				s.push(wsServerMessage{Type: "new_order", Topic: topic, Event: &event, Order: &out})
+				continue
+			}
+			s.gateway.logger.Log("message", "failed loading new order for kitchen push", "order_id", e.OrderID.String(), "error", err)
+		}
+
+		s.push(wsServerMessage{Type: "event", Topic: topic, Event: &event})
+	}
+
+	// the hub closes the channel on unsubscribe or when the session fell behind, tell the device so it can resubscribe.
+	// The topic may already hold a newer subscription made after an unsubscribe, that one is left alone.
+	s.mu.Lock()
+	if s.subscriptions[topic] == sub {
+		delete(s.subscriptions, topic)
+	}
+	s.mu.Unlock()
+	s.push(wsServerMessage{Type: "unsubscribed", Topic: topic})
+}
+
+func (s *wsSession) command(msg wsClientMessage) {
+	cmd, ok := wsCommands[msg.Action]
+	if !ok {
+		s.push(wsServerMessage{Type: "error", RequestID: msg.RequestID, Error: ErrUnknownCommand.Error()})
+		return
+	}
+
+	allowed := false
+	for _, r := range cmd.roles {
+		allowed = allowed || r == s.role
+	}
+	if !allowed {
+		s.push(wsServerMessage{Type: "error", RequestID: msg.RequestID, Error: ErrForbidden.Error()})
+		return
+	}
+
+	orderID, err := uuid.Parse(msg.OrderID)
+	if err != nil {
+		s.push(wsServerMessage{Type: "error", RequestID: msg.RequestID, Error: ErrBadRequest.Error()})
+		return
+	}
+
+	order, err := s.gateway.svc.UpdateOrderStatus(context.Background(), orderID, cmd.status)
+	if err != nil {
+		s.push(wsServerMessage{Type: "error", RequestID: msg.RequestID, Error: err.Error()})
+		return
+	}
+
+	s.gateway.logger.Log(
+		"message", "order status changed through websocket",
+		"session_id", s.id.String(),
+		"order_id", orderID.String(),
+		"status", string(order.Status),
+	)
+	out := endpoint.OrderResponseFromModel(order)
+	s.push(wsServerMessage{Type: "ack", RequestID: msg.RequestID, Order: &out})
+}
@@ -5,4 +5,19 @@ import "errors"
 var ErrUnauthorized = errors.New("user is not authorize to access this resource")
 var ErrInvalidCurrencyFormat = errors.New("invalid currency format. Expected 'R$ X,XX'")
 var ErrBadRequest = errors.New("bad request")
+var ErrNotFound = errors.New("resource not found")
 var ErrInvalidInput = errors.New("invalid input at request")
+var ErrProductUnavailable = errors.New("product is not available at the moment")
+var ErrOutOfStock = errors.New("not enough stock of an ingredient for this order")
+var ErrCategoryCycle = errors.New("category can not be nested under itself or its subcategories")
+var ErrCategoryNotEmpty = errors.New("category still has active products")
+var ErrPriceNotScheduled = errors.New("price is no longer scheduled")
+var ErrStoreClosed = errors.New("store is closed, orders are not taken now")
+var ErrInvalidStatusTransition = errors.New("invalid order status transition")
+var ErrOrderNotEditable = errors.New("order can no longer be changed")
+var ErrOrderNotCancelable = errors.New("order can no longer be canceled")
+var ErrPaymentNotRetryable = errors.New("order has no refused or failed payment to retry")
+var ErrPixUnavailable = errors.New("pix is not available for this payment")
+var ErrPaymentStatusChanged = errors.New("payment status changed meanwhile")
+var ErrRefundNotAllowed = errors.New("payment can not be refunded by this amount")
+var ErrWebhookReplayed = errors.New("webhook delivery is already being processed")
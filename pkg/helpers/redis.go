@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyClaimer takes keys atomically, the RedisStore from msvc-payments only offers Exists and Set which race
+type KeyClaimer interface {
+	// Claim sets key to value for ttl unless it is already set, telling whether this caller got it
+	Claim(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+}
+
+type redisClaimer struct {
+	client *redis.Client
+}
+
+func NewRedisKeyClaimer(addr string, db int) KeyClaimer {
+	return &redisClaimer{client: redis.NewClient(&redis.Options{Addr: addr, DB: db})}
+}
+
+func (c *redisClaimer) Claim(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Timestamp"
+
+	signaturePrefix = "sha256="
+)
+
+// SignPayload signs "<unix timestamp>.<body>" with HMAC-SHA256, the format used by our webhooks in both directions
+func SignPayload(secret []byte, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature produced by SignPayload in constant time
+func VerifySignature(secret []byte, timestamp time.Time, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	expected := SignPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// ParseSignatureTimestamp reads the unix seconds sent along with a signature
+func ParseSignatureTimestamp(raw string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrUnauthorized
+	}
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,11 @@
+package messages
+
+// msvc-payments has no cancellation contract yet, this is the channel and payload it consumes to void a pending charge
+var OrderPaymentCancelRequestChannel = "order_payment_cancel_request_channel"
+
+type PaymentCancelRequestMessage struct {
+	ID        string `json:"id"`
+	OrderID   string `json:"order_id"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,9 @@
+package messages
+
+import productionmsgs "github.com/SOAT1StackGoLang/msvc-production/pkg/messages"
+
+// OrderSentMessage extends the msvc-production message with the number called out at the counter
+type OrderSentMessage struct {
+	productionmsgs.OrderSentMessage
+	PickupNumber int `json:"pickup_number,omitempty"`
+}
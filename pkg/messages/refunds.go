@@ -0,0 +1,32 @@
+package messages
+
+// msvc-payments has no refund contract yet, these are the channels and payloads it consumes and answers on
+var (
+	OrderRefundRequestChannel   = "order_refund_request_channel"
+	RefundStatusResponseChannel = "refund_status_channel"
+)
+
+type RefundRequestMessage struct {
+	ID        string              `json:"id"`
+	PaymentID string              `json:"payment_id"`
+	OrderID   string              `json:"order_id"`
+	Amount    float64             `json:"amount"`
+	Items     []RefundItemMessage `json:"items,omitempty"`
+	Reason    string              `json:"reason,omitempty"`
+	CreatedAt string              `json:"created_at"`
+}
+
+type RefundItemMessage struct {
+	ProductID string  `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	Amount    float64 `json:"amount"`
+}
+
+// RefundStatusChangedMessage confirms a refund, Status is either "refunded" or "failed"
+type RefundStatusChangedMessage struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	OrderID   string `json:"order_id"`
+	Status    string `json:"status"`
+	UpdatedAt string `json:"updated_at"`
+}
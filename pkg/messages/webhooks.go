@@ -0,0 +1,19 @@
+package messages
+
+const (
+	WebhookEventHeader      = "X-Webhook-Event"
+	WebhookDeliveryIDHeader = "X-Webhook-Delivery"
+
+	OrderStatusChangedEvent = "order.status_changed"
+)
+
+// OrderWebhookMessage is the body of the outbound order webhooks, signed as described in helpers.SignPayload.
+// ID is the same on every retry of a delivery so receivers can drop duplicates.
+type OrderWebhookMessage struct {
+	ID             string `json:"id"`
+	Event          string `json:"event"`
+	OrderID        string `json:"order_id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	OccurredAt     string `json:"occurred_at"`
+}
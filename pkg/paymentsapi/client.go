@@ -0,0 +1,70 @@
+// Package paymentsapi talks to the msvc-payments HTTP API like its own api package does, but through an
+// http.Client with a timeout so a stuck call can't hold the caller forever
+package paymentsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SOAT1StackGoLang/msvc-payments/pkg/api"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected status code")
+
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string, timeout time.Duration) api.PaymentAPI {
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return &client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *client) CreatePayment(request api.CreatePaymentRequest) (api.CreatePaymentResponse, error) {
+	var out api.CreatePaymentResponse
+	err := c.do(http.MethodPost, request, &out)
+	return out, err
+}
+
+func (c *client) GetPayment(request api.GetPaymentRequest) (api.GetPaymentResponse, error) {
+	var out api.GetPaymentResponse
+	err := c.do(http.MethodGet, request, &out)
+	return out, err
+}
+
+// do sends request as the JSON body to /payments, the only resource msvc-payments exposes
+func (c *client) do(method string, request, out any) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/payments", c.baseURL), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ErrUnexpectedStatus
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,146 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{"empty", "", 0xFFFF},
+		{"check value", "123456789", 0x29B1},
+		{
+			"bcb static example",
+			"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304",
+			0x1D3D,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16(tt.data); got != tt.want {
+				t.Errorf("CRC16(%q) = %04X, want %04X", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerchantPayload(t *testing.T) {
+	merchant := Merchant{Key: "123e4567-e12b-12d1-a456-426655440000", Name: "Fulano de Tal", City: "BRASILIA"}
+
+	tests := []struct {
+		name     string
+		merchant Merchant
+		charge   Charge
+		contains []string
+		wantErr  error
+	}{
+		{
+			name:     "static code without amount",
+			merchant: merchant,
+			charge:   Charge{},
+			contains: []string{
+				"000201",
+				"010211",
+				"26580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000",
+				"52040000",
+				"5303986",
+				"5802BR",
+				"5913Fulano de Tal",
+				"6008BRASILIA",
+				"62070503***",
+			},
+		},
+		{
+			name:     "dynamic code with amount and txid",
+			merchant: merchant,
+			charge:   Charge{Amount: decimal.RequireFromString("10.5"), TxID: "abc123"},
+			contains: []string{"010212", "540510.50", "62100506abc123"},
+		},
+		{
+			name:     "accents and long names are normalized",
+			merchant: Merchant{Key: "chave", Name: "Lanchonete São João do Açaí Ltda", City: "São Paulo"},
+			charge:   Charge{},
+			contains: []string{"5925Lanchonete Sao Joao do Ac", "6009Sao Paulo"},
+		},
+		{
+			name:     "missing key",
+			merchant: Merchant{Name: "Fulano", City: "BRASILIA"},
+			wantErr:  ErrMissingKey,
+		},
+		{
+			name:     "key too long",
+			merchant: Merchant{Key: strings.Repeat("k", maxKey+1), Name: "Fulano", City: "BRASILIA"},
+			wantErr:  ErrKeyTooLong,
+		},
+		{
+			name:     "missing merchant",
+			merchant: Merchant{Key: "chave", Name: "  ", City: "BRASILIA"},
+			wantErr:  ErrMissingMerchant,
+		},
+		{
+			name:     "invalid txid",
+			merchant: merchant,
+			charge:   Charge{TxID: "not-alphanumeric"},
+			wantErr:  ErrInvalidTxID,
+		},
+		{
+			name:     "negative amount",
+			merchant: merchant,
+			charge:   Charge{Amount: decimal.NewFromInt(-1)},
+			wantErr:  ErrInvalidAmount,
+		},
+		{
+			name:     "amount too long",
+			merchant: merchant,
+			charge:   Charge{Amount: decimal.NewFromInt(10_000_000_000)},
+			wantErr:  ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.merchant.Payload(tt.charge)
+			if err != tt.wantErr {
+				t.Fatalf("Payload() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			for _, part := range tt.contains {
+				if !strings.Contains(got, part) {
+					t.Errorf("Payload() = %q, missing %q", got, part)
+				}
+			}
+
+			body, crc := got[:len(got)-4], got[len(got)-4:]
+			if !strings.HasSuffix(body, "6304") {
+				t.Errorf("Payload() = %q, CRC field is not last", got)
+			}
+			if want := fmt.Sprintf("%04X", CRC16(body)); crc != want {
+				t.Errorf("Payload() CRC = %s, want %s", crc, want)
+			}
+		})
+	}
+}
+
+func TestTxIDFromUUID(t *testing.T) {
+	id := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+
+	got := TxIDFromUUID(id)
+	if got != "a0eebc999c0b4ef8bb6d6bb9b" {
+		t.Errorf("TxIDFromUUID() = %q", got)
+	}
+	if !validTxID(got) {
+		t.Errorf("TxIDFromUUID() = %q is not a valid txid", got)
+	}
+}